@@ -72,6 +72,7 @@ func main() {
 			}
 
 			descriptors.InitModelJobController()
+			descriptors.InitModelConverterController()
 			descriptors.InitLogController()
 			descriptors.InitEventController()
 			descriptors.InitServingController()
@@ -25,6 +25,8 @@ type ServerOption struct {
 	MetricsAddr string
 
 	EnableLeaderElection bool
+
+	EnableWebhook bool
 }
 
 // NewServerOption creates a new CMServer with a default config.
@@ -42,4 +44,8 @@ func (s *ServerOption) AddFlags(fs *flag.FlagSet) {
 	flag.BoolVar(&s.EnableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+
+	flag.BoolVar(&s.EnableWebhook, "enable-webhook", false,
+		"Enable the validating and defaulting admission webhooks of ModelJob. "+
+			"The serving certificates must be mounted in /tmp/k8s-webhook-server/serving-certs.")
 }
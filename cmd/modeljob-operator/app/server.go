@@ -19,28 +19,37 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kleveross/klever-model-registry/cmd/modeljob-operator/app/options"
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobsv1beta1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1beta1"
 	"github.com/kleveross/klever-model-registry/pkg/controllers"
 	"github.com/kleveross/klever-model-registry/pkg/version"
 )
@@ -55,12 +64,25 @@ const (
 	CustomResourceDefinitionKind = "CustomResourceDefinition"
 	// CustomResourceDefinitionPath is the path of crd yaml.
 	CustomResourceDefinitionPath = "/crds"
+
+	// CustomResourceDefinitionSchemaHashAnnotationKey is the annotation of the hash of crd spec, the crd is
+	// updated if the hash of the spec in crd yaml is different.
+	CustomResourceDefinitionSchemaHashAnnotationKey = "kleveross.io/schema-hash"
+
+	// crdEstablishedInterval and crdEstablishedTimeout are the interval and timeout to wait for crds established.
+	crdEstablishedInterval = time.Second
+	crdEstablishedTimeout  = time.Minute
+
+	// storageVersionMigrationInterval is the interval to retry the storage version migration of modeljobs.
+	storageVersionMigrationInterval = 10 * time.Second
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(modeljobsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(modeljobsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -71,6 +93,18 @@ func Run(opt *options.ServerOption) error {
 	}
 
 	config := ctrl.GetConfigOrDie()
+
+	if err := controllers.Initialization(); err != nil {
+		setupLog.Error(err, "init error")
+		return err
+	}
+
+	// The crds are ensured before the manager is created, so that its resources are served when it starts.
+	if err := ensureCRD(config, opt.EnableWebhook); err != nil {
+		setupLog.Error(err, "unable to start install modeljob crd")
+		return err
+	}
+
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: opt.MetricsAddr,
@@ -83,16 +117,6 @@ func Run(opt *options.ServerOption) error {
 		return err
 	}
 
-	if err := ensureCRD(config); err != nil {
-		setupLog.Error(err, "unable to start install modeljob crd")
-		return err
-	}
-
-	if err := controllers.Initialization(); err != nil {
-		setupLog.Error(err, "init error")
-		return err
-	}
-
 	if err = (&controllers.ModelJobReconciler{
 		Client:        mgr.GetClient(),
 		Log:           ctrl.Log.WithName(controllers.ControllerName).WithName("ModelJob"),
@@ -102,6 +126,51 @@ func Run(opt *options.ServerOption) error {
 		setupLog.Error(err, "unable to create controller", "controller", "ModelJob")
 		return err
 	}
+	if err = (&controllers.ModelPipelineReconciler{
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName(controllers.ControllerName).WithName("ModelPipeline"),
+		EventRecorder: mgr.GetEventRecorderFor(controllers.ControllerName),
+		Scheme:        mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ModelPipeline")
+		return err
+	}
+	// model-registry renders the jobs of modeljobs by the operator, so that they are the same as the created ones.
+	if err := mgr.AddMetricsExtraHandler(controllers.ModelJobRenderPath, controllers.NewRenderHandler(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to add render handler")
+		return err
+	}
+	// The modelconverters of preset images are ensured after the cache of manager started.
+	err = mgr.Add(manager.RunnableFunc(func(<-chan struct{}) error {
+		if err := controllers.EnsurePresetModelConverters(mgr.GetClient()); err != nil {
+			setupLog.Error(err, "unable to ensure preset modelconverters")
+			return err
+		}
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to add preset modelconverters runnable")
+		return err
+	}
+
+	if opt.EnableWebhook {
+		controllers.SetupWebhookWithManager(mgr)
+
+		// The modeljobs stored in v1alpha1 are migrated to v1beta1 once the conversion webhook is serving.
+		err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+			return wait.PollImmediateUntil(storageVersionMigrationInterval, func() (bool, error) {
+				if err := controllers.MigrateModelJobStorageVersion(mgr.GetAPIReader(), mgr.GetClient()); err != nil {
+					setupLog.Error(err, "unable to migrate the storage version of modeljobs, will retry")
+					return false, nil
+				}
+				return true, nil
+			}, stop)
+		}))
+		if err != nil {
+			setupLog.Error(err, "unable to add storage version migration runnable")
+			return err
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -197,7 +266,9 @@ func readDocuments(fp string) ([][]byte, error) {
 	return docs, nil
 }
 
-func ensureCRD(config *rest.Config) error {
+// ensureCRD creates the crds, or updates them if the hash of their spec differs from the schema hash annotation,
+// e.g. when the operator is upgraded, then waits until they are established.
+func ensureCRD(config *rest.Config, enableWebhook bool) error {
 	client, err := clientset.NewForConfig(config)
 	if err != nil {
 		return err
@@ -207,14 +278,190 @@ func ensureCRD(config *rest.Config) error {
 		return err
 	}
 	for _, crd := range crds {
-		_, err = client.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), crd, metav1.CreateOptions{})
+		storedVersions, err := getStoredVersions(client, crd.Name)
 		if err != nil {
-			if !errors.IsAlreadyExists(err) {
-				setupLog.Error(err, "unable to ensure crds")
-				return err
-			}
+			return err
+		}
+		if err := controllers.SetModelJobCRDConversion(crd, storedVersions, enableWebhook); err != nil {
+			setupLog.Error(err, "unable to set the conversion of crd", "crd", crd.Name)
+			return err
+		}
+		hash, err := hashCRDSpec(&crd.Spec)
+		if err != nil {
+			return err
+		}
+		if crd.Annotations == nil {
+			crd.Annotations = map[string]string{}
+		}
+		crd.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey] = hash
+
+		if err := applyCRD(client, crd); err != nil {
+			setupLog.Error(err, "unable to ensure crds", "crd", crd.Name)
+			return err
+		}
+		if err := waitForCRDEstablished(client, crd.Name); err != nil {
+			setupLog.Error(err, "unable to wait for crd established", "crd", crd.Name)
+			return err
 		}
 	}
 
 	return nil
 }
+
+// getStoredVersions returns status.storedVersions of the installed crd, nil if it is not installed.
+func getStoredVersions(client clientset.Interface, name string) ([]string, error) {
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return crd.Status.StoredVersions, nil
+}
+
+// applyCRD creates the crd, or updates its spec and schema hash annotation if the hash changed.
+func applyCRD(client clientset.Interface, crd *apiextensionsv1.CustomResourceDefinition) error {
+	_, err := client.ApiextensionsV1().CustomResourceDefinitions().Create(context.TODO(), crd, metav1.CreateOptions{})
+	if err == nil {
+		setupLog.Info("crd created", "crd", crd.Name)
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	hash := crd.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey]
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crd.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey] == hash {
+			setupLog.Info("crd is up to date", "crd", crd.Name)
+			return nil
+		}
+
+		// The apiserver rejects the spec without the versions which the objects may be stored in.
+		if dropped := getDroppedStoredVersions(current, &crd.Spec); len(dropped) != 0 {
+			return fmt.Errorf("versions %v of crd %v are removed but still in status.storedVersions %v, "+
+				"the objects must be migrated to the storage version before removing them",
+				dropped, crd.Name, current.Status.StoredVersions)
+		}
+
+		changes := diffCRDSpec(&current.Spec, &crd.Spec)
+		current.Spec = crd.Spec
+		if current.Annotations == nil {
+			current.Annotations = map[string]string{}
+		}
+		current.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey] = hash
+		if _, err := client.ApiextensionsV1().CustomResourceDefinitions().Update(context.TODO(), current, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		setupLog.Info("crd updated", "crd", crd.Name, "changes", changes)
+		return nil
+	})
+}
+
+// getDroppedStoredVersions returns the stored versions of the crd which are not in the desired spec.
+func getDroppedStoredVersions(current *apiextensionsv1.CustomResourceDefinition, desired *apiextensionsv1.CustomResourceDefinitionSpec) []string {
+	versions := sets.NewString()
+	for _, version := range desired.Versions {
+		versions.Insert(version.Name)
+	}
+	dropped := []string{}
+	for _, version := range current.Status.StoredVersions {
+		if !versions.Has(version) {
+			dropped = append(dropped, version)
+		}
+	}
+	return dropped
+}
+
+// hashCRDSpec returns the sha256 of the spec of crd in JSON.
+func hashCRDSpec(spec *apiextensionsv1.CustomResourceDefinitionSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// diffCRDSpec describes the changes from the current spec of crd to the desired one, the versions, their schemas,
+// subresources and printer columns, the names and the conversion are compared.
+func diffCRDSpec(current, desired *apiextensionsv1.CustomResourceDefinitionSpec) []string {
+	changes := []string{}
+
+	currentVersions := map[string]apiextensionsv1.CustomResourceDefinitionVersion{}
+	for _, version := range current.Versions {
+		currentVersions[version.Name] = version
+	}
+	desiredVersions := sets.NewString()
+	for _, version := range desired.Versions {
+		desiredVersions.Insert(version.Name)
+		old, ok := currentVersions[version.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("version %v added", version.Name))
+			continue
+		}
+		if old.Served != version.Served {
+			changes = append(changes, fmt.Sprintf("version %v served: %v -> %v", version.Name, old.Served, version.Served))
+		}
+		if old.Storage != version.Storage {
+			changes = append(changes, fmt.Sprintf("version %v storage: %v -> %v", version.Name, old.Storage, version.Storage))
+		}
+		if !equality.Semantic.DeepEqual(old.Schema, version.Schema) {
+			changes = append(changes, fmt.Sprintf("schema of version %v changed", version.Name))
+		}
+		if !equality.Semantic.DeepEqual(old.Subresources, version.Subresources) {
+			changes = append(changes, fmt.Sprintf("subresources of version %v changed", version.Name))
+		}
+		if !equality.Semantic.DeepEqual(old.AdditionalPrinterColumns, version.AdditionalPrinterColumns) {
+			changes = append(changes, fmt.Sprintf("printer columns of version %v changed", version.Name))
+		}
+	}
+	for _, version := range current.Versions {
+		if !desiredVersions.Has(version.Name) {
+			changes = append(changes, fmt.Sprintf("version %v removed", version.Name))
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(current.Names, desired.Names) {
+		changes = append(changes, "names changed")
+	}
+	if !equality.Semantic.DeepEqual(normalizeCRDConversion(current.Conversion), normalizeCRDConversion(desired.Conversion)) {
+		changes = append(changes, "conversion changed")
+	}
+	return changes
+}
+
+// normalizeCRDConversion returns the conversion which the apiserver defaults to if it is not set.
+func normalizeCRDConversion(conversion *apiextensionsv1.CustomResourceConversion) *apiextensionsv1.CustomResourceConversion {
+	if conversion == nil {
+		return &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+	}
+	return conversion
+}
+
+// waitForCRDEstablished waits until the crd has the Established condition, so that its resources can be served.
+func waitForCRDEstablished(client clientset.Interface, name string) error {
+	return wait.PollImmediate(crdEstablishedInterval, crdEstablishedTimeout, func() (bool, error) {
+		crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range crd.Status.Conditions {
+			switch condition.Type {
+			case apiextensionsv1.Established:
+				if condition.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			case apiextensionsv1.NamesAccepted:
+				if condition.Status == apiextensionsv1.ConditionFalse {
+					return false, fmt.Errorf("names of crd %v are not accepted: %v", name, condition.Message)
+				}
+			}
+		}
+		return false, nil
+	})
+}
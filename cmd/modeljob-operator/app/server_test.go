@@ -0,0 +1,344 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestCRD(hash string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "modeljobs.kleveross.io",
+			Annotations: map[string]string{CustomResourceDefinitionSchemaHashAnnotationKey: hash},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "kleveross.io",
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "ModelJob", Plural: "modeljobs"},
+			Scope:    apiextensionsv1.NamespaceScoped,
+			Versions: versions,
+		},
+	}
+}
+
+func newTestCRDVersion(name string, served, storage bool, properties ...string) apiextensionsv1.CustomResourceDefinitionVersion {
+	schema := &apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{}}
+	for _, property := range properties {
+		schema.Properties[property] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+	}
+	return apiextensionsv1.CustomResourceDefinitionVersion{
+		Name:    name,
+		Served:  served,
+		Storage: storage,
+		Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: schema},
+	}
+}
+
+func Test_hashCRDSpec(t *testing.T) {
+	crd := newTestCRD("", newTestCRDVersion("v1alpha1", true, true, "model"))
+
+	hash, err := hashCRDSpec(&crd.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hash) != 64 {
+		t.Errorf("hashCRDSpec() = %v, want the hex of sha256", hash)
+	}
+
+	tests := []struct {
+		name     string
+		update   func(crd *apiextensionsv1.CustomResourceDefinition)
+		wantSame bool
+	}{
+		{
+			name:     "same spec",
+			update:   func(crd *apiextensionsv1.CustomResourceDefinition) {},
+			wantSame: true,
+		},
+		{
+			name: "annotations changed",
+			update: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey] = hash
+			},
+			wantSame: true,
+		},
+		{
+			name: "schema changed",
+			update: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[0] = newTestCRDVersion("v1alpha1", true, true, "model", "format")
+			},
+		},
+		{
+			name: "version added",
+			update: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions = append(crd.Spec.Versions, newTestCRDVersion("v1beta1", false, false))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := crd.DeepCopy()
+			tt.update(desired)
+			got, err := hashCRDSpec(&desired.Spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == hash) != tt.wantSame {
+				t.Errorf("hashCRDSpec() = %v, hash of the original spec %v, want same %v", got, hash, tt.wantSame)
+			}
+		})
+	}
+}
+
+func Test_diffCRDSpec(t *testing.T) {
+	alpha := newTestCRDVersion("v1alpha1", true, true, "model")
+	beta := newTestCRDVersion("v1beta1", false, false, "model")
+
+	tests := []struct {
+		name    string
+		current *apiextensionsv1.CustomResourceDefinition
+		desired *apiextensionsv1.CustomResourceDefinition
+		want    []string
+	}{
+		{
+			name:    "not changed",
+			current: newTestCRD("", alpha),
+			desired: newTestCRD("", alpha),
+			want:    []string{},
+		},
+		{
+			name:    "version added",
+			current: newTestCRD("", alpha),
+			desired: newTestCRD("", alpha, beta),
+			want:    []string{"version v1beta1 added"},
+		},
+		{
+			name:    "version removed",
+			current: newTestCRD("", alpha, beta),
+			desired: newTestCRD("", alpha),
+			want:    []string{"version v1beta1 removed"},
+		},
+		{
+			name:    "storage flipped",
+			current: newTestCRD("", alpha, beta),
+			desired: newTestCRD("", newTestCRDVersion("v1alpha1", true, false, "model"), newTestCRDVersion("v1beta1", true, true, "model")),
+			want: []string{
+				"version v1alpha1 storage: true -> false",
+				"version v1beta1 served: false -> true",
+				"version v1beta1 storage: false -> true",
+			},
+		},
+		{
+			name:    "schema changed",
+			current: newTestCRD("", alpha),
+			desired: newTestCRD("", newTestCRDVersion("v1alpha1", true, true, "model", "format")),
+			want:    []string{"schema of version v1alpha1 changed"},
+		},
+		{
+			name:    "default conversion",
+			current: newTestCRD("", alpha),
+			desired: func() *apiextensionsv1.CustomResourceDefinition {
+				crd := newTestCRD("", alpha)
+				crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+				return crd
+			}(),
+			want: []string{},
+		},
+		{
+			name:    "webhook conversion",
+			current: newTestCRD("", alpha),
+			desired: func() *apiextensionsv1.CustomResourceDefinition {
+				crd := newTestCRD("", alpha)
+				crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.WebhookConverter}
+				return crd
+			}(),
+			want: []string{"conversion changed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffCRDSpec(&tt.current.Spec, &tt.desired.Spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffCRDSpec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyCRD(t *testing.T) {
+	alpha := newTestCRDVersion("v1alpha1", true, true, "model")
+	beta := newTestCRDVersion("v1beta1", true, false, "model")
+	withStoredVersions := func(crd *apiextensionsv1.CustomResourceDefinition, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd.Status.StoredVersions = storedVersions
+		return crd
+	}
+
+	tests := []struct {
+		name        string
+		existing    []runtime.Object
+		desired     *apiextensionsv1.CustomResourceDefinition
+		wantVerbs   []string
+		wantHash    string
+		wantErr     bool
+		wantVersion int
+	}{
+		{
+			name:        "created",
+			desired:     newTestCRD("new", alpha),
+			wantVerbs:   []string{"create"},
+			wantHash:    "new",
+			wantVersion: 1,
+		},
+		{
+			name:        "up to date",
+			existing:    []runtime.Object{newTestCRD("old", alpha, beta)},
+			desired:     newTestCRD("old", alpha),
+			wantVerbs:   []string{"create", "get"},
+			wantHash:    "old",
+			wantVersion: 2,
+		},
+		{
+			name:        "updated",
+			existing:    []runtime.Object{withStoredVersions(newTestCRD("old", alpha, beta), "v1alpha1")},
+			desired:     newTestCRD("new", alpha),
+			wantVerbs:   []string{"create", "get", "update"},
+			wantHash:    "new",
+			wantVersion: 1,
+		},
+		{
+			name:        "stored version removed",
+			existing:    []runtime.Object{withStoredVersions(newTestCRD("old", alpha, beta), "v1alpha1", "v1beta1")},
+			desired:     newTestCRD("new", alpha),
+			wantVerbs:   []string{"create", "get"},
+			wantHash:    "old",
+			wantErr:     true,
+			wantVersion: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.existing...)
+
+			if err := applyCRD(client, tt.desired); (err != nil) != tt.wantErr {
+				t.Fatalf("applyCRD() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			verbs := []string{}
+			for _, action := range client.Actions() {
+				verbs = append(verbs, action.GetVerb())
+			}
+			if !reflect.DeepEqual(verbs, tt.wantVerbs) {
+				t.Errorf("applyCRD() actions = %v, want %v", verbs, tt.wantVerbs)
+			}
+			crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), tt.desired.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash := crd.Annotations[CustomResourceDefinitionSchemaHashAnnotationKey]; hash != tt.wantHash {
+				t.Errorf("applyCRD() schema hash = %v, want %v", hash, tt.wantHash)
+			}
+			if len(crd.Spec.Versions) != tt.wantVersion {
+				t.Errorf("applyCRD() versions = %v, want %v versions", crd.Spec.Versions, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func Test_getDroppedStoredVersions(t *testing.T) {
+	current := newTestCRD("", newTestCRDVersion("v1alpha1", true, false), newTestCRDVersion("v1beta1", true, true))
+	current.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+
+	tests := []struct {
+		name    string
+		desired *apiextensionsv1.CustomResourceDefinition
+		want    []string
+	}{
+		{
+			name:    "all stored versions kept",
+			desired: newTestCRD("", newTestCRDVersion("v1alpha1", false, false), newTestCRDVersion("v1beta1", true, true)),
+			want:    []string{},
+		},
+		{
+			name:    "stored version removed",
+			desired: newTestCRD("", newTestCRDVersion("v1beta1", true, true)),
+			want:    []string{"v1alpha1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDroppedStoredVersions(current, &tt.desired.Spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getDroppedStoredVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_waitForCRDEstablished(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []apiextensionsv1.CustomResourceDefinitionCondition
+		wantErr    bool
+	}{
+		{
+			name: "established",
+			conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+			},
+		},
+		{
+			name: "names not accepted",
+			conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionFalse, Message: "conflicts"},
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionFalse},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := newTestCRD("")
+			crd.Status.Conditions = tt.conditions
+			client := fake.NewSimpleClientset(crd)
+
+			if err := waitForCRDEstablished(client, crd.Name); (err != nil) != tt.wantErr {
+				t.Errorf("waitForCRDEstablished() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_getStoredVersions(t *testing.T) {
+	crd := newTestCRD("", newTestCRDVersion("v1alpha1", true, false), newTestCRDVersion("v1beta1", true, true))
+	crd.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+
+	tests := []struct {
+		name     string
+		existing []runtime.Object
+		want     []string
+	}{
+		{
+			name: "not installed",
+		},
+		{
+			name:     "installed",
+			existing: []runtime.Object{crd},
+			want:     []string{"v1alpha1", "v1beta1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getStoredVersions(fake.NewSimpleClientset(tt.existing...), crd.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getStoredVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ require (
 	github.com/nwaples/rardecode v1.1.0 // indirect
 	github.com/onsi/ginkgo v1.14.1
 	github.com/onsi/gomega v1.10.2
+	github.com/prometheus/client_golang v1.7.1
 	github.com/seldonio/seldon-core/operator v1.5.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/viper v1.7.0
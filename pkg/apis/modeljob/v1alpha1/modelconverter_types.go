@@ -0,0 +1,94 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceFormats"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetFormats"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelConverter is the Schema for the modelconverters API, it declares an executor
+// of model extraction or conversion which can be run by modeljobs.
+type ModelConverter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ModelConverterSpec `json:"spec,omitempty"`
+}
+
+// ModelConverterType is the type of modeljob which the converter runs.
+type ModelConverterType string
+
+const (
+	// ModelConverterTypeExtraction means the converter extracts the metadata of model.
+	ModelConverterTypeExtraction ModelConverterType = "Extraction"
+	// ModelConverterTypeConversion means the converter converts model to another format.
+	ModelConverterTypeConversion ModelConverterType = "Conversion"
+	// ModelConverterTypeValidation means the converter validates model by the inference with sample inputs.
+	ModelConverterTypeValidation ModelConverterType = "Validation"
+	// ModelConverterTypeOptimization means the converter optimizes model in the same format.
+	ModelConverterTypeOptimization ModelConverterType = "Optimization"
+	// ModelConverterTypeBenchmark means the converter benchmarks model in its serving runtime.
+	ModelConverterTypeBenchmark ModelConverterType = "Benchmark"
+)
+
+// ModelConverterSpec defines the executor of ModelConverter
+type ModelConverterSpec struct {
+	// Type is the type of modeljob which the converter runs, Extraction, Conversion, Validation, Optimization
+	// or Benchmark.
+	// +kubebuilder:validation:Enum=Extraction;Conversion;Validation;Optimization;Benchmark
+	Type ModelConverterType `json:"type"`
+
+	// SourceFormats is the formats of model which the converter accepts.
+	// +kubebuilder:validation:MinItems=1
+	SourceFormats []Format `json:"sourceFormats"`
+
+	// TargetFormats is the formats which the converter converts model to, it is ignored for the types except conversion.
+	// All target formats are accepted if it is empty.
+	TargetFormats []Format `json:"targetFormats,omitempty"`
+
+	// Image is the image of executor container.
+	Image string `json:"image"`
+
+	// Resources is the default resources of executor container.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env defines the extra env for executor container.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelConverterList contains a list of ModelConverter
+type ModelConverterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ModelConverter `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ModelConverter{}, &ModelConverterList{})
+}
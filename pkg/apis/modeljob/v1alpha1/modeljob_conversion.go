@@ -0,0 +1,4 @@
+package v1alpha1
+
+// Hub marks ModelJob v1alpha1 as the hub of conversion, the other versions are converted to and from it.
+func (*ModelJob) Hub() {}
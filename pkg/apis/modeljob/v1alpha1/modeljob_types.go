@@ -16,6 +16,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,15 +42,90 @@ const (
 	DestinationModelTagEnvKey = "DESTINATION_MODEL_TAG"
 	// ExtractorEnvKey is extractor env key
 	ExtractorEnvKey = "EXTRACTOR"
+	// ResultPathEnvKey is the env key of the path where executor writes the json of ModelJobResult
+	ResultPathEnvKey = "MODELJOB_RESULT_PATH"
+	// TaskEnvKey is the env key of the task of executor, eg: extract, convert or validate.
+	TaskEnvKey = "MODELJOB_TASK"
+	// StagePathEnvKey is the env key of the path where executor writes the stage it is running, eg: task or push.
+	StagePathEnvKey = "MODELJOB_STAGE_PATH"
+	// ValidationSampleInputsEnvKey is the env key of the path of sample inputs in model for validation.
+	ValidationSampleInputsEnvKey = "VALIDATION_SAMPLE_INPUTS"
+	// ValidationExpectedOutputsEnvKey is the env key of the path of expected outputs in model for validation.
+	ValidationExpectedOutputsEnvKey = "VALIDATION_EXPECTED_OUTPUTS"
+	// ValidationToleranceEnvKey is the env key of the tolerance of outputs for validation.
+	ValidationToleranceEnvKey = "VALIDATION_TOLERANCE"
+	// OptimizationsEnvKey is the env key of the optimizations separated by comma, eg: Simplify,DynamicQuantization.
+	OptimizationsEnvKey = "OPTIMIZATIONS"
+	// TargetOpsetEnvKey is the env key of the ONNX opset which the model is upgraded to.
+	TargetOpsetEnvKey = "TARGET_OPSET"
+	// BenchmarkBatchSizesEnvKey is the env key of the batch sizes of benchmark separated by comma.
+	BenchmarkBatchSizesEnvKey = "BENCHMARK_BATCH_SIZES"
+	// BenchmarkConcurrencyEnvKey is the env key of the concurrency of benchmark separated by comma.
+	BenchmarkConcurrencyEnvKey = "BENCHMARK_CONCURRENCY"
+	// BenchmarkRequestsEnvKey is the env key of the number of requests of each case of benchmark.
+	BenchmarkRequestsEnvKey = "BENCHMARK_REQUESTS"
+	// BenchmarkWarmupRequestsEnvKey is the env key of the number of requests before each case of benchmark.
+	BenchmarkWarmupRequestsEnvKey = "BENCHMARK_WARMUP_REQUESTS"
 
 	// SourceModelPath is path of ormb pull
 	SourceModelPath = "/models/input"
 	// DestinationModelPath if the path of convert's destination
 	DestinationModelPath = "/models/output"
+	// StagePath is the path where executor writes the stage it is running.
+	StagePath = "/tmp/modeljob-stage"
+
+	// ExtractLabelKey is the label key of extraction modeljob.
+	ExtractLabelKey = "modeljob/extract"
+	// ConvertLabelKey is the label key of conversion modeljob.
+	ConvertLabelKey = "modeljob/convert"
+	// ValidateLabelKey is the label key of validation modeljob.
+	ValidateLabelKey = "modeljob/validate"
+	// OptimizeLabelKey is the label key of optimization modeljob.
+	OptimizeLabelKey = "modeljob/optimize"
+	// S3AccessKeyIDKey is the key of access key ID in the credentials Secret of S3 model source.
+	S3AccessKeyIDKey = "accessKeyID"
+	// S3SecretAccessKeyKey is the key of secret access key in the credentials Secret of S3 model source.
+	S3SecretAccessKeyKey = "secretAccessKey"
+
+	// BenchmarkLabelKey is the label key of benchmark modeljob.
+	BenchmarkLabelKey = "modeljob/benchmark"
+	// BenchmarkAnnotationKey is the annotation key of Harbor artifact which records the result of benchmark as JSON.
+	BenchmarkAnnotationKey = "kleveross.io/benchmark"
+	// ValidationAnnotationKey is the annotation key of Harbor artifact which records the result of validation,
+	// the value is passed or failed.
+	ValidationAnnotationKey = "kleveross.io/validation"
+	// ModelJobCancelAnnotationKey is the annotation key to cancel the modeljob, the value is "true".
+	ModelJobCancelAnnotationKey = "modeljob.kleveross.io/cancel"
+	// ModelJobNameLabelKey is the label key of the job and pods of modeljob, the value is the name of modeljob.
+	ModelJobNameLabelKey = "modeljob.kleveross.io/name"
+	// ModelJobProjectLabelKey is the label key of the extraction modeljob created for the pushed model, the value is
+	// the name of Harbor project.
+	ModelJobProjectLabelKey = "modeljob.kleveross.io/project"
+	// ModelJobModelLabelKey is the label key of the extraction modeljob created for the pushed model, the value is
+	// the name of model.
+	ModelJobModelLabelKey = "modeljob.kleveross.io/model"
+	// ModelJobVersionLabelKey is the label key of the extraction modeljob created for the pushed model, the value is
+	// the version of model.
+	ModelJobVersionLabelKey = "modeljob.kleveross.io/version"
+	// ModelJobDigestLabelKey is the label key of the extraction modeljob created for the pushed model, the value is
+	// the hex of the artifact digest truncated to 63 characters, and the full digest is in the annotation of the same key.
+	ModelJobDigestLabelKey = "modeljob.kleveross.io/digest"
+	// ModelJobResultDigestLabelKey is the label key of the succeeded extraction modeljob, the value is the hex of
+	// status.result.digest truncated to 63 characters.
+	ModelJobResultDigestLabelKey = "modeljob.kleveross.io/result-digest"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.model",priority=1
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",priority=1
+// +kubebuilder:printcolumn:name="Queue",type="integer",JSONPath=".status.queuePosition",priority=1
+// +kubebuilder:printcolumn:name="Started",type="date",JSONPath=".status.startTime"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -70,29 +146,285 @@ type ModelJobSpec struct {
 	// Model is model ref, eg: kleveross/resnet:v1.
 	Model string `json:"model,omitempty"`
 
+	// Source is the location of the model out of the model registry, the files are staged to /models/input
+	// instead of pulling Model, and the extraction pushes them to Model as a new version.
+	// It may only be set for extraction and conversion.
+	Source *ModelSource `json:"source,omitempty"`
+
 	// DesiredTag is the target tag of model convert.
 	DesiredTag *string `json:"desiredTag,omitempty"`
 
 	// Env defines the env for modeljob.
+	// The MODELJOB_TASK_CPU, MODELJOB_TASK_MEM, MODEL_INITIALIZER_CPU and MODEL_INITIALIZER_MEM env are deprecated,
+	// use Resources and InitializerResources instead.
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// Executor overrides the image, command and args of the executor container instead of the modelconverter
+	// or the preset image of operator, the image must match the executor image allowlist of operator.
+	Executor *Executor `json:"executor,omitempty"`
+
+	// Resources is the resources of the executor container. The resources of modelconverter are used if it is not set.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// InitializerResources is the resources of the model initializer container.
+	InitializerResources *corev1.ResourceRequirements `json:"initializerResources,omitempty"`
+
+	// NodeSelector is the node selector of the pod of job.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations is the tolerations of the pod of job.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity is the scheduling constraints of the pod of job.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the priority class of the pod of job.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ServiceAccountName is the service account to run the pod of job.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Workspace is the volume which the model is pulled to and shared by the init containers and the executor,
+	// it is an emptyDir without size limit if it is not set.
+	Workspace *Workspace `json:"workspace,omitempty"`
+
 	// InitContainer is the init container, we can use it to pull model by custome.
 	InitContainer []corev1.Container `json:"initContainer,omitempty"`
 
+	// RetryPolicy defines how to retry the modeljob when its job failed, the modeljob is not retried if it is nil.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of a modeljob that has finished,
+	// the modeljob and its job will be deleted after the ttl expired.
+	// The operator-wide default is used if it is not set.
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds relative to the start time that
+	// the job of modeljob may be active before it is failed with Timeout reason.
+	// The operator-wide default of the format is used if it is not set.
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Priority is the priority of modeljob in the queue of operator, the modeljobs with higher priority
+	// start first when the concurrent jobs are limited. It defaults to 0.
+	Priority *int32 `json:"priority,omitempty"`
+
+	// Suspend tells the operator to stop the job of modeljob, the modeljob is Suspended until it is set to false,
+	// then the job is created again from the beginning.
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// RegistryCredentials references the Secret of the credentials to pull and push model,
+	// the account of operator is used only if it is not set and the fallback of operator is enabled.
+	RegistryCredentials *RegistryCredentials `json:"registryCredentials,omitempty"`
+
 	// ModelJobSource is model job source.
 	ModelJobSource `json:",inline"`
 }
 
+// RegistryCredentials defines the Secret of registry credentials in the namespace of modeljob.
+type RegistryCredentials struct {
+	// SecretName is the name of Secret, its type is kubernetes.io/dockerconfigjson or kubernetes.io/basic-auth.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// ModelSource defines the location of model out of the model registry, only one of its members may be set.
+// The files are staged as they are if there is ormbfile.yaml in them, otherwise they are moved to the model
+// directory and ormbfile.yaml is generated with the format of modeljob. The archive of .tar, .tar.gz, .tgz
+// or .zip is extracted.
+type ModelSource struct {
+	// S3 is an object or prefix in the bucket of an S3-compatible service, e.g. MinIO.
+	S3 *S3ModelSource `json:"s3,omitempty"`
+
+	// HTTP is a file downloaded from the HTTP(S) URL.
+	HTTP *HTTPModelSource `json:"http,omitempty"`
+
+	// PersistentVolumeClaim is a file or directory in an existing PVC in the namespace of modeljob.
+	PersistentVolumeClaim *PersistentVolumeClaimModelSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+// S3ModelSource defines the model in the bucket of an S3-compatible service.
+type S3ModelSource struct {
+	// Endpoint is the URL of the service, e.g. http://minio.minio-system:9000, AWS S3 is used if it is not set.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the region of bucket.
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the name of bucket.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Key is the key of object, or the prefix of objects if it ends with "/".
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// CredentialsSecretName is the Secret in the namespace of modeljob which has the accessKeyID and
+	// secretAccessKey keys, the objects are downloaded anonymously if it is not set.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// HTTPModelSource defines the model downloaded from the HTTP(S) URL.
+type HTTPModelSource struct {
+	// URL is the HTTP(S) URL of file.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Checksum is the checksum of file in the form of <algorithm>:<hex>, the algorithm is sha256 or sha512.
+	// +kubebuilder:validation:MinLength=1
+	Checksum string `json:"checksum"`
+}
+
+// PersistentVolumeClaimModelSource defines the model in an existing PVC.
+type PersistentVolumeClaimModelSource struct {
+	// ClaimName is the name of PVC.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Path is the relative path of the file or directory in PVC, it is the root of PVC if it is not set.
+	Path string `json:"path,omitempty"`
+}
+
+// Executor defines the executor container of modeljob.
+type Executor struct {
+	// Image is the image of executor, it runs the task with the same env and volumes as the preset image.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command overrides the entrypoint of image.
+	Command []string `json:"command,omitempty"`
+
+	// Args overrides the cmd of image.
+	Args []string `json:"args,omitempty"`
+}
+
+// Workspace defines the volume of modeljob, only one of its members may be set.
+type Workspace struct {
+	// EmptyDir is an emptyDir on the node of pod, the pod is evicted when the model exceeds its size limit.
+	EmptyDir *EmptyDirWorkspace `json:"emptyDir,omitempty"`
+
+	// Ephemeral is a generic ephemeral volume, the PVC is created with the pod and deleted with it.
+	// It requires the GenericEphemeralVolume feature of Kubernetes.
+	Ephemeral *EphemeralWorkspace `json:"ephemeral,omitempty"`
+
+	// PersistentVolumeClaim is an existing PVC in the namespace of modeljob, it is not cleaned after the modeljob finished.
+	PersistentVolumeClaim *PersistentVolumeClaimWorkspace `json:"persistentVolumeClaim,omitempty"`
+}
+
+// EmptyDirWorkspace defines the emptyDir workspace.
+type EmptyDirWorkspace struct {
+	// SizeLimit is the total amount of local storage of the emptyDir.
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// EphemeralWorkspace defines the PVC of the generic ephemeral workspace.
+type EphemeralWorkspace struct {
+	// StorageClassName is the storage class of PVC, the default storage class is used if it is not set.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the requested storage of PVC.
+	Size resource.Quantity `json:"size"`
+}
+
+// PersistentVolumeClaimWorkspace defines the existing PVC workspace.
+type PersistentVolumeClaimWorkspace struct {
+	// ClaimName is the name of PVC.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// SubPath is the directory in the PVC which the model is pulled to, it defaults to the name of modeljob
+	// so that the modeljobs can share the PVC.
+	SubPath string `json:"subPath,omitempty"`
+}
+
 // ModelJobSource defines the modeljob source information
 type ModelJobSource struct {
 	Extraction *ExtractionSource `json:"extraction,omitempty"`
 	Conversion *ConversionSource `json:"conversion,omitempty"`
+	Validation *ValidationSource `json:"validation,omitempty"`
+	// Optimization optimizes the model and pushes it to DesiredTag in the same format.
+	Optimization *OptimizationSource `json:"optimization,omitempty"`
+	// Benchmark measures the latency, throughput and memory of the model in its serving runtime.
+	Benchmark *BenchmarkSource `json:"benchmark,omitempty"`
 }
 
 type ExtractionSource struct {
 	Format Format `json:"format,omitempty"`
 }
 
+// ValidationSource validates that the model can be loaded and gives sane outputs by the inference with sample inputs.
+type ValidationSource struct {
+	// Format is the format of model.
+	Format Format `json:"format,omitempty"`
+
+	// SampleInputs is the path of the JSON file of sample inputs in the model version, eg: samples/inputs.json,
+	// it maps the name of input tensors to their values. The inputs are generated from the signature of
+	// ormbfile.yaml if it is not set.
+	SampleInputs string `json:"sampleInputs,omitempty"`
+
+	// ExpectedOutputs is the path of the JSON file of expected outputs in the model version, it maps the name
+	// of output tensors to their values. The outputs are only checked to be finite if it is not set.
+	ExpectedOutputs string `json:"expectedOutputs,omitempty"`
+
+	// Tolerance is the max absolute difference between the outputs and expected outputs, eg: "1e-4", defaults to "1e-5".
+	Tolerance string `json:"tolerance,omitempty"`
+}
+
+// OptimizationType is the optimization applied to model.
+type OptimizationType string
+
+const (
+	// OptimizationUpgradeOpset upgrades the opset of ONNX model to TargetOpset.
+	OptimizationUpgradeOpset OptimizationType = "UpgradeOpset"
+	// OptimizationSimplify simplifies the graph of ONNX model, eg: folds the constants and removes the redundant nodes.
+	OptimizationSimplify OptimizationType = "Simplify"
+	// OptimizationDynamicQuantization quantizes the weights of ONNX model to INT8, and the activations
+	// are quantized dynamically in the inference.
+	OptimizationDynamicQuantization OptimizationType = "DynamicQuantization"
+	// OptimizationConstantFolding freezes the variables of SavedModel and folds the constants of its graph.
+	OptimizationConstantFolding OptimizationType = "ConstantFolding"
+)
+
+// OptimizationSource optimizes the model for CPU inference, the optimizations which run on CPU are supported.
+type OptimizationSource struct {
+	// Format is the format of model, ONNX and SavedModel are supported.
+	Format Format `json:"format,omitempty"`
+
+	// Optimizations is the optimizations applied to model, UpgradeOpset, Simplify and DynamicQuantization
+	// for ONNX, ConstantFolding for SavedModel. They are applied in the order of UpgradeOpset, Simplify,
+	// DynamicQuantization whatever the order here is.
+	// +kubebuilder:validation:MinItems=1
+	Optimizations []OptimizationType `json:"optimizations"`
+
+	// TargetOpset is the ONNX opset which the model is upgraded to, it must be set for UpgradeOpset.
+	// +optional
+	TargetOpset *int32 `json:"targetOpset,omitempty"`
+}
+
+// BenchmarkSource benchmarks the model on CPU in the serving runtime image of its format by the synthetic
+// requests shaped by the signature of ormbfile.yaml, each pair of batch size and concurrency is a case.
+type BenchmarkSource struct {
+	// Format is the format of model.
+	Format Format `json:"format,omitempty"`
+
+	// BatchSizes is the batch sizes of requests, defaults to [1].
+	// +optional
+	BatchSizes []int32 `json:"batchSizes,omitempty"`
+
+	// Concurrency is the numbers of concurrent requests, defaults to [1].
+	// +optional
+	Concurrency []int32 `json:"concurrency,omitempty"`
+
+	// Requests is the number of requests of each case, defaults to 100.
+	// +optional
+	Requests *int32 `json:"requests,omitempty"`
+
+	// WarmupRequests is the number of requests sent before each case which are not measured, defaults to 10.
+	// +optional
+	WarmupRequests *int32 `json:"warmupRequests,omitempty"`
+}
+
 type ConversionSource struct {
 	MMdnn *MMdnnSpec `json:"mmdnn,omitempty"`
 }
@@ -106,6 +438,36 @@ type ConversionBaseSpec struct {
 	To   Format `json:"to,omitempty"`
 }
 
+// RetryPolicy defines the retry policy of modeljob.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts, including the first one, defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// Backoff is the exponential backoff between two attempts.
+	Backoff *RetryBackoff `json:"backoff,omitempty"`
+
+	// RetryableExitCodes is the exit codes of executor which can be retried,
+	// defaults to the exit codes of ormb login, pull and push errors.
+	RetryableExitCodes []int32 `json:"retryableExitCodes,omitempty"`
+}
+
+// RetryBackoff defines the exponential backoff between two attempts,
+// the delay is InitialDelaySeconds * Factor^(attempt-1) and no more than MaxDelaySeconds.
+type RetryBackoff struct {
+	// InitialDelaySeconds is the delay before the second attempt, defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Factor is the multiplier of delay for each attempt, defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	Factor int32 `json:"factor,omitempty"`
+
+	// MaxDelaySeconds is the max delay between two attempts, defaults to 300.
+	// +kubebuilder:validation:Minimum=0
+	MaxDelaySeconds int32 `json:"maxDelaySeconds,omitempty"`
+}
+
 // Framework is model framework, eg: TensorFlow.
 type Framework string
 
@@ -151,6 +513,12 @@ const (
 	ModelJobDeleting  ModelJobPhase = "Deleting"
 	ModelJobSucceeded ModelJobPhase = "Succeeded"
 	ModelJobFailed    ModelJobPhase = "Failed"
+	// ModelJobQueued means the modeljob is waiting in the queue of operator for the limits of concurrent jobs.
+	ModelJobQueued ModelJobPhase = "Queued"
+	// ModelJobSuspended means the job of modeljob is stopped by spec.suspend, it runs again when resumed.
+	ModelJobSuspended ModelJobPhase = "Suspended"
+	// ModelJobCancelled means the job of modeljob is stopped by the cancel annotation, it never runs again.
+	ModelJobCancelled ModelJobPhase = "Cancelled"
 )
 
 // ModelJobStatus defines the observed state of ModelJob
@@ -163,6 +531,196 @@ type ModelJobStatus struct {
 
 	// Human readable message indicating the reason for Failure
 	Message string `json:"message"`
+
+	// A brief CamelCase message indicating details about why the modeljob is in this phase, eg: Timeout.
+	Reason string `json:"reason,omitempty"`
+
+	// StartTime is the time when the job of modeljob was created.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the modeljob was finished, no matter it succeeded or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the modeljob-operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions is the latest available observations of the modeljob's stages.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []ModelJobCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Attempts is the number of jobs which have been created for the modeljob.
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// FailedAttempts records the failure of each failed attempt.
+	FailedAttempts []ModelJobAttempt `json:"failedAttempts,omitempty"`
+
+	// QueuePosition is the position of modeljob in the queue of operator when it is Queued, it starts from 1.
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+
+	// Result is the result reported by the executor of the latest attempt.
+	Result *ModelJobResult `json:"result,omitempty"`
+}
+
+// ModelJobResult is the result which the executor writes to its termination message.
+type ModelJobResult struct {
+	// Signature is the signature of model extracted by the executor.
+	Signature *ModelSignature `json:"signature,omitempty"`
+
+	// DestinationRef is the ref of model pushed by the executor.
+	DestinationRef string `json:"destinationRef,omitempty"`
+
+	// Digest is the digest of model pushed by the executor, eg: sha256:xxx.
+	Digest string `json:"digest,omitempty"`
+
+	// Size is the size of model in bytes.
+	Size int64 `json:"size,omitempty"`
+
+	// Error is the detailed error of the executor.
+	Error string `json:"error,omitempty"`
+
+	// Truncated is true if the signature is dropped because the result exceeds the size limit of termination message.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// TaskDuration is how long the executor ran the extraction or conversion task.
+	TaskDuration *metav1.Duration `json:"taskDuration,omitempty"`
+
+	// PushDuration is how long the executor saved and pushed the model.
+	PushDuration *metav1.Duration `json:"pushDuration,omitempty"`
+
+	// Validation is the result of model validation.
+	Validation *ModelValidationResult `json:"validation,omitempty"`
+
+	// Optimization is the result of model optimization.
+	Optimization *ModelOptimizationResult `json:"optimization,omitempty"`
+
+	// Benchmark is the result of model benchmark.
+	Benchmark *ModelBenchmarkResult `json:"benchmark,omitempty"`
+}
+
+// ModelBenchmarkResult is the latency and throughput of each case of benchmark and the memory of serving runtime.
+type ModelBenchmarkResult struct {
+	// Cases is the results of each pair of batch size and concurrency.
+	Cases []BenchmarkCaseResult `json:"cases,omitempty"`
+
+	// PeakMemoryBytes is the peak resident memory of the serving runtime processes during the benchmark.
+	PeakMemoryBytes int64 `json:"peakMemoryBytes,omitempty"`
+
+	// Annotated is true if the result is recorded in the annotation of Harbor artifact.
+	Annotated bool `json:"annotated,omitempty"`
+}
+
+// BenchmarkCaseResult is the result of a case of benchmark.
+type BenchmarkCaseResult struct {
+	BatchSize   int32 `json:"batchSize"`
+	Concurrency int32 `json:"concurrency"`
+
+	// LatencyP50, LatencyP95 and LatencyP99 are the percentiles of the latency of requests.
+	LatencyP50 *metav1.Duration `json:"latencyP50,omitempty"`
+	LatencyP95 *metav1.Duration `json:"latencyP95,omitempty"`
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// Throughput is the number of inferred samples per second, eg: "123.4".
+	Throughput string `json:"throughput,omitempty"`
+
+	// Errors is the number of failed requests.
+	Errors int32 `json:"errors,omitempty"`
+}
+
+// ModelOptimizationResult is the optimizations applied to model and the sizes of model.
+type ModelOptimizationResult struct {
+	// Optimizations is the optimizations applied to model in order.
+	Optimizations []OptimizationType `json:"optimizations,omitempty"`
+
+	// OriginalSize is the bytes of model files before the optimization.
+	OriginalSize int64 `json:"originalSize,omitempty"`
+
+	// OptimizedSize is the bytes of model files after the optimization.
+	OptimizedSize int64 `json:"optimizedSize,omitempty"`
+}
+
+// ModelValidationResult is the result of the inference with sample inputs.
+type ModelValidationResult struct {
+	// Passed is true if the model is loaded and its outputs are within the tolerance of the expected outputs.
+	Passed bool `json:"passed"`
+
+	// InputsGenerated is true if the sample inputs are generated from the signature of model.
+	InputsGenerated bool `json:"inputsGenerated,omitempty"`
+
+	// MaxAbsoluteError is the max absolute difference between the outputs and expected outputs.
+	MaxAbsoluteError string `json:"maxAbsoluteError,omitempty"`
+
+	// Message is the reason why the validation failed.
+	Message string `json:"message,omitempty"`
+
+	// Annotated is true if the result is recorded in the annotation of Harbor artifact.
+	Annotated bool `json:"annotated,omitempty"`
+}
+
+// ModelSignature is the signature of model, it is the same as the signature of ormbfile.yaml.
+type ModelSignature struct {
+	Inputs  []ModelTensor `json:"inputs,omitempty"`
+	Outputs []ModelTensor `json:"outputs,omitempty"`
+	// Layers is the number of each type of layers.
+	Layers map[string]int64 `json:"layers,omitempty"`
+}
+
+// ModelTensor is the input or output tensor of model.
+type ModelTensor struct {
+	Name  string  `json:"name,omitempty"`
+	Size  []int64 `json:"size,omitempty"`
+	DType string  `json:"dType,omitempty"`
+	// OpType is special for PMML
+	OpType string `json:"opType,omitempty"`
+	// Values is special for PMML
+	Values []string `json:"values,omitempty"`
+}
+
+// ModelJobAttempt records the failure of an attempt.
+type ModelJobAttempt struct {
+	// Attempt is the sequence number of the attempt, starts from 1.
+	Attempt int32 `json:"attempt"`
+	// ExitCode is the exit code of executor.
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// Machine readable reason for the failure.
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating the reason for failure.
+	Message string `json:"message,omitempty"`
+	// FinishTime is the time when the attempt failed.
+	FinishTime metav1.Time `json:"finishTime,omitempty"`
+}
+
+// ModelJobConditionType is the type of modeljob condition.
+type ModelJobConditionType string
+
+const (
+	// ModelJobScheduled means the pod of modeljob has been scheduled to a node.
+	ModelJobScheduled ModelJobConditionType = "Scheduled"
+	// ModelJobInitialized means all init containers of modeljob have completed.
+	ModelJobInitialized ModelJobConditionType = "Initialized"
+	// ModelJobModelPulled means the model has been pulled from model registry.
+	ModelJobModelPulled ModelJobConditionType = "ModelPulled"
+	// ModelJobTaskCompleted means the extraction or conversion task has completed.
+	ModelJobTaskCompleted ModelJobConditionType = "TaskCompleted"
+	// ModelJobModelPushed means the model has been pushed to model registry.
+	ModelJobModelPushed ModelJobConditionType = "ModelPushed"
+)
+
+// ModelJobCondition describes the state of a modeljob stage at a certain point.
+type ModelJobCondition struct {
+	// Type of modeljob condition.
+	Type ModelJobConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Machine readable reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
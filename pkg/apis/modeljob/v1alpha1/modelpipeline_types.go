@@ -0,0 +1,151 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ModelPipelineLabelKey is the label key of the modeljobs created by modelpipeline, the value is the name of modelpipeline.
+	ModelPipelineLabelKey = "modelpipeline.kleveross.io/name"
+	// ModelPipelineStepLabelKey is the label key of the modeljobs created by modelpipeline, the value is the name of step.
+	ModelPipelineStepLabelKey = "modelpipeline.kleveross.io/step"
+	// ModelPipelineRerunFromStepAnnotationKey is the annotation key to rerun the modelpipeline from the step,
+	// the step and all steps depending on it are run again, and the annotation is removed after that.
+	ModelPipelineRerunFromStepAnnotationKey = "modelpipeline.kleveross.io/rerun-from-step"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.model",priority=1
+// +kubebuilder:printcolumn:name="Started",type="date",JSONPath=".status.startTime"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelPipeline is the Schema for the modelpipelines API, it runs the steps of
+// modeljobs in order or as a DAG, the output model of a step is the input model of next step.
+type ModelPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelPipelineSpec   `json:"spec,omitempty"`
+	Status ModelPipelineStatus `json:"status,omitempty"`
+}
+
+// ModelPipelineSpec defines the desired state of ModelPipeline
+type ModelPipelineSpec struct {
+	// Model is the model ref of pipeline input, eg: kleveross/resnet:v1.
+	Model string `json:"model"`
+
+	// Steps is the steps of pipeline. If no step declares dependsOn, the steps run in order,
+	// otherwise they run as a DAG and the steps without dependsOn run first.
+	// +kubebuilder:validation:MinItems=1
+	Steps []ModelPipelineStep `json:"steps"`
+}
+
+// ModelPipelineStep defines a step of pipeline, each step runs a modeljob.
+type ModelPipelineStep struct {
+	// Name is the unique name of step in pipeline.
+	Name string `json:"name"`
+
+	// DependsOn is the names of steps which must succeed before the step runs.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// ModelFrom is the name of step whose output model is the input model of the step.
+	// It defaults to the first step of dependsOn, or the model of pipeline if the step has no dependencies.
+	ModelFrom string `json:"modelFrom,omitempty"`
+
+	// Template is the spec of modeljob run by the step, its model is set by pipeline.
+	// The output model of step is the desiredTag of conversion, or the input model of extraction.
+	Template ModelJobSpec `json:"template"`
+}
+
+// ModelPipelinePhase is the phase of ModelPipeline and its steps.
+type ModelPipelinePhase string
+
+const (
+	ModelPipelinePending   ModelPipelinePhase = "Pending"
+	ModelPipelineRunning   ModelPipelinePhase = "Running"
+	ModelPipelineSucceeded ModelPipelinePhase = "Succeeded"
+	ModelPipelineFailed    ModelPipelinePhase = "Failed"
+	// ModelPipelineSkipped means the step is not run because a step it depends on failed.
+	ModelPipelineSkipped ModelPipelinePhase = "Skipped"
+)
+
+// ModelPipelineStatus defines the observed state of ModelPipeline
+type ModelPipelineStatus struct {
+	Phase ModelPipelinePhase `json:"phase,omitempty"`
+
+	// Human readable message indicating the reason for Failure
+	Message string `json:"message,omitempty"`
+
+	// Represents time when the pipeline started to run its first step.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents time when all steps of pipeline finished.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Steps is the status of each step.
+	// +listType=map
+	// +listMapKey=name
+	Steps []ModelPipelineStepStatus `json:"steps,omitempty"`
+}
+
+// ModelPipelineStepStatus defines the observed state of a step.
+type ModelPipelineStepStatus struct {
+	// Name is the name of step.
+	Name string `json:"name"`
+
+	Phase ModelPipelinePhase `json:"phase,omitempty"`
+
+	// Human readable message indicating the reason for Failure or Skipped
+	Message string `json:"message,omitempty"`
+
+	// Runs is the number of times the step is run, it increases when the pipeline reruns from the step.
+	Runs int32 `json:"runs,omitempty"`
+
+	// ModelJob is the name of modeljob of the latest run.
+	ModelJob string `json:"modelJob,omitempty"`
+
+	// Model is the input model of step.
+	Model string `json:"model,omitempty"`
+
+	// OutputModel is the output model of step, it is set when the step succeeded.
+	OutputModel string `json:"outputModel,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelPipelineList contains a list of ModelPipeline
+type ModelPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ModelPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ModelPipeline{}, &ModelPipelineList{})
+}
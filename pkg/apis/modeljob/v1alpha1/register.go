@@ -27,6 +27,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&ModelJob{},
 		&ModelJobList{},
+		&ModelConverter{},
+		&ModelConverterList{},
+		&ModelPipeline{},
+		&ModelPipelineList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
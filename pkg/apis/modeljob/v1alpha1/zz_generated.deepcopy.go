@@ -18,10 +18,78 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkCaseResult) DeepCopyInto(out *BenchmarkCaseResult) {
+	*out = *in
+	if in.LatencyP50 != nil {
+		in, out := &in.LatencyP50, &out.LatencyP50
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LatencyP95 != nil {
+		in, out := &in.LatencyP95, &out.LatencyP95
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LatencyP99 != nil {
+		in, out := &in.LatencyP99, &out.LatencyP99
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkCaseResult.
+func (in *BenchmarkCaseResult) DeepCopy() *BenchmarkCaseResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkCaseResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkSource) DeepCopyInto(out *BenchmarkSource) {
+	*out = *in
+	if in.BatchSizes != nil {
+		in, out := &in.BatchSizes, &out.BatchSizes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WarmupRequests != nil {
+		in, out := &in.WarmupRequests, &out.WarmupRequests
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkSource.
+func (in *BenchmarkSource) DeepCopy() *BenchmarkSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConversionBaseSpec) DeepCopyInto(out *ConversionBaseSpec) {
 	*out = *in
@@ -59,6 +127,75 @@ func (in *ConversionSource) DeepCopy() *ConversionSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmptyDirWorkspace) DeepCopyInto(out *EmptyDirWorkspace) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDirWorkspace.
+func (in *EmptyDirWorkspace) DeepCopy() *EmptyDirWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(EmptyDirWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralWorkspace) DeepCopyInto(out *EphemeralWorkspace) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralWorkspace.
+func (in *EphemeralWorkspace) DeepCopy() *EphemeralWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Executor) DeepCopyInto(out *Executor) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Executor.
+func (in *Executor) DeepCopy() *Executor {
+	if in == nil {
+		return nil
+	}
+	out := new(Executor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtractionSource) DeepCopyInto(out *ExtractionSource) {
 	*out = *in
@@ -75,6 +212,22 @@ func (in *ExtractionSource) DeepCopy() *ExtractionSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPModelSource) DeepCopyInto(out *HTTPModelSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPModelSource.
+func (in *HTTPModelSource) DeepCopy() *HTTPModelSource {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPModelSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MMdnnSpec) DeepCopyInto(out *MMdnnSpec) {
 	*out = *in
@@ -92,13 +245,130 @@ func (in *MMdnnSpec) DeepCopy() *MMdnnSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkResult) DeepCopyInto(out *ModelBenchmarkResult) {
+	*out = *in
+	if in.Cases != nil {
+		in, out := &in.Cases, &out.Cases
+		*out = make([]BenchmarkCaseResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkResult.
+func (in *ModelBenchmarkResult) DeepCopy() *ModelBenchmarkResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelConverter) DeepCopyInto(out *ModelConverter) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConverter.
+func (in *ModelConverter) DeepCopy() *ModelConverter {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelConverter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelConverter) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelConverterList) DeepCopyInto(out *ModelConverterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModelConverter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConverterList.
+func (in *ModelConverterList) DeepCopy() *ModelConverterList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelConverterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelConverterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelConverterSpec) DeepCopyInto(out *ModelConverterSpec) {
+	*out = *in
+	if in.SourceFormats != nil {
+		in, out := &in.SourceFormats, &out.SourceFormats
+		*out = make([]Format, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetFormats != nil {
+		in, out := &in.TargetFormats, &out.TargetFormats
+		*out = make([]Format, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConverterSpec.
+func (in *ModelConverterSpec) DeepCopy() *ModelConverterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelConverterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelJob) DeepCopyInto(out *ModelJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -120,6 +390,40 @@ func (in *ModelJob) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobAttempt) DeepCopyInto(out *ModelJobAttempt) {
+	*out = *in
+	in.FinishTime.DeepCopyInto(&out.FinishTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobAttempt.
+func (in *ModelJobAttempt) DeepCopy() *ModelJobAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobCondition) DeepCopyInto(out *ModelJobCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobCondition.
+func (in *ModelJobCondition) DeepCopy() *ModelJobCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelJobList) DeepCopyInto(out *ModelJobList) {
 	*out = *in
@@ -153,6 +457,52 @@ func (in *ModelJobList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobResult) DeepCopyInto(out *ModelJobResult) {
+	*out = *in
+	if in.Signature != nil {
+		in, out := &in.Signature, &out.Signature
+		*out = new(ModelSignature)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskDuration != nil {
+		in, out := &in.TaskDuration, &out.TaskDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PushDuration != nil {
+		in, out := &in.PushDuration, &out.PushDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ModelValidationResult)
+		**out = **in
+	}
+	if in.Optimization != nil {
+		in, out := &in.Optimization, &out.Optimization
+		*out = new(ModelOptimizationResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Benchmark != nil {
+		in, out := &in.Benchmark, &out.Benchmark
+		*out = new(ModelBenchmarkResult)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobResult.
+func (in *ModelJobResult) DeepCopy() *ModelJobResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelJobSource) DeepCopyInto(out *ModelJobSource) {
 	*out = *in
@@ -166,6 +516,21 @@ func (in *ModelJobSource) DeepCopyInto(out *ModelJobSource) {
 		*out = new(ConversionSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationSource)
+		**out = **in
+	}
+	if in.Optimization != nil {
+		in, out := &in.Optimization, &out.Optimization
+		*out = new(OptimizationSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Benchmark != nil {
+		in, out := &in.Benchmark, &out.Benchmark
+		*out = new(BenchmarkSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -182,6 +547,11 @@ func (in *ModelJobSource) DeepCopy() *ModelJobSource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelJobSpec) DeepCopyInto(out *ModelJobSpec) {
 	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(ModelSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DesiredTag != nil {
 		in, out := &in.DesiredTag, &out.DesiredTag
 		*out = new(string)
@@ -189,26 +559,95 @@ func (in *ModelJobSpec) DeepCopyInto(out *ModelJobSpec) {
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Executor != nil {
+		in, out := &in.Executor, &out.Executor
+		*out = new(Executor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitializerResources != nil {
+		in, out := &in.InitializerResources, &out.InitializerResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(Workspace)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InitContainer != nil {
 		in, out := &in.InitContainer, &out.InitContainer
-		*out = make([]v1.Container, len(*in))
+		*out = make([]corev1.Container, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.ModelJobSource.DeepCopyInto(&out.ModelJobSource)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobSpec.
-func (in *ModelJobSpec) DeepCopy() *ModelJobSpec {
-	if in == nil {
-		return nil
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RegistryCredentials != nil {
+		in, out := &in.RegistryCredentials, &out.RegistryCredentials
+		*out = new(RegistryCredentials)
+		**out = **in
+	}
+	in.ModelJobSource.DeepCopyInto(&out.ModelJobSource)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobSpec.
+func (in *ModelJobSpec) DeepCopy() *ModelJobSpec {
+	if in == nil {
+		return nil
 	}
 	out := new(ModelJobSpec)
 	in.DeepCopyInto(out)
@@ -218,6 +657,33 @@ func (in *ModelJobSpec) DeepCopy() *ModelJobSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelJobStatus) DeepCopyInto(out *ModelJobStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ModelJobCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailedAttempts != nil {
+		in, out := &in.FailedAttempts, &out.FailedAttempts
+		*out = make([]ModelJobAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(ModelJobResult)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -230,3 +696,474 @@ func (in *ModelJobStatus) DeepCopy() *ModelJobStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelOptimizationResult) DeepCopyInto(out *ModelOptimizationResult) {
+	*out = *in
+	if in.Optimizations != nil {
+		in, out := &in.Optimizations, &out.Optimizations
+		*out = make([]OptimizationType, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelOptimizationResult.
+func (in *ModelOptimizationResult) DeepCopy() *ModelOptimizationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelOptimizationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipeline) DeepCopyInto(out *ModelPipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipeline.
+func (in *ModelPipeline) DeepCopy() *ModelPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelPipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipelineList) DeepCopyInto(out *ModelPipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModelPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipelineList.
+func (in *ModelPipelineList) DeepCopy() *ModelPipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelPipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipelineSpec) DeepCopyInto(out *ModelPipelineSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ModelPipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipelineSpec.
+func (in *ModelPipelineSpec) DeepCopy() *ModelPipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipelineStatus) DeepCopyInto(out *ModelPipelineStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ModelPipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipelineStatus.
+func (in *ModelPipelineStatus) DeepCopy() *ModelPipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipelineStep) DeepCopyInto(out *ModelPipelineStep) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipelineStep.
+func (in *ModelPipelineStep) DeepCopy() *ModelPipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPipelineStepStatus) DeepCopyInto(out *ModelPipelineStepStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPipelineStepStatus.
+func (in *ModelPipelineStepStatus) DeepCopy() *ModelPipelineStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPipelineStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSignature) DeepCopyInto(out *ModelSignature) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]ModelTensor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]ModelTensor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Layers != nil {
+		in, out := &in.Layers, &out.Layers
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSignature.
+func (in *ModelSignature) DeepCopy() *ModelSignature {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSignature)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSource) DeepCopyInto(out *ModelSource) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3ModelSource)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPModelSource)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimModelSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSource.
+func (in *ModelSource) DeepCopy() *ModelSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelTensor) DeepCopyInto(out *ModelTensor) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelTensor.
+func (in *ModelTensor) DeepCopy() *ModelTensor {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelTensor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelValidationResult) DeepCopyInto(out *ModelValidationResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelValidationResult.
+func (in *ModelValidationResult) DeepCopy() *ModelValidationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelValidationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationSource) DeepCopyInto(out *OptimizationSource) {
+	*out = *in
+	if in.Optimizations != nil {
+		in, out := &in.Optimizations, &out.Optimizations
+		*out = make([]OptimizationType, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetOpset != nil {
+		in, out := &in.TargetOpset, &out.TargetOpset
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationSource.
+func (in *OptimizationSource) DeepCopy() *OptimizationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimModelSource) DeepCopyInto(out *PersistentVolumeClaimModelSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimModelSource.
+func (in *PersistentVolumeClaimModelSource) DeepCopy() *PersistentVolumeClaimModelSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimModelSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimWorkspace) DeepCopyInto(out *PersistentVolumeClaimWorkspace) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimWorkspace.
+func (in *PersistentVolumeClaimWorkspace) DeepCopy() *PersistentVolumeClaimWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCredentials) DeepCopyInto(out *RegistryCredentials) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCredentials.
+func (in *RegistryCredentials) DeepCopy() *RegistryCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryBackoff) DeepCopyInto(out *RetryBackoff) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryBackoff.
+func (in *RetryBackoff) DeepCopy() *RetryBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(RetryBackoff)
+		**out = **in
+	}
+	if in.RetryableExitCodes != nil {
+		in, out := &in.RetryableExitCodes, &out.RetryableExitCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ModelSource) DeepCopyInto(out *S3ModelSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ModelSource.
+func (in *S3ModelSource) DeepCopy() *S3ModelSource {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ModelSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationSource) DeepCopyInto(out *ValidationSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationSource.
+func (in *ValidationSource) DeepCopy() *ValidationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workspace) DeepCopyInto(out *Workspace) {
+	*out = *in
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(EmptyDirWorkspace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(EphemeralWorkspace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimWorkspace)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
+func (in *Workspace) DeepCopy() *Workspace {
+	if in == nil {
+		return nil
+	}
+	out := new(Workspace)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,4 @@
+// +k8s:deepcopy-gen=package
+// +groupName=kleveross.io
+
+package v1beta1
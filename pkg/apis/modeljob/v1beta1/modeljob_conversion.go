@@ -0,0 +1,254 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+const (
+	// V1alpha1SpecAnnotationKey keeps the v1alpha1 spec of the modeljob served as v1beta1 if v1beta1 can not
+	// represent it, e.g. an empty mmdnn conversion or more than one source.
+	V1alpha1SpecAnnotationKey = "kleveross.io/v1alpha1-spec"
+	// V1beta1SpecAnnotationKey keeps the v1beta1 spec of the modeljob stored as v1alpha1 if v1alpha1 can not
+	// represent it, e.g. the destination format of an extraction.
+	V1beta1SpecAnnotationKey = "kleveross.io/v1beta1-spec"
+)
+
+// ConvertTo converts the ModelJob to the hub version v1alpha1. The v1alpha1 spec kept in the annotation is
+// restored if the spec is not changed since it was converted, and the spec which v1alpha1 can not represent
+// is kept in the annotation, so that the modeljobs are not changed by the round trip of conversion.
+func (src *ModelJob) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.ModelJob)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = src.Status
+	dst.Spec = convertSpecToV1alpha1(&src.Spec)
+
+	kept := &v1alpha1.ModelJobSpec{}
+	if popSpecAnnotation(&dst.ObjectMeta, V1alpha1SpecAnnotationKey, kept) &&
+		equality.Semantic.DeepEqual(convertSpecFromV1alpha1(kept), src.Spec) {
+		dst.Spec = *kept
+	}
+	if !equality.Semantic.DeepEqual(convertSpecFromV1alpha1(&dst.Spec), src.Spec) {
+		return setSpecAnnotation(&dst.ObjectMeta, V1beta1SpecAnnotationKey, &src.Spec)
+	}
+	return nil
+}
+
+// convertSpecToV1alpha1 converts the spec to v1alpha1.
+func convertSpecToV1alpha1(spec *ModelJobSpec) v1alpha1.ModelJobSpec {
+	dst := v1alpha1.ModelJobSpec{
+		Model:                   spec.Source.Model,
+		Env:                     spec.Env,
+		Executor:                spec.Executor,
+		NodeSelector:            spec.NodeSelector,
+		Tolerations:             spec.Tolerations,
+		Affinity:                spec.Affinity,
+		PriorityClassName:       spec.PriorityClassName,
+		ServiceAccountName:      spec.ServiceAccountName,
+		Workspace:               spec.Workspace,
+		InitContainer:           spec.InitContainers,
+		RetryPolicy:             spec.RetryPolicy,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		ActiveDeadlineSeconds:   spec.ActiveDeadlineSeconds,
+		Priority:                spec.Priority,
+		Suspend:                 spec.Suspend,
+	}
+
+	if spec.Source.S3 != nil || spec.Source.HTTP != nil || spec.Source.PersistentVolumeClaim != nil {
+		dst.Source = &v1alpha1.ModelSource{
+			S3:                    spec.Source.S3,
+			HTTP:                  spec.Source.HTTP,
+			PersistentVolumeClaim: spec.Source.PersistentVolumeClaim,
+		}
+	}
+	if spec.Destination != nil && spec.Destination.Model != "" {
+		desiredTag := spec.Destination.Model
+		dst.DesiredTag = &desiredTag
+	}
+	if spec.Resources != nil {
+		dst.Resources = spec.Resources.Executor
+		dst.InitializerResources = spec.Resources.Initializer
+	}
+	if spec.Credentials != nil {
+		dst.RegistryCredentials = spec.Credentials.Registry
+	}
+
+	format := spec.Source.Format
+	switch spec.Type {
+	case ModelJobTypeExtraction:
+		dst.Extraction = &v1alpha1.ExtractionSource{Format: format}
+	case ModelJobTypeConversion:
+		dst.Conversion = &v1alpha1.ConversionSource{}
+		var to v1alpha1.Format
+		if spec.Destination != nil {
+			to = spec.Destination.Format
+		}
+		if format != "" || to != "" {
+			dst.Conversion.MMdnn = &v1alpha1.MMdnnSpec{
+				ConversionBaseSpec: v1alpha1.ConversionBaseSpec{From: format, To: to},
+			}
+		}
+	case ModelJobTypeValidation:
+		dst.Validation = &v1alpha1.ValidationSource{Format: format}
+		if spec.Validation != nil {
+			dst.Validation.SampleInputs = spec.Validation.SampleInputs
+			dst.Validation.ExpectedOutputs = spec.Validation.ExpectedOutputs
+			dst.Validation.Tolerance = spec.Validation.Tolerance
+		}
+	case ModelJobTypeOptimization:
+		dst.Optimization = &v1alpha1.OptimizationSource{Format: format}
+		if spec.Optimization != nil {
+			dst.Optimization.Optimizations = spec.Optimization.Optimizations
+			dst.Optimization.TargetOpset = spec.Optimization.TargetOpset
+		}
+	case ModelJobTypeBenchmark:
+		dst.Benchmark = &v1alpha1.BenchmarkSource{Format: format}
+		if spec.Benchmark != nil {
+			dst.Benchmark.BatchSizes = spec.Benchmark.BatchSizes
+			dst.Benchmark.Concurrency = spec.Benchmark.Concurrency
+			dst.Benchmark.Requests = spec.Benchmark.Requests
+			dst.Benchmark.WarmupRequests = spec.Benchmark.WarmupRequests
+		}
+	}
+
+	return dst
+}
+
+// ConvertFrom converts the ModelJob from the hub version v1alpha1, the annotations of spec are handled as
+// ConvertTo does.
+func (dst *ModelJob) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.ModelJob)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = src.Status
+	dst.Spec = convertSpecFromV1alpha1(&src.Spec)
+
+	kept := &ModelJobSpec{}
+	if popSpecAnnotation(&dst.ObjectMeta, V1beta1SpecAnnotationKey, kept) &&
+		equality.Semantic.DeepEqual(convertSpecToV1alpha1(kept), src.Spec) {
+		dst.Spec = *kept
+	}
+	if !equality.Semantic.DeepEqual(convertSpecToV1alpha1(&dst.Spec), src.Spec) {
+		return setSpecAnnotation(&dst.ObjectMeta, V1alpha1SpecAnnotationKey, &src.Spec)
+	}
+	return nil
+}
+
+// convertSpecFromV1alpha1 converts the spec from v1alpha1. The type of modeljob is decided by the first source
+// of extraction, conversion, validation, optimization and benchmark which is set.
+func convertSpecFromV1alpha1(spec *v1alpha1.ModelJobSpec) ModelJobSpec {
+	dst := ModelJobSpec{
+		Source:                  ModelJobSource{Model: spec.Model},
+		Executor:                spec.Executor,
+		Env:                     spec.Env,
+		NodeSelector:            spec.NodeSelector,
+		Tolerations:             spec.Tolerations,
+		Affinity:                spec.Affinity,
+		PriorityClassName:       spec.PriorityClassName,
+		ServiceAccountName:      spec.ServiceAccountName,
+		Workspace:               spec.Workspace,
+		InitContainers:          spec.InitContainer,
+		RetryPolicy:             spec.RetryPolicy,
+		TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
+		ActiveDeadlineSeconds:   spec.ActiveDeadlineSeconds,
+		Priority:                spec.Priority,
+		Suspend:                 spec.Suspend,
+	}
+
+	if spec.Source != nil {
+		dst.Source.S3 = spec.Source.S3
+		dst.Source.HTTP = spec.Source.HTTP
+		dst.Source.PersistentVolumeClaim = spec.Source.PersistentVolumeClaim
+	}
+	if spec.DesiredTag != nil && *spec.DesiredTag != "" {
+		dst.Destination = &ModelJobDestination{Model: *spec.DesiredTag}
+	}
+	if spec.Resources != nil || spec.InitializerResources != nil {
+		dst.Resources = &ModelJobResources{
+			Executor:    spec.Resources,
+			Initializer: spec.InitializerResources,
+		}
+	}
+	if spec.RegistryCredentials != nil {
+		dst.Credentials = &ModelJobCredentials{Registry: spec.RegistryCredentials}
+	}
+
+	switch {
+	case spec.Extraction != nil:
+		dst.Type = ModelJobTypeExtraction
+		dst.Source.Format = spec.Extraction.Format
+	case spec.Conversion != nil:
+		dst.Type = ModelJobTypeConversion
+		if spec.Conversion.MMdnn != nil {
+			dst.Source.Format = spec.Conversion.MMdnn.From
+			if to := spec.Conversion.MMdnn.To; to != "" {
+				if dst.Destination == nil {
+					dst.Destination = &ModelJobDestination{}
+				}
+				dst.Destination.Format = to
+			}
+		}
+	case spec.Validation != nil:
+		dst.Type = ModelJobTypeValidation
+		dst.Source.Format = spec.Validation.Format
+		if spec.Validation.SampleInputs != "" || spec.Validation.ExpectedOutputs != "" || spec.Validation.Tolerance != "" {
+			dst.Validation = &ValidationParameters{
+				SampleInputs:    spec.Validation.SampleInputs,
+				ExpectedOutputs: spec.Validation.ExpectedOutputs,
+				Tolerance:       spec.Validation.Tolerance,
+			}
+		}
+	case spec.Optimization != nil:
+		dst.Type = ModelJobTypeOptimization
+		dst.Source.Format = spec.Optimization.Format
+		dst.Optimization = &OptimizationParameters{
+			Optimizations: spec.Optimization.Optimizations,
+			TargetOpset:   spec.Optimization.TargetOpset,
+		}
+	case spec.Benchmark != nil:
+		dst.Type = ModelJobTypeBenchmark
+		dst.Source.Format = spec.Benchmark.Format
+		if len(spec.Benchmark.BatchSizes) != 0 || len(spec.Benchmark.Concurrency) != 0 ||
+			spec.Benchmark.Requests != nil || spec.Benchmark.WarmupRequests != nil {
+			dst.Benchmark = &BenchmarkParameters{
+				BatchSizes:     spec.Benchmark.BatchSizes,
+				Concurrency:    spec.Benchmark.Concurrency,
+				Requests:       spec.Benchmark.Requests,
+				WarmupRequests: spec.Benchmark.WarmupRequests,
+			}
+		}
+	}
+
+	return dst
+}
+
+// popSpecAnnotation removes the spec kept in the annotation of key, and decodes it into spec. It returns false
+// if the annotation is not set or can not be decoded.
+func popSpecAnnotation(meta *metav1.ObjectMeta, key string, spec interface{}) bool {
+	data, ok := meta.Annotations[key]
+	if !ok {
+		return false
+	}
+	delete(meta.Annotations, key)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return json.Unmarshal([]byte(data), spec) == nil
+}
+
+// setSpecAnnotation keeps the spec in the annotation of key.
+func setSpecAnnotation(meta *metav1.ObjectMeta, key string, spec interface{}) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = string(data)
+	return nil
+}
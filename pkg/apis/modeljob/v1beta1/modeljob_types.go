@@ -0,0 +1,240 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:unservedversion
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.source.model",priority=1
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",priority=1
+// +kubebuilder:printcolumn:name="Queue",type="integer",JSONPath=".status.queuePosition",priority=1
+// +kubebuilder:printcolumn:name="Started",type="date",JSONPath=".status.startTime"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelJob is the Schema for the modeljobs API
+type ModelJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelJobSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ModelJobStatus `json:"status,omitempty"`
+}
+
+// ModelJobType is the type of the task of modeljob.
+// +kubebuilder:validation:Enum=Extraction;Conversion;Validation;Optimization;Benchmark
+type ModelJobType string
+
+const (
+	// ModelJobTypeExtraction extracts the signature of the source model and pushes it as a new version.
+	ModelJobTypeExtraction ModelJobType = "Extraction"
+	// ModelJobTypeConversion converts the source model to the format of destination.
+	ModelJobTypeConversion ModelJobType = "Conversion"
+	// ModelJobTypeValidation runs the smoke inference of the source model.
+	ModelJobTypeValidation ModelJobType = "Validation"
+	// ModelJobTypeOptimization optimizes the source model and pushes it to the destination.
+	ModelJobTypeOptimization ModelJobType = "Optimization"
+	// ModelJobTypeBenchmark benchmarks the source model in its serving runtime.
+	ModelJobTypeBenchmark ModelJobType = "Benchmark"
+)
+
+// ModelJobSpec defines the desired state of ModelJob
+type ModelJobSpec struct {
+	// Type is the type of the task of modeljob.
+	Type ModelJobType `json:"type"`
+
+	// Source is the input model of modeljob.
+	Source ModelJobSource `json:"source"`
+
+	// Destination is the output model of conversion and optimization.
+	Destination *ModelJobDestination `json:"destination,omitempty"`
+
+	// Validation is the parameters of validation.
+	Validation *ValidationParameters `json:"validation,omitempty"`
+
+	// Optimization is the parameters of optimization.
+	Optimization *OptimizationParameters `json:"optimization,omitempty"`
+
+	// Benchmark is the parameters of benchmark.
+	Benchmark *BenchmarkParameters `json:"benchmark,omitempty"`
+
+	// Executor overrides the image, command and args of the executor container instead of the modelconverter
+	// or the preset image of operator, the image must match the executor image allowlist of operator.
+	Executor *v1alpha1.Executor `json:"executor,omitempty"`
+
+	// Resources is the resources of the containers of job.
+	Resources *ModelJobResources `json:"resources,omitempty"`
+
+	// Credentials is the credentials which the job pulls and pushes models with.
+	Credentials *ModelJobCredentials `json:"credentials,omitempty"`
+
+	// Env defines the env of the executor container.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// NodeSelector is the node selector of the pod of job.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations is the tolerations of the pod of job.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity is the scheduling constraints of the pod of job.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the priority class of the pod of job.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// ServiceAccountName is the service account to run the pod of job.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Workspace is the volume which the model is pulled to and shared by the init containers and the executor,
+	// it is an emptyDir without size limit if it is not set.
+	Workspace *v1alpha1.Workspace `json:"workspace,omitempty"`
+
+	// InitContainers replaces the model initializer, which pulls the source model to /models/input.
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// RetryPolicy defines how to retry the modeljob when its job failed, the modeljob is not retried if it is nil.
+	RetryPolicy *v1alpha1.RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of a modeljob that has finished,
+	// the modeljob and its job will be deleted after the ttl expired.
+	// The operator-wide default is used if it is not set.
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds relative to the start time that
+	// the job of modeljob may be active before it is failed with Timeout reason.
+	// The operator-wide default of the format is used if it is not set.
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Priority is the priority of modeljob in the queue of operator, the modeljobs with higher priority
+	// start first when the concurrent jobs are limited. It defaults to 0.
+	Priority *int32 `json:"priority,omitempty"`
+
+	// Suspend suspends the modeljob, its running job is deleted and it is started again when it is resumed.
+	Suspend *bool `json:"suspend,omitempty"`
+}
+
+// ModelJobSource is the input model of modeljob. The model is pulled from the registry, or staged from one of
+// S3, HTTP and PersistentVolumeClaim for extraction and conversion.
+type ModelJobSource struct {
+	// Model is the model ref in registry, eg: release/resnet:v1. The extraction of the model staged from
+	// the other sources pushes it to Model as a new version.
+	Model string `json:"model"`
+
+	// Format is the format of model.
+	Format v1alpha1.Format `json:"format"`
+
+	// S3 is the object or the objects under the prefix in an S3-compatible bucket.
+	S3 *v1alpha1.S3ModelSource `json:"s3,omitempty"`
+
+	// HTTP is the file downloaded from the HTTP(S) URL.
+	HTTP *v1alpha1.HTTPModelSource `json:"http,omitempty"`
+
+	// PersistentVolumeClaim is the file or directory in an existing PVC in the namespace of modeljob.
+	PersistentVolumeClaim *v1alpha1.PersistentVolumeClaimModelSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+// ModelJobDestination is the output model of modeljob.
+type ModelJobDestination struct {
+	// Model is the model ref which the output model is pushed to, eg: release/resnet:v1-onnx.
+	Model string `json:"model"`
+
+	// Format is the format of the output model of conversion, the optimization keeps the format of source.
+	Format v1alpha1.Format `json:"format,omitempty"`
+}
+
+// ValidationParameters defines the smoke inference of the model.
+type ValidationParameters struct {
+	// SampleInputs is the path in model of the sample inputs, the inputs are generated by the signature if it is empty.
+	SampleInputs string `json:"sampleInputs,omitempty"`
+
+	// ExpectedOutputs is the path in model of the expected outputs of the sample inputs.
+	ExpectedOutputs string `json:"expectedOutputs,omitempty"`
+
+	// Tolerance is the max absolute error between the outputs and the expected outputs, eg: "1e-5".
+	Tolerance string `json:"tolerance,omitempty"`
+}
+
+// OptimizationParameters defines the optimizations of the model.
+type OptimizationParameters struct {
+	// Optimizations is the optimizations in order.
+	// +kubebuilder:validation:MinItems=1
+	Optimizations []v1alpha1.OptimizationType `json:"optimizations"`
+
+	// TargetOpset is the ONNX opset which the model is upgraded to, it must be set for UpgradeOpset.
+	// +kubebuilder:validation:Minimum=1
+	TargetOpset *int32 `json:"targetOpset,omitempty"`
+}
+
+// BenchmarkParameters defines the cases of benchmark, a case is run for each batch size and concurrency.
+type BenchmarkParameters struct {
+	// BatchSizes is the batch sizes of the requests, it defaults to [1].
+	BatchSizes []int32 `json:"batchSizes,omitempty"`
+
+	// Concurrency is the numbers of concurrent requests, it defaults to [1].
+	Concurrency []int32 `json:"concurrency,omitempty"`
+
+	// Requests is the number of requests of each case, it defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	Requests *int32 `json:"requests,omitempty"`
+
+	// WarmupRequests is the number of requests before each case, it defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	WarmupRequests *int32 `json:"warmupRequests,omitempty"`
+}
+
+// ModelJobResources is the resources of the containers of job.
+type ModelJobResources struct {
+	// Executor is the resources of the executor container. The resources of modelconverter are used if it is not set.
+	Executor *corev1.ResourceRequirements `json:"executor,omitempty"`
+
+	// Initializer is the resources of the model initializer container.
+	Initializer *corev1.ResourceRequirements `json:"initializer,omitempty"`
+}
+
+// ModelJobCredentials is the credentials of modeljob.
+type ModelJobCredentials struct {
+	// Registry references the Secret of the credentials of model registry. The ORMB account of operator is used
+	// if it is not set and the registry credentials fallback of operator is enabled.
+	Registry *v1alpha1.RegistryCredentials `json:"registry,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ModelJobList contains a list of ModelJob
+type ModelJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ModelJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ModelJob{}, &ModelJobList{})
+}
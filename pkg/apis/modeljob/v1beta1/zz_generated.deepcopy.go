@@ -0,0 +1,372 @@
+// +build !ignore_autogenerated
+
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkParameters) DeepCopyInto(out *BenchmarkParameters) {
+	*out = *in
+	if in.BatchSizes != nil {
+		in, out := &in.BatchSizes, &out.BatchSizes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WarmupRequests != nil {
+		in, out := &in.WarmupRequests, &out.WarmupRequests
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkParameters.
+func (in *BenchmarkParameters) DeepCopy() *BenchmarkParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJob) DeepCopyInto(out *ModelJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJob.
+func (in *ModelJob) DeepCopy() *ModelJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobCredentials) DeepCopyInto(out *ModelJobCredentials) {
+	*out = *in
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(v1alpha1.RegistryCredentials)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobCredentials.
+func (in *ModelJobCredentials) DeepCopy() *ModelJobCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobDestination) DeepCopyInto(out *ModelJobDestination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobDestination.
+func (in *ModelJobDestination) DeepCopy() *ModelJobDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobList) DeepCopyInto(out *ModelJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModelJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobList.
+func (in *ModelJobList) DeepCopy() *ModelJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobResources) DeepCopyInto(out *ModelJobResources) {
+	*out = *in
+	if in.Executor != nil {
+		in, out := &in.Executor, &out.Executor
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Initializer != nil {
+		in, out := &in.Initializer, &out.Initializer
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobResources.
+func (in *ModelJobResources) DeepCopy() *ModelJobResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobSource) DeepCopyInto(out *ModelJobSource) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(v1alpha1.S3ModelSource)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(v1alpha1.HTTPModelSource)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1alpha1.PersistentVolumeClaimModelSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobSource.
+func (in *ModelJobSource) DeepCopy() *ModelJobSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelJobSpec) DeepCopyInto(out *ModelJobSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(ModelJobDestination)
+		**out = **in
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationParameters)
+		**out = **in
+	}
+	if in.Optimization != nil {
+		in, out := &in.Optimization, &out.Optimization
+		*out = new(OptimizationParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Benchmark != nil {
+		in, out := &in.Benchmark, &out.Benchmark
+		*out = new(BenchmarkParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Executor != nil {
+		in, out := &in.Executor, &out.Executor
+		*out = new(v1alpha1.Executor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ModelJobResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(ModelJobCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(v1alpha1.Workspace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(v1alpha1.RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelJobSpec.
+func (in *ModelJobSpec) DeepCopy() *ModelJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationParameters) DeepCopyInto(out *OptimizationParameters) {
+	*out = *in
+	if in.Optimizations != nil {
+		in, out := &in.Optimizations, &out.Optimizations
+		*out = make([]v1alpha1.OptimizationType, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetOpset != nil {
+		in, out := &in.TargetOpset, &out.TargetOpset
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationParameters.
+func (in *OptimizationParameters) DeepCopy() *OptimizationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationParameters) DeepCopyInto(out *ValidationParameters) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationParameters.
+func (in *ValidationParameters) DeepCopy() *ValidationParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationParameters)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,119 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeModelConverters implements ModelConverterInterface
+type FakeModelConverters struct {
+	Fake *FakeKleverossV1alpha1
+}
+
+var modelconvertersResource = schema.GroupVersionResource{Group: "kleveross.io", Version: "v1alpha1", Resource: "modelconverters"}
+
+var modelconvertersKind = schema.GroupVersionKind{Group: "kleveross.io", Version: "v1alpha1", Kind: "ModelConverter"}
+
+// Get takes name of the modelConverter, and returns the corresponding modelConverter object, and an error if there is any.
+func (c *FakeModelConverters) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ModelConverter, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(modelconvertersResource, name), &v1alpha1.ModelConverter{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelConverter), err
+}
+
+// List takes label and field selectors, and returns the list of ModelConverters that match those selectors.
+func (c *FakeModelConverters) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ModelConverterList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(modelconvertersResource, modelconvertersKind, opts), &v1alpha1.ModelConverterList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ModelConverterList{ListMeta: obj.(*v1alpha1.ModelConverterList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ModelConverterList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested modelConverters.
+func (c *FakeModelConverters) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(modelconvertersResource, opts))
+}
+
+// Create takes the representation of a modelConverter and creates it.  Returns the server's representation of the modelConverter, and an error, if there is any.
+func (c *FakeModelConverters) Create(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.CreateOptions) (result *v1alpha1.ModelConverter, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(modelconvertersResource, modelConverter), &v1alpha1.ModelConverter{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelConverter), err
+}
+
+// Update takes the representation of a modelConverter and updates it. Returns the server's representation of the modelConverter, and an error, if there is any.
+func (c *FakeModelConverters) Update(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.UpdateOptions) (result *v1alpha1.ModelConverter, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(modelconvertersResource, modelConverter), &v1alpha1.ModelConverter{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelConverter), err
+}
+
+// Delete takes name of the modelConverter and deletes it. Returns an error if one occurs.
+func (c *FakeModelConverters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(modelconvertersResource, name), &v1alpha1.ModelConverter{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeModelConverters) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(modelconvertersResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ModelConverterList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched modelConverter.
+func (c *FakeModelConverters) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelConverter, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(modelconvertersResource, name, pt, data, subresources...), &v1alpha1.ModelConverter{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelConverter), err
+}
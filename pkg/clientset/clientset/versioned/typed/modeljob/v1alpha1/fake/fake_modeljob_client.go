@@ -25,10 +25,18 @@ type FakeKleverossV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeKleverossV1alpha1) ModelConverters() v1alpha1.ModelConverterInterface {
+	return &FakeModelConverters{c}
+}
+
 func (c *FakeKleverossV1alpha1) ModelJobs(namespace string) v1alpha1.ModelJobInterface {
 	return &FakeModelJobs{c, namespace}
 }
 
+func (c *FakeKleverossV1alpha1) ModelPipelines(namespace string) v1alpha1.ModelPipelineInterface {
+	return &FakeModelPipelines{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeKleverossV1alpha1) RESTClient() rest.Interface {
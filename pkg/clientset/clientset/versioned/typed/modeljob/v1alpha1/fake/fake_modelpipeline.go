@@ -0,0 +1,139 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeModelPipelines implements ModelPipelineInterface
+type FakeModelPipelines struct {
+	Fake *FakeKleverossV1alpha1
+	ns   string
+}
+
+var modelpipelinesResource = schema.GroupVersionResource{Group: "kleveross.io", Version: "v1alpha1", Resource: "modelpipelines"}
+
+var modelpipelinesKind = schema.GroupVersionKind{Group: "kleveross.io", Version: "v1alpha1", Kind: "ModelPipeline"}
+
+// Get takes name of the modelPipeline, and returns the corresponding modelPipeline object, and an error if there is any.
+func (c *FakeModelPipelines) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ModelPipeline, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(modelpipelinesResource, c.ns, name), &v1alpha1.ModelPipeline{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelPipeline), err
+}
+
+// List takes label and field selectors, and returns the list of ModelPipelines that match those selectors.
+func (c *FakeModelPipelines) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ModelPipelineList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(modelpipelinesResource, modelpipelinesKind, c.ns, opts), &v1alpha1.ModelPipelineList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ModelPipelineList{ListMeta: obj.(*v1alpha1.ModelPipelineList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ModelPipelineList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested modelPipelines.
+func (c *FakeModelPipelines) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(modelpipelinesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a modelPipeline and creates it.  Returns the server's representation of the modelPipeline, and an error, if there is any.
+func (c *FakeModelPipelines) Create(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.CreateOptions) (result *v1alpha1.ModelPipeline, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(modelpipelinesResource, c.ns, modelPipeline), &v1alpha1.ModelPipeline{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelPipeline), err
+}
+
+// Update takes the representation of a modelPipeline and updates it. Returns the server's representation of the modelPipeline, and an error, if there is any.
+func (c *FakeModelPipelines) Update(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (result *v1alpha1.ModelPipeline, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(modelpipelinesResource, c.ns, modelPipeline), &v1alpha1.ModelPipeline{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelPipeline), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeModelPipelines) UpdateStatus(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (*v1alpha1.ModelPipeline, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(modelpipelinesResource, "status", c.ns, modelPipeline), &v1alpha1.ModelPipeline{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelPipeline), err
+}
+
+// Delete takes name of the modelPipeline and deletes it. Returns an error if one occurs.
+func (c *FakeModelPipelines) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(modelpipelinesResource, c.ns, name), &v1alpha1.ModelPipeline{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeModelPipelines) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(modelpipelinesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ModelPipelineList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched modelPipeline.
+func (c *FakeModelPipelines) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelPipeline, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(modelpipelinesResource, c.ns, name, pt, data, subresources...), &v1alpha1.ModelPipeline{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ModelPipeline), err
+}
@@ -15,4 +15,8 @@ limitations under the License.
 
 package v1alpha1
 
+type ModelConverterExpansion interface{}
+
 type ModelJobExpansion interface{}
+
+type ModelPipelineExpansion interface{}
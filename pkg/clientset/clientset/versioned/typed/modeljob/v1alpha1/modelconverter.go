@@ -0,0 +1,165 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	scheme "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ModelConvertersGetter has a method to return a ModelConverterInterface.
+// A group's client should implement this interface.
+type ModelConvertersGetter interface {
+	ModelConverters() ModelConverterInterface
+}
+
+// ModelConverterInterface has methods to work with ModelConverter resources.
+type ModelConverterInterface interface {
+	Create(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.CreateOptions) (*v1alpha1.ModelConverter, error)
+	Update(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.UpdateOptions) (*v1alpha1.ModelConverter, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ModelConverter, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ModelConverterList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelConverter, err error)
+	ModelConverterExpansion
+}
+
+// modelConverters implements ModelConverterInterface
+type modelConverters struct {
+	client rest.Interface
+}
+
+// newModelConverters returns a ModelConverters
+func newModelConverters(c *KleverossV1alpha1Client) *modelConverters {
+	return &modelConverters{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the modelConverter, and returns the corresponding modelConverter object, and an error if there is any.
+func (c *modelConverters) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ModelConverter, err error) {
+	result = &v1alpha1.ModelConverter{}
+	err = c.client.Get().
+		Resource("modelconverters").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ModelConverters that match those selectors.
+func (c *modelConverters) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ModelConverterList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ModelConverterList{}
+	err = c.client.Get().
+		Resource("modelconverters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested modelConverters.
+func (c *modelConverters) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("modelconverters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a modelConverter and creates it.  Returns the server's representation of the modelConverter, and an error, if there is any.
+func (c *modelConverters) Create(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.CreateOptions) (result *v1alpha1.ModelConverter, err error) {
+	result = &v1alpha1.ModelConverter{}
+	err = c.client.Post().
+		Resource("modelconverters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(modelConverter).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a modelConverter and updates it. Returns the server's representation of the modelConverter, and an error, if there is any.
+func (c *modelConverters) Update(ctx context.Context, modelConverter *v1alpha1.ModelConverter, opts v1.UpdateOptions) (result *v1alpha1.ModelConverter, err error) {
+	result = &v1alpha1.ModelConverter{}
+	err = c.client.Put().
+		Resource("modelconverters").
+		Name(modelConverter.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(modelConverter).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the modelConverter and deletes it. Returns an error if one occurs.
+func (c *modelConverters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("modelconverters").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *modelConverters) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("modelconverters").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched modelConverter.
+func (c *modelConverters) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelConverter, err error) {
+	result = &v1alpha1.ModelConverter{}
+	err = c.client.Patch(pt).
+		Resource("modelconverters").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -23,7 +23,9 @@ import (
 
 type KleverossV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ModelConvertersGetter
 	ModelJobsGetter
+	ModelPipelinesGetter
 }
 
 // KleverossV1alpha1Client is used to interact with features provided by the kleveross.io group.
@@ -31,10 +33,18 @@ type KleverossV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KleverossV1alpha1Client) ModelConverters() ModelConverterInterface {
+	return newModelConverters(c)
+}
+
 func (c *KleverossV1alpha1Client) ModelJobs(namespace string) ModelJobInterface {
 	return newModelJobs(c, namespace)
 }
 
+func (c *KleverossV1alpha1Client) ModelPipelines(namespace string) ModelPipelineInterface {
+	return newModelPipelines(c, namespace)
+}
+
 // NewForConfig creates a new KleverossV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*KleverossV1alpha1Client, error) {
 	config := *c
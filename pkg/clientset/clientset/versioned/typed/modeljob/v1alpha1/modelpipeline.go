@@ -0,0 +1,192 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	scheme "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ModelPipelinesGetter has a method to return a ModelPipelineInterface.
+// A group's client should implement this interface.
+type ModelPipelinesGetter interface {
+	ModelPipelines(namespace string) ModelPipelineInterface
+}
+
+// ModelPipelineInterface has methods to work with ModelPipeline resources.
+type ModelPipelineInterface interface {
+	Create(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.CreateOptions) (*v1alpha1.ModelPipeline, error)
+	Update(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (*v1alpha1.ModelPipeline, error)
+	UpdateStatus(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (*v1alpha1.ModelPipeline, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ModelPipeline, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ModelPipelineList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelPipeline, err error)
+	ModelPipelineExpansion
+}
+
+// modelPipelines implements ModelPipelineInterface
+type modelPipelines struct {
+	client rest.Interface
+	ns     string
+}
+
+// newModelPipelines returns a ModelPipelines
+func newModelPipelines(c *KleverossV1alpha1Client, namespace string) *modelPipelines {
+	return &modelPipelines{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the modelPipeline, and returns the corresponding modelPipeline object, and an error if there is any.
+func (c *modelPipelines) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ModelPipeline, err error) {
+	result = &v1alpha1.ModelPipeline{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ModelPipelines that match those selectors.
+func (c *modelPipelines) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ModelPipelineList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ModelPipelineList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested modelPipelines.
+func (c *modelPipelines) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a modelPipeline and creates it.  Returns the server's representation of the modelPipeline, and an error, if there is any.
+func (c *modelPipelines) Create(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.CreateOptions) (result *v1alpha1.ModelPipeline, err error) {
+	result = &v1alpha1.ModelPipeline{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(modelPipeline).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a modelPipeline and updates it. Returns the server's representation of the modelPipeline, and an error, if there is any.
+func (c *modelPipelines) Update(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (result *v1alpha1.ModelPipeline, err error) {
+	result = &v1alpha1.ModelPipeline{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		Name(modelPipeline.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(modelPipeline).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *modelPipelines) UpdateStatus(ctx context.Context, modelPipeline *v1alpha1.ModelPipeline, opts v1.UpdateOptions) (result *v1alpha1.ModelPipeline, err error) {
+	result = &v1alpha1.ModelPipeline{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		Name(modelPipeline.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(modelPipeline).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the modelPipeline and deletes it. Returns an error if one occurs.
+func (c *modelPipelines) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *modelPipelines) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched modelPipeline.
+func (c *modelPipelines) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ModelPipeline, err error) {
+	result = &v1alpha1.ModelPipeline{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("modelpipelines").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -50,8 +50,12 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=kleveross.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("modelconverters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kleveross().V1alpha1().ModelConverters().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("modeljobs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kleveross().V1alpha1().ModelJobs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("modelpipelines"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kleveross().V1alpha1().ModelPipelines().Informer()}, nil
 
 	}
 
@@ -21,8 +21,12 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ModelConverters returns a ModelConverterInformer.
+	ModelConverters() ModelConverterInformer
 	// ModelJobs returns a ModelJobInformer.
 	ModelJobs() ModelJobInformer
+	// ModelPipelines returns a ModelPipelineInformer.
+	ModelPipelines() ModelPipelineInformer
 }
 
 type version struct {
@@ -36,7 +40,17 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ModelConverters returns a ModelConverterInformer.
+func (v *version) ModelConverters() ModelConverterInformer {
+	return &modelConverterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ModelJobs returns a ModelJobInformer.
 func (v *version) ModelJobs() ModelJobInformer {
 	return &modelJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ModelPipelines returns a ModelPipelineInformer.
+func (v *version) ModelPipelines() ModelPipelineInformer {
+	return &modelPipelineInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -15,6 +15,10 @@ limitations under the License.
 
 package v1alpha1
 
+// ModelConverterListerExpansion allows custom methods to be added to
+// ModelConverterLister.
+type ModelConverterListerExpansion interface{}
+
 // ModelJobListerExpansion allows custom methods to be added to
 // ModelJobLister.
 type ModelJobListerExpansion interface{}
@@ -22,3 +26,11 @@ type ModelJobListerExpansion interface{}
 // ModelJobNamespaceListerExpansion allows custom methods to be added to
 // ModelJobNamespaceLister.
 type ModelJobNamespaceListerExpansion interface{}
+
+// ModelPipelineListerExpansion allows custom methods to be added to
+// ModelPipelineLister.
+type ModelPipelineListerExpansion interface{}
+
+// ModelPipelineNamespaceListerExpansion allows custom methods to be added to
+// ModelPipelineNamespaceLister.
+type ModelPipelineNamespaceListerExpansion interface{}
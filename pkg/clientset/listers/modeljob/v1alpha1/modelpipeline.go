@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ModelPipelineLister helps list ModelPipelines.
+type ModelPipelineLister interface {
+	// List lists all ModelPipelines in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.ModelPipeline, err error)
+	// ModelPipelines returns an object that can list and get ModelPipelines.
+	ModelPipelines(namespace string) ModelPipelineNamespaceLister
+	ModelPipelineListerExpansion
+}
+
+// modelPipelineLister implements the ModelPipelineLister interface.
+type modelPipelineLister struct {
+	indexer cache.Indexer
+}
+
+// NewModelPipelineLister returns a new ModelPipelineLister.
+func NewModelPipelineLister(indexer cache.Indexer) ModelPipelineLister {
+	return &modelPipelineLister{indexer: indexer}
+}
+
+// List lists all ModelPipelines in the indexer.
+func (s *modelPipelineLister) List(selector labels.Selector) (ret []*v1alpha1.ModelPipeline, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ModelPipeline))
+	})
+	return ret, err
+}
+
+// ModelPipelines returns an object that can list and get ModelPipelines.
+func (s *modelPipelineLister) ModelPipelines(namespace string) ModelPipelineNamespaceLister {
+	return modelPipelineNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ModelPipelineNamespaceLister helps list and get ModelPipelines.
+type ModelPipelineNamespaceLister interface {
+	// List lists all ModelPipelines in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.ModelPipeline, err error)
+	// Get retrieves the ModelPipeline from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.ModelPipeline, error)
+	ModelPipelineNamespaceListerExpansion
+}
+
+// modelPipelineNamespaceLister implements the ModelPipelineNamespaceLister
+// interface.
+type modelPipelineNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ModelPipelines in the indexer for a given namespace.
+func (s modelPipelineNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ModelPipeline, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ModelPipeline))
+	})
+	return ret, err
+}
+
+// Get retrieves the ModelPipeline from the indexer for a given namespace and name.
+func (s modelPipelineNamespaceLister) Get(name string) (*v1alpha1.ModelPipeline, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("modelpipeline"), name)
+	}
+	return obj.(*v1alpha1.ModelPipeline), nil
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"github.com/spf13/viper"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+const (
+	// TRTServingImageEnvKey is the preset image for tritonserver.
+	TRTServingImageEnvKey = "TRT_SERVING_IMAGE"
+	// PMMLServingImageEnvKey is the preset image for pmml.
+	PMMLServingImageEnvKey = "PMML_SERVING_IMAGE"
+	// MLServerImageEnvKey is the preset image for mlserver.
+	MLServerImageEnvKey = "MLSERVER_IMAGE"
+)
+
+// ServingImageEnvKey returns the env key of the serving runtime image of the model format,
+// it is shared by model serving and the benchmark of modeljob.
+func ServingImageEnvKey(format string) string {
+	// Group1 for PMML image
+	if format == string(modeljobsv1alpha1.FormatPMML) {
+		return PMMLServingImageEnvKey
+	}
+	// Group2 for mlserver image
+	if IsMLServerModel(format) {
+		return MLServerImageEnvKey
+	}
+
+	// Group3 for default TRT server image
+	return TRTServingImageEnvKey
+}
+
+// GetServingImage returns the serving runtime image of the model format.
+func GetServingImage(format string) string {
+	return viper.GetString(ServingImageEnvKey(format))
+}
+
+// IsMLServerModel returns true if the model format is served by mlserver.
+func IsMLServerModel(format string) bool {
+	if format == string(modeljobsv1alpha1.FormatSKLearn) || format == string(modeljobsv1alpha1.FormatXGBoost) || format == string(modeljobsv1alpha1.FormatMLlib) {
+		return true
+	}
+	return false
+}
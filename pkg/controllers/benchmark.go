@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/common"
+)
+
+const (
+	defaultBenchmarkRequests       = 100
+	defaultBenchmarkWarmupRequests = 10
+	// maxBenchmarkCases is the max number of cases of benchmark, the results of all cases are reported
+	// by the termination message of executor which is limited to 4096 bytes.
+	maxBenchmarkCases = 10
+)
+
+// benchmarkFormats is the formats which can be benchmarked on CPU in the serving runtime images.
+var benchmarkFormats = []modeljobsv1alpha1.Format{
+	modeljobsv1alpha1.FormatSavedModel,
+	modeljobsv1alpha1.FormatONNX,
+	modeljobsv1alpha1.FormatGraphDef,
+	modeljobsv1alpha1.FormatTorchScript,
+	modeljobsv1alpha1.FormatNetDef,
+	modeljobsv1alpha1.FormatPMML,
+	modeljobsv1alpha1.FormatSKLearn,
+	modeljobsv1alpha1.FormatXGBoost,
+	modeljobsv1alpha1.FormatMLlib,
+}
+
+func init() {
+	// The benchmark runs in the serving runtime image of the format, which is shared with model serving.
+	for _, format := range benchmarkFormats {
+		presetImage[strings.ToLower(string(format))+"-benchmark"] = common.ServingImageEnvKey(string(format))
+	}
+}
+
+// generateBenchmarkEnv generates the env of executor for the benchmark source.
+func generateBenchmarkEnv(benchmark *modeljobsv1alpha1.BenchmarkSource) []corev1.EnvVar {
+	if benchmark == nil {
+		return nil
+	}
+
+	requests := int32(defaultBenchmarkRequests)
+	if benchmark.Requests != nil {
+		requests = *benchmark.Requests
+	}
+	warmupRequests := int32(defaultBenchmarkWarmupRequests)
+	if benchmark.WarmupRequests != nil {
+		warmupRequests = *benchmark.WarmupRequests
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  modeljobsv1alpha1.BenchmarkBatchSizesEnvKey,
+			Value: joinInt32s(benchmark.BatchSizes),
+		},
+		{
+			Name:  modeljobsv1alpha1.BenchmarkConcurrencyEnvKey,
+			Value: joinInt32s(benchmark.Concurrency),
+		},
+		{
+			Name:  modeljobsv1alpha1.BenchmarkRequestsEnvKey,
+			Value: strconv.Itoa(int(requests)),
+		},
+		{
+			Name:  modeljobsv1alpha1.BenchmarkWarmupRequestsEnvKey,
+			Value: strconv.Itoa(int(warmupRequests)),
+		},
+		// The serving runtime loads the model from the model store, the name of model is the name of its directory.
+		{
+			Name:  "MODEL_STORE",
+			Value: path.Dir(modeljobsv1alpha1.SourceModelPath),
+		},
+		{
+			Name:  "SERVING_NAME",
+			Value: path.Base(modeljobsv1alpha1.SourceModelPath),
+		},
+		// The benchmark runs on CPU even if the node has GPUs.
+		{
+			Name:  "NVIDIA_VISIBLE_DEVICES",
+			Value: "",
+		},
+	}
+}
+
+// joinInt32s joins the values by comma, it returns "1" if there is no value.
+func joinInt32s(values []int32) string {
+	if len(values) == 0 {
+		return "1"
+	}
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		items = append(items, strconv.Itoa(int(v)))
+	}
+	return strings.Join(items, ",")
+}
+
+// validateBenchmarkParameters validates the batch sizes, concurrency and requests of benchmark source.
+func validateBenchmarkParameters(benchmark *modeljobsv1alpha1.BenchmarkSource, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	batchSizesPath := fldPath.Child("batchSizes")
+	for i, batchSize := range benchmark.BatchSizes {
+		if batchSize <= 0 {
+			errs = append(errs, field.Invalid(batchSizesPath.Index(i), batchSize, "must be greater than 0"))
+		} else if benchmark.Format == modeljobsv1alpha1.FormatPMML && batchSize != 1 {
+			errs = append(errs, field.Invalid(batchSizesPath.Index(i), batchSize, "must be 1 for PMML"))
+		}
+	}
+	for i, concurrency := range benchmark.Concurrency {
+		if concurrency <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("concurrency").Index(i), concurrency, "must be greater than 0"))
+		}
+	}
+	if cases := maxInt(len(benchmark.BatchSizes), 1) * maxInt(len(benchmark.Concurrency), 1); cases > maxBenchmarkCases {
+		errs = append(errs, field.TooMany(fldPath, cases, maxBenchmarkCases))
+	}
+
+	if benchmark.Requests != nil && *benchmark.Requests <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("requests"), *benchmark.Requests, "must be greater than 0"))
+	}
+	if benchmark.WarmupRequests != nil && *benchmark.WarmupRequests < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("warmupRequests"), *benchmark.WarmupRequests,
+			"must be greater than or equal to 0"))
+	}
+
+	return errs
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
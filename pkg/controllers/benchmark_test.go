@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/common"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_generateJobResource_benchmark(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+	viper.Set(common.TRTServingImageEnvKey, "demo.goharbor.com/release/tritonserver:v0.3.0")
+	defer viper.Set(common.TRTServingImageEnvKey, "")
+
+	requests := int32(200)
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model: "release/onnx:v1",
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Benchmark: &modeljobsv1alpha1.BenchmarkSource{
+					Format:      modeljobsv1alpha1.FormatONNX,
+					BatchSizes:  []int32{1, 8},
+					Concurrency: []int32{4},
+					Requests:    &requests,
+				},
+			},
+		},
+	}
+	job, err := generateJobResource(modeljob, nil, "")
+	if err != nil {
+		t.Fatalf("generateJobResource() error = %v", err)
+	}
+
+	envs := map[string]string{}
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		envs[env.Name] = env.Value
+	}
+	for name, want := range map[string]string{
+		modeljobsv1alpha1.TaskEnvKey:                    "benchmark",
+		modeljobsv1alpha1.DestinationModelTagEnvKey:     "empty",
+		modeljobsv1alpha1.BenchmarkBatchSizesEnvKey:     "1,8",
+		modeljobsv1alpha1.BenchmarkConcurrencyEnvKey:    "4",
+		modeljobsv1alpha1.BenchmarkRequestsEnvKey:       "200",
+		modeljobsv1alpha1.BenchmarkWarmupRequestsEnvKey: "10",
+		"MODEL_STORE":  "/models",
+		"SERVING_NAME": "input",
+	} {
+		if envs[name] != want {
+			t.Errorf("generateJobResource() env %v = %v, want %v", name, envs[name], want)
+		}
+	}
+	if got := job.Spec.Template.Spec.Containers[0].Image; got != "demo.goharbor.com/release/tritonserver:v0.3.0" {
+		t.Errorf("generateJobResource() image = %v, want the serving image", got)
+	}
+}
+
+func Test_validateBenchmarkParameters(t *testing.T) {
+	negative := int32(-1)
+	tests := []struct {
+		name      string
+		benchmark *modeljobsv1alpha1.BenchmarkSource
+		wantErrs  []string
+	}{
+		{
+			name:      "defaults",
+			benchmark: &modeljobsv1alpha1.BenchmarkSource{Format: modeljobsv1alpha1.FormatSavedModel},
+		},
+		{
+			name: "too many cases",
+			benchmark: &modeljobsv1alpha1.BenchmarkSource{
+				Format:      modeljobsv1alpha1.FormatSavedModel,
+				BatchSizes:  []int32{1, 2, 4, 8},
+				Concurrency: []int32{1, 2, 4},
+			},
+			wantErrs: []string{"benchmark"},
+		},
+		{
+			name: "negative warmup requests",
+			benchmark: &modeljobsv1alpha1.BenchmarkSource{
+				Format:         modeljobsv1alpha1.FormatSKLearn,
+				WarmupRequests: &negative,
+			},
+			wantErrs: []string{"benchmark.warmupRequests"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateBenchmarkParameters(tt.benchmark, field.NewPath("benchmark"))
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateBenchmarkParameters() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateBenchmarkParameters() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
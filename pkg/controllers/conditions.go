@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// defines the reason of modeljob conditions
+const (
+	conditionReasonUnschedulable     = "Unschedulable"
+	conditionReasonScheduled         = "Scheduled"
+	conditionReasonContainersNotDone = "ContainersNotCompleted"
+	conditionReasonInitialized       = "Initialized"
+	conditionReasonModelPulled       = "ModelPulled"
+	conditionReasonModelPullFailed   = "ModelPullFailed"
+	conditionReasonTaskCompleted     = "TaskCompleted"
+	conditionReasonTaskFailed        = "TaskFailed"
+	conditionReasonModelPushed       = "ModelPushed"
+	conditionReasonModelPushFailed   = "ModelPushFailed"
+)
+
+// getModelJobCondition returns the condition with the provided type.
+func getModelJobCondition(status *modeljobsv1alpha1.ModelJobStatus,
+	condType modeljobsv1alpha1.ModelJobConditionType) *modeljobsv1alpha1.ModelJobCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isModelJobConditionTrue returns true if the condition with the provided type is true.
+func isModelJobConditionTrue(status *modeljobsv1alpha1.ModelJobStatus,
+	condType modeljobsv1alpha1.ModelJobConditionType) bool {
+	cond := getModelJobCondition(status, condType)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// setModelJobCondition updates the condition with the provided type, LastTransitionTime
+// only changes when the status of condition changes.
+func setModelJobCondition(status *modeljobsv1alpha1.ModelJobStatus, condType modeljobsv1alpha1.ModelJobConditionType,
+	condStatus corev1.ConditionStatus, reason, message string) {
+	cond := getModelJobCondition(status, condType)
+	if cond == nil {
+		status.Conditions = append(status.Conditions, modeljobsv1alpha1.ModelJobCondition{
+			Type:               condType,
+			Status:             condStatus,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            message,
+		})
+		return
+	}
+
+	if cond.Status != condStatus {
+		cond.LastTransitionTime = metav1.Now()
+	}
+	cond.Status = condStatus
+	cond.Reason = reason
+	cond.Message = message
+}
+
+// setModelJobConditionsByPods derives the stage conditions of modeljob from the pod of its job.
+func setModelJobConditionsByPods(status *modeljobsv1alpha1.ModelJobStatus, pods *corev1.PodList) {
+	if len(pods.Items) == 0 {
+		return
+	}
+	pod := &pods.Items[0]
+
+	for _, c := range pod.Status.Conditions {
+		switch c.Type {
+		case corev1.PodScheduled:
+			reason := conditionReasonScheduled
+			if c.Status != corev1.ConditionTrue {
+				reason = conditionReasonUnschedulable
+			}
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobScheduled, c.Status, reason, c.Message)
+		case corev1.PodInitialized:
+			reason := conditionReasonInitialized
+			if c.Status != corev1.ConditionTrue {
+				reason = conditionReasonContainersNotDone
+			}
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobInitialized, c.Status, reason, c.Message)
+		}
+	}
+
+	// The model is pulled by init container, so the model is pulled when all init containers complete.
+	pulled := len(pod.Status.InitContainerStatuses) != 0
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.State.Terminated == nil {
+			pulled = false
+			continue
+		}
+		if cs.State.Terminated.ExitCode != 0 {
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPulled, corev1.ConditionFalse,
+				conditionReasonModelPullFailed, errORMBPull)
+			return
+		}
+	}
+	if pulled {
+		setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPulled, corev1.ConditionTrue,
+			conditionReasonModelPulled, "")
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		// The running executor is ready when the task completed and it pushes the model.
+		if cs.Name == executorContainerName && cs.State.Running != nil && cs.Ready {
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionTrue,
+				conditionReasonTaskCompleted, "")
+			continue
+		}
+		if cs.State.Terminated == nil {
+			continue
+		}
+		switch cs.State.Terminated.ExitCode {
+		case Success:
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionTrue,
+				conditionReasonTaskCompleted, "")
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPushed, corev1.ConditionTrue,
+				conditionReasonModelPushed, "")
+		case ErrORMBPullModel, ErrORMBExportModel:
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPulled, corev1.ConditionFalse,
+				conditionReasonModelPullFailed, errORMBPull)
+		case ErrORMBSaveModel:
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionTrue,
+				conditionReasonTaskCompleted, "")
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPushed, corev1.ConditionFalse,
+				conditionReasonModelPushFailed, errORMBSave)
+		case ErrORMBPushModel:
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionTrue,
+				conditionReasonTaskCompleted, "")
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPushed, corev1.ConditionFalse,
+				conditionReasonModelPushFailed, errORMBPush)
+		default:
+			message := errRunTask
+			switch cs.State.Terminated.ExitCode {
+			case ErrORMBLogin:
+				message = errORMBLogin
+			case ErrValidationFailed:
+				message = errValidationFailed
+			}
+			setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionFalse,
+				conditionReasonTaskFailed, message)
+		}
+	}
+}
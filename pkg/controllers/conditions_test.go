@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_setModelJobConditionsByPods(t *testing.T) {
+	newPod := func(initExitCode, exitCode int32) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodScheduled,
+						Status: corev1.ConditionTrue,
+					},
+				},
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: initExitCode,
+							},
+						},
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: exitCode,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		pods *corev1.PodList
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus
+	}{
+		{
+			name: "no pods",
+			args: args{
+				pods: &corev1.PodList{},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{},
+		},
+		{
+			name: "unschedulable",
+			args: args{
+				pods: &corev1.PodList{
+					Items: []corev1.Pod{
+						{
+							Status: corev1.PodStatus{
+								Conditions: []corev1.PodCondition{
+									{
+										Type:   corev1.PodScheduled,
+										Status: corev1.ConditionFalse,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{
+				modeljobsv1alpha1.ModelJobScheduled: corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "pull model error",
+			args: args{
+				pods: &corev1.PodList{
+					Items: []corev1.Pod{newPod(1, 0)},
+				},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{
+				modeljobsv1alpha1.ModelJobScheduled:   corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPulled: corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "run task error",
+			args: args{
+				pods: &corev1.PodList{
+					Items: []corev1.Pod{newPod(0, ErrRunTask)},
+				},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{
+				modeljobsv1alpha1.ModelJobScheduled:     corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPulled:   corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobTaskCompleted: corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "push model error",
+			args: args{
+				pods: &corev1.PodList{
+					Items: []corev1.Pod{newPod(0, ErrORMBPushModel)},
+				},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{
+				modeljobsv1alpha1.ModelJobScheduled:     corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPulled:   corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobTaskCompleted: corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPushed:   corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "succeeded",
+			args: args{
+				pods: &corev1.PodList{
+					Items: []corev1.Pod{newPod(0, Success)},
+				},
+			},
+			want: map[modeljobsv1alpha1.ModelJobConditionType]corev1.ConditionStatus{
+				modeljobsv1alpha1.ModelJobScheduled:     corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPulled:   corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobTaskCompleted: corev1.ConditionTrue,
+				modeljobsv1alpha1.ModelJobModelPushed:   corev1.ConditionTrue,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &modeljobsv1alpha1.ModelJobStatus{}
+			setModelJobConditionsByPods(status, tt.args.pods)
+			if len(status.Conditions) != len(tt.want) {
+				t.Errorf("setModelJobConditionsByPods() = %v, want %v", status.Conditions, tt.want)
+				return
+			}
+			for condType, condStatus := range tt.want {
+				cond := getModelJobCondition(status, condType)
+				if cond == nil || cond.Status != condStatus {
+					t.Errorf("setModelJobConditionsByPods() condition %v = %v, want %v", condType, cond, condStatus)
+				}
+			}
+		})
+	}
+}
+
+func Test_setModelJobCondition(t *testing.T) {
+	status := &modeljobsv1alpha1.ModelJobStatus{}
+	setModelJobCondition(status, modeljobsv1alpha1.ModelJobScheduled, corev1.ConditionFalse, conditionReasonUnschedulable, "")
+	transitionTime := status.Conditions[0].LastTransitionTime
+
+	setModelJobCondition(status, modeljobsv1alpha1.ModelJobScheduled, corev1.ConditionFalse, conditionReasonUnschedulable, "insufficient cpu")
+	if len(status.Conditions) != 1 {
+		t.Errorf("setModelJobCondition() conditions = %v, want 1 condition", status.Conditions)
+	}
+	if !status.Conditions[0].LastTransitionTime.Equal(&transitionTime) {
+		t.Errorf("setModelJobCondition() changed LastTransitionTime without status transition")
+	}
+	if status.Conditions[0].Message != "insufficient cpu" {
+		t.Errorf("setModelJobCondition() message = %v, want %v", status.Conditions[0].Message, "insufficient cpu")
+	}
+
+	setModelJobCondition(status, modeljobsv1alpha1.ModelJobScheduled, corev1.ConditionTrue, conditionReasonScheduled, "")
+	if !isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobScheduled) {
+		t.Errorf("setModelJobCondition() condition = %v, want true", status.Conditions[0])
+	}
+}
@@ -17,21 +17,25 @@ const (
 	ReasonCrashLoopBackOff = "CrashLoopBackOff"
 	// ReasonContainerCreating ContainerCreating.
 	ReasonContainerCreating = "ContainerCreating"
+	// ReasonEvicted is the reason of the pod evicted by kubelet.
+	ReasonEvicted = "Evicted"
 )
 
 // defines the error message
 const (
-	errPullImage             = "failed to pull image"
-	errContainerCreating     = "container is creating"
-	errContainerOutOfMemory  = "container out of memory"
-	errContainerImageInvalid = "container image invalid"
-	errContainerCrashed      = "container crashed"
-	errORMBPull              = "failed to pull model"
-	errORMBLogin             = "failed to login model registry"
-	errORMBSave              = "failed to save model to localhost"
-	errORMBPush              = "failed to push model to model registry"
-	errORMBExport            = "failed to export model to local"
-	errRunTask               = "failed to run extract/convert task"
+	errPullImage               = "failed to pull image"
+	errContainerCreating       = "container is creating"
+	errContainerOutOfMemory    = "container out of memory"
+	errContainerImageInvalid   = "container image invalid"
+	errContainerCrashed        = "container crashed"
+	errORMBPull                = "failed to pull model"
+	errORMBLogin               = "failed to login model registry"
+	errORMBSave                = "failed to save model to localhost"
+	errORMBPush                = "failed to push model to model registry"
+	errORMBExport              = "failed to export model to local"
+	errRunTask                 = "failed to run extract/convert task"
+	errValidationFailed        = "model validation failed"
+	errEphemeralStorageEvicted = "pod evicted for ephemeral storage"
 )
 
 var (
@@ -54,6 +58,8 @@ var (
 	ModelJobWorkDir = "/models"
 
 	// ModelInitializerCPUEnvKey defines the cpu env key for model initializer container.
+	// The resources env of modeljob are deprecated by spec.resources and spec.initializerResources,
+	// the env of operator Deployment are still the default resources of modeljobs.
 	ModelInitializerCPUEnvKey = "MODEL_INITIALIZER_CPU"
 	// ModelInitializerMEMEnvKey defines the mem env key for model initializer container.
 	ModelInitializerMEMEnvKey = "MODEL_INITIALIZER_MEM"
@@ -62,10 +68,59 @@ var (
 	// ModelJobTaskMEMEnvKey defines the mem env key for modeljob task container.
 	ModelJobTaskMEMEnvKey = "MODELJOB_TASK_MEM"
 
+	// ModelJobTTLSecondsAfterFinishedEnvKey is the env key for the default ttl of finished modeljobs, it is set in Deployment.
+	ModelJobTTLSecondsAfterFinishedEnvKey = "MODELJOB_TTL_SECONDS_AFTER_FINISHED"
+	// ModelJobHistoryLimitEnvKey is the env key for the number of finished modeljobs kept for each model
+	// even if their ttl expired, it is set in Deployment.
+	ModelJobHistoryLimitEnvKey = "MODELJOB_HISTORY_LIMIT"
+
+	// ModelJobActiveDeadlineSecondsEnvKey is the env key for the default active deadline of modeljobs, it is set in Deployment.
+	// The default of each format is set by the env key like SAVEDMODEL_EXTRACT_ACTIVE_DEADLINE_SECONDS.
+	ModelJobActiveDeadlineSecondsEnvKey = "MODELJOB_ACTIVE_DEADLINE_SECONDS"
+	// ActiveDeadlineSecondsEnvKeySuffix is the suffix of env key for the default active deadline of each format.
+	ActiveDeadlineSecondsEnvKeySuffix = "_ACTIVE_DEADLINE_SECONDS"
+
+	// ModelJobRegistryCredentialsFallbackEnvKey is the env key to use the account of operator for the modeljobs
+	// without spec.registryCredentials, it is set in Deployment.
+	ModelJobRegistryCredentialsFallbackEnvKey = "MODELJOB_REGISTRY_CREDENTIALS_FALLBACK"
+
+	// ModelJobExecutorImageAllowlistEnvKey is the env key for the comma-separated registries and prefixes of the
+	// images of spec.executor, it is set in Deployment. spec.executor is rejected if it is empty.
+	ModelJobExecutorImageAllowlistEnvKey = "MODELJOB_EXECUTOR_IMAGE_ALLOWLIST"
+
+	// ModelJobMaxConcurrentJobsEnvKey is the env key for the limit of concurrent jobs of all modeljobs,
+	// it is set in Deployment, zero means no limit.
+	ModelJobMaxConcurrentJobsEnvKey = "MODELJOB_MAX_CONCURRENT_JOBS"
+	// ModelJobMaxConcurrentJobsPerNamespaceEnvKey is the env key for the limit of concurrent jobs of modeljobs
+	// in each namespace, it is set in Deployment, zero means no limit.
+	ModelJobMaxConcurrentJobsPerNamespaceEnvKey = "MODELJOB_MAX_CONCURRENT_JOBS_PER_NAMESPACE"
+
+	// ModelJobWebhookServiceNameEnvKey is the env key for the name of the Service of the webhooks of operator,
+	// it is set in Deployment, the conversion webhook of modeljobs is called by it.
+	ModelJobWebhookServiceNameEnvKey = "MODELJOB_WEBHOOK_SERVICE_NAME"
+	// ModelJobWebhookServiceNamespaceEnvKey is the env key for the namespace of the Service of the webhooks
+	// of operator, it is set in Deployment.
+	ModelJobWebhookServiceNamespaceEnvKey = "MODELJOB_WEBHOOK_SERVICE_NAMESPACE"
+
 	ModelJobReasonPending      = "Pending"
 	ModelJobReasonStartRunning = "StartRunning"
 	ModelJobReasonSucceded     = "Succeded"
 	ModelJobReasonFailed       = "Failed"
+	ModelJobReasonRetrying     = "Retrying"
+	ModelJobReasonTimeout      = "Timeout"
+	ModelJobReasonQueued       = "Queued"
+	// ModelJobReasonInvalidResult is the event reason when the result of executor can not be parsed.
+	ModelJobReasonInvalidResult = "InvalidResult"
+	ModelJobReasonSuspended     = "Suspended"
+	ModelJobReasonResumed       = "Resumed"
+	ModelJobReasonCancelled     = "Cancelled"
+	// ModelJobReasonExecutorImageNotAllowed is the reason when the image of spec.executor is not in the allowlist.
+	ModelJobReasonExecutorImageNotAllowed = "ExecutorImageNotAllowed"
+	// ModelJobReasonDeprecatedEnv is the event reason when the modeljob sets resources by the deprecated env.
+	ModelJobReasonDeprecatedEnv = "DeprecatedEnv"
+	// ModelJobReasonRegistryCredentialsNotFound is the reason when the Secret of spec.registryCredentials is not found,
+	// the modeljob does not wait in the queue until it is created.
+	ModelJobReasonRegistryCredentialsNotFound = "RegistryCredentialsNotFound"
 )
 
 var presetImage = map[string]string{
@@ -78,9 +133,17 @@ var presetImage = map[string]string{
 	"savedmodel-extract":  "SAVEDMODEL_EXTRACT_IMAGE",
 	"torchscript-extract": "TORCHSCRIPT_EXTRACT_IMAGE",
 	"pmml-extract":        "PMML_EXTRACT_IMAGE",
-	"caffemodel-convert":  "CAFFE_CONVERSION_IMAGE",
-	"mxnetparams-convert": "MXNET_CONVERSION_IMAGE",
-	"h5-convert":          "H5_CONVERSION_IMAGE",
-	"netdef-convert":      "NETDEF_CONVERSION_IMAGE",
-	"initializer":         "ORMB_INITIALIZER_IMAGE",
+	// The extraction images validate the models of their formats too.
+	"h5-validate":          "H5_EXTRACT_IMAGE",
+	"onnx-validate":        "ONNX_EXTRACT_IMAGE",
+	"savedmodel-validate":  "SAVEDMODEL_EXTRACT_IMAGE",
+	"torchscript-validate": "TORCHSCRIPT_EXTRACT_IMAGE",
+	"caffemodel-convert":   "CAFFE_CONVERSION_IMAGE",
+	"mxnetparams-convert":  "MXNET_CONVERSION_IMAGE",
+	"h5-convert":           "H5_CONVERSION_IMAGE",
+	"netdef-convert":       "NETDEF_CONVERSION_IMAGE",
+	"onnx-optimize":        "ONNX_OPTIMIZATION_IMAGE",
+	"savedmodel-optimize":  "SAVEDMODEL_OPTIMIZATION_IMAGE",
+	"initializer":          "ORMB_INITIALIZER_IMAGE",
+	"source-initializer":   "MODEL_SOURCE_INITIALIZER_IMAGE",
 }
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobsv1beta1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1beta1"
+)
+
+const (
+	// ModelJobConversionWebhookPath is the path of modeljob conversion webhook.
+	ModelJobConversionWebhookPath = "/convert"
+	// ModelJobCRDName is the name of the CustomResourceDefinition of modeljob.
+	ModelJobCRDName = "modeljobs." + modeljobsv1alpha1.GroupName
+
+	// webhookCAName is the CA of the serving certificates, the apiserver verifies the conversion webhook by it.
+	webhookCAName = "ca.crt"
+)
+
+// webhookServiceCertDir is the directory of the serving certificates of webhook server.
+var webhookServiceCertDir = "/tmp/k8s-webhook-server/serving-certs"
+
+// SetModelJobCRDConversion configures the versions and conversion of the CustomResourceDefinition of modeljob.
+// The crd yaml serves and stores only v1alpha1, so that it can be installed as it is without the webhook.
+// v1beta1 is served and stored by the conversion webhook of operator if the webhook is enabled, otherwise only
+// v1alpha1 is served and stored, since the objects can not be converted between them without the webhook.
+// storedVersions is status.storedVersions of the installed crd, the webhook can not be disabled if the objects
+// may be stored in v1beta1, they could not be read as v1alpha1 without it.
+func SetModelJobCRDConversion(crd *apiextensionsv1.CustomResourceDefinition, storedVersions []string, enableWebhook bool) error {
+	if crd.Name != ModelJobCRDName {
+		return nil
+	}
+
+	if !enableWebhook {
+		for _, version := range storedVersions {
+			if version != modeljobsv1alpha1.GroupVersion.Version {
+				return fmt.Errorf("the webhook of modeljob must be enabled, since the modeljobs may be stored in %v "+
+					"(status.storedVersions of crd %v: %v)", version, crd.Name, storedVersions)
+			}
+		}
+		for i := range crd.Spec.Versions {
+			version := &crd.Spec.Versions[i]
+			version.Served = version.Name == modeljobsv1alpha1.GroupVersion.Version
+			version.Storage = version.Served
+		}
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+		return nil
+	}
+
+	name := viper.GetString(ModelJobWebhookServiceNameEnvKey)
+	namespace := viper.GetString(ModelJobWebhookServiceNamespaceEnvKey)
+	if name == "" || namespace == "" {
+		return fmt.Errorf("%v and %v must be set for the conversion webhook of modeljob",
+			ModelJobWebhookServiceNameEnvKey, ModelJobWebhookServiceNamespaceEnvKey)
+	}
+	caBundle, err := ioutil.ReadFile(filepath.Join(webhookServiceCertDir, webhookCAName))
+	if err != nil {
+		return fmt.Errorf("failed to read the CA of webhook: %w", err)
+	}
+
+	path := ModelJobConversionWebhookPath
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: namespace,
+					Name:      name,
+					Path:      &path,
+				},
+				CABundle: caBundle,
+			},
+			// The conversion webhook of controller-runtime only handles the v1beta1 ConversionReview.
+			ConversionReviewVersions: []string{"v1beta1"},
+		},
+	}
+	for i := range crd.Spec.Versions {
+		version := &crd.Spec.Versions[i]
+		version.Served = true
+		version.Storage = version.Name == modeljobsv1beta1.GroupVersion.Version
+	}
+	return nil
+}
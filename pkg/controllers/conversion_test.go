@@ -0,0 +1,468 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/util/retry"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobsv1beta1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1beta1"
+)
+
+func Test_ModelJobConversion(t *testing.T) {
+	desiredTag := "release/resnet:v1-savedmodel"
+	targetOpset := int32(13)
+	requests := int32(200)
+	resources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+	}
+	newModelJob := func(source modeljobsv1alpha1.ModelJobSource) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet"},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:          "release/resnet:v1",
+				ModelJobSource: source,
+			},
+			Status: modeljobsv1alpha1.ModelJobStatus{Phase: modeljobsv1alpha1.ModelJobSucceeded},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modeljob func() *modeljobsv1alpha1.ModelJob
+		wantType modeljobsv1beta1.ModelJobType
+	}{
+		{
+			name: "extraction from s3",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				})
+				modeljob.Spec.Source = &modeljobsv1alpha1.ModelSource{
+					S3: &modeljobsv1alpha1.S3ModelSource{Bucket: "models", Key: "resnet/"},
+				}
+				modeljob.Spec.RegistryCredentials = &modeljobsv1alpha1.RegistryCredentials{SecretName: "harbor"}
+				return modeljob
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeExtraction,
+		},
+		{
+			name: "conversion",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatH5,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				})
+				modeljob.Spec.DesiredTag = &desiredTag
+				modeljob.Spec.Resources = resources
+				modeljob.Spec.InitContainer = []corev1.Container{{Name: "pull", Image: "busybox"}}
+				return modeljob
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeConversion,
+		},
+		{
+			name: "conversion without mmdnn",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				return newModelJob(modeljobsv1alpha1.ModelJobSource{Conversion: &modeljobsv1alpha1.ConversionSource{}})
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeConversion,
+		},
+		{
+			name: "conversion with empty mmdnn",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				return newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{MMdnn: &modeljobsv1alpha1.MMdnnSpec{}},
+				})
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeConversion,
+		},
+		{
+			name: "extraction and validation",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatONNX},
+					Validation: &modeljobsv1alpha1.ValidationSource{Format: modeljobsv1alpha1.FormatONNX},
+				})
+				emptyTag := ""
+				modeljob.Spec.DesiredTag = &emptyTag
+				return modeljob
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeExtraction,
+		},
+		{
+			name: "validation",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				return newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Validation: &modeljobsv1alpha1.ValidationSource{Format: modeljobsv1alpha1.FormatONNX, Tolerance: "1e-4"},
+				})
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeValidation,
+		},
+		{
+			name: "optimization",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Optimization: &modeljobsv1alpha1.OptimizationSource{
+						Format:        modeljobsv1alpha1.FormatONNX,
+						Optimizations: []modeljobsv1alpha1.OptimizationType{modeljobsv1alpha1.OptimizationUpgradeOpset},
+						TargetOpset:   &targetOpset,
+					},
+				})
+				modeljob.Spec.DesiredTag = &desiredTag
+				return modeljob
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeOptimization,
+		},
+		{
+			name: "benchmark",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Benchmark: &modeljobsv1alpha1.BenchmarkSource{Format: modeljobsv1alpha1.FormatPMML, Requests: &requests},
+				})
+				modeljob.Spec.InitializerResources = resources
+				return modeljob
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeBenchmark,
+		},
+		{
+			name: "benchmark without parameters",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				return newModelJob(modeljobsv1alpha1.ModelJobSource{
+					Benchmark: &modeljobsv1alpha1.BenchmarkSource{Format: modeljobsv1alpha1.FormatPMML},
+				})
+			},
+			wantType: modeljobsv1beta1.ModelJobTypeBenchmark,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modeljob := tt.modeljob()
+
+			beta := &modeljobsv1beta1.ModelJob{}
+			if err := beta.ConvertFrom(modeljob.DeepCopy()); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if beta.Spec.Type != tt.wantType {
+				t.Errorf("ConvertFrom() type = %v, want %v", beta.Spec.Type, tt.wantType)
+			}
+			if beta.Spec.Source.Model != modeljob.Spec.Model {
+				t.Errorf("ConvertFrom() source model = %v, want %v", beta.Spec.Source.Model, modeljob.Spec.Model)
+			}
+
+			alpha := &modeljobsv1alpha1.ModelJob{}
+			if err := beta.DeepCopy().ConvertTo(alpha); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(alpha, modeljob) {
+				t.Errorf("ConvertTo() = %+v, want %+v", alpha.Spec, modeljob.Spec)
+			}
+
+			roundTrip := &modeljobsv1beta1.ModelJob{}
+			if err := roundTrip.ConvertFrom(alpha); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(roundTrip, beta) {
+				t.Errorf("ConvertFrom() = %+v, want %+v", roundTrip.Spec, beta.Spec)
+			}
+		})
+	}
+}
+
+func Test_ModelJobConversion_v1beta1(t *testing.T) {
+	newModelJob := func(modelJobType modeljobsv1beta1.ModelJobType, destination *modeljobsv1beta1.ModelJobDestination) *modeljobsv1beta1.ModelJob {
+		return &modeljobsv1beta1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet"},
+			Spec: modeljobsv1beta1.ModelJobSpec{
+				Type:        modelJobType,
+				Source:      modeljobsv1beta1.ModelJobSource{Model: "release/resnet:v1", Format: modeljobsv1alpha1.FormatH5},
+				Destination: destination,
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		modeljob       *modeljobsv1beta1.ModelJob
+		wantAnnotation bool
+	}{
+		{
+			name:     "conversion",
+			modeljob: newModelJob(modeljobsv1beta1.ModelJobTypeConversion, &modeljobsv1beta1.ModelJobDestination{Model: "release/resnet:v2", Format: modeljobsv1alpha1.FormatSavedModel}),
+		},
+		{
+			name:           "extraction with destination format",
+			modeljob:       newModelJob(modeljobsv1beta1.ModelJobTypeExtraction, &modeljobsv1beta1.ModelJobDestination{Model: "release/resnet:v2", Format: modeljobsv1alpha1.FormatSavedModel}),
+			wantAnnotation: true,
+		},
+		{
+			name:           "validation with empty destination",
+			modeljob:       newModelJob(modeljobsv1beta1.ModelJobTypeValidation, &modeljobsv1beta1.ModelJobDestination{}),
+			wantAnnotation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alpha := &modeljobsv1alpha1.ModelJob{}
+			if err := tt.modeljob.DeepCopy().ConvertTo(alpha); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if _, ok := alpha.Annotations[modeljobsv1beta1.V1beta1SpecAnnotationKey]; ok != tt.wantAnnotation {
+				t.Errorf("ConvertTo() annotations = %v, want the annotation of v1beta1 spec %v", alpha.Annotations, tt.wantAnnotation)
+			}
+
+			beta := &modeljobsv1beta1.ModelJob{}
+			if err := beta.ConvertFrom(alpha); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(beta, tt.modeljob) {
+				t.Errorf("ConvertFrom() = %+v, want %+v", beta.Spec, tt.modeljob.Spec)
+			}
+
+			// The spec kept in the annotation is not restored once the modeljob is changed in v1alpha1.
+			alpha.Spec.Model = "release/resnet:v3"
+			if err := beta.ConvertFrom(alpha); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if beta.Spec.Source.Model != alpha.Spec.Model || len(beta.Annotations) != 0 {
+				t.Errorf("ConvertFrom() = %+v, annotations %v, want the changed model without annotations", beta.Spec, beta.Annotations)
+			}
+		})
+	}
+}
+
+func Test_ModelJobConversion_roundTrip(t *testing.T) {
+	f := fuzz.New().NilChance(0.5).NumElements(0, 2)
+
+	for i := 0; i < 1000; i++ {
+		alpha := &modeljobsv1alpha1.ModelJob{ObjectMeta: metav1.ObjectMeta{Name: "resnet"}}
+		f.Fuzz(&alpha.Spec.Model)
+		f.Fuzz(&alpha.Spec.Source)
+		f.Fuzz(&alpha.Spec.DesiredTag)
+		f.Fuzz(&alpha.Spec.ModelJobSource)
+
+		beta := &modeljobsv1beta1.ModelJob{}
+		if err := beta.ConvertFrom(alpha.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		gotAlpha := &modeljobsv1alpha1.ModelJob{}
+		if err := beta.ConvertTo(gotAlpha); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(gotAlpha, alpha) {
+			t.Fatalf("round trip of v1alpha1 = %+v, want %+v", gotAlpha.Spec, alpha.Spec)
+		}
+
+		beta = &modeljobsv1beta1.ModelJob{ObjectMeta: metav1.ObjectMeta{Name: "resnet"}}
+		f.Fuzz(&beta.Spec.Type)
+		f.Fuzz(&beta.Spec.Source)
+		f.Fuzz(&beta.Spec.Destination)
+		f.Fuzz(&beta.Spec.Validation)
+		f.Fuzz(&beta.Spec.Optimization)
+		f.Fuzz(&beta.Spec.Benchmark)
+
+		alpha = &modeljobsv1alpha1.ModelJob{}
+		if err := beta.DeepCopy().ConvertTo(alpha); err != nil {
+			t.Fatalf("ConvertTo() error = %v", err)
+		}
+		gotBeta := &modeljobsv1beta1.ModelJob{}
+		if err := gotBeta.ConvertFrom(alpha); err != nil {
+			t.Fatalf("ConvertFrom() error = %v", err)
+		}
+		if !equality.Semantic.DeepEqual(gotBeta, beta) {
+			t.Fatalf("round trip of v1beta1 = %+v, want %+v", gotBeta.Spec, beta.Spec)
+		}
+	}
+}
+
+func Test_SetModelJobCRDConversion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serving-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, webhookCAName), []byte("ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	certDir := webhookServiceCertDir
+	webhookServiceCertDir = dir
+	defer func() { webhookServiceCertDir = certDir }()
+	defer viper.Set(ModelJobWebhookServiceNameEnvKey, "")
+	defer viper.Set(ModelJobWebhookServiceNamespaceEnvKey, "")
+
+	newCRD := func() *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: ModelJobCRDName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true, Storage: true},
+					{Name: "v1beta1"},
+				},
+			},
+		}
+	}
+
+	crd := newCRD()
+	if err := SetModelJobCRDConversion(crd, []string{"v1alpha1"}, false); err != nil {
+		t.Fatalf("SetModelJobCRDConversion() error = %v", err)
+	}
+	if v := crd.Spec.Versions; !v[0].Served || !v[0].Storage || v[1].Served || v[1].Storage {
+		t.Errorf("SetModelJobCRDConversion() versions = %+v, want only v1alpha1 served and stored", v)
+	}
+
+	crd = newCRD()
+	if err := SetModelJobCRDConversion(crd, []string{"v1alpha1", "v1beta1"}, false); err == nil {
+		t.Errorf("SetModelJobCRDConversion() error = nil, want the webhook required by the objects stored in v1beta1")
+	}
+
+	crd = newCRD()
+	if err := SetModelJobCRDConversion(crd, nil, true); err == nil {
+		t.Errorf("SetModelJobCRDConversion() error = nil, want the service of webhook not set")
+	}
+
+	viper.Set(ModelJobWebhookServiceNameEnvKey, "klever-modeljob-operator-webhook")
+	viper.Set(ModelJobWebhookServiceNamespaceEnvKey, "kleveross-system")
+	if err := SetModelJobCRDConversion(crd, []string{"v1alpha1", "v1beta1"}, true); err != nil {
+		t.Fatalf("SetModelJobCRDConversion() error = %v", err)
+	}
+	conversion := crd.Spec.Conversion
+	if conversion.Strategy != apiextensionsv1.WebhookConverter || string(conversion.Webhook.ClientConfig.CABundle) != "ca" ||
+		*conversion.Webhook.ClientConfig.Service.Path != ModelJobConversionWebhookPath {
+		t.Errorf("SetModelJobCRDConversion() conversion = %+v", conversion.Webhook.ClientConfig)
+	}
+	if v := crd.Spec.Versions; !v[0].Served || v[0].Storage || !v[1].Served || !v[1].Storage {
+		t.Errorf("SetModelJobCRDConversion() versions = %+v, want v1beta1 stored", v)
+	}
+}
+
+var _ = Describe("ModelJob Conversion", func() {
+	const timeout = time.Second * 10
+	const interval = time.Second * 1
+
+	BeforeEach(func() {
+		By("serving and storing v1beta1 by the conversion webhook of manager")
+		options := testEnv.WebhookInstallOptions
+		url := fmt.Sprintf("https://%v%v",
+			net.JoinHostPort(options.LocalServingHost, strconv.Itoa(options.LocalServingPort)), ModelJobConversionWebhookPath)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: ModelJobCRDName}, crd); err != nil {
+				return err
+			}
+			crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: &url, CABundle: options.LocalServingCAData},
+					ConversionReviewVersions: []string{"v1beta1"},
+				},
+			}
+			for i := range crd.Spec.Versions {
+				crd.Spec.Versions[i].Served = true
+				crd.Spec.Versions[i].Storage = crd.Spec.Versions[i].Name == modeljobsv1beta1.GroupVersion.Version
+			}
+			return k8sClient.Update(context.Background(), crd)
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Should convert v1alpha1 modeljobs through the apiserver", func() {
+		desiredTag := "release/resnet:v1-savedmodel"
+		key := types.NamespacedName{Namespace: "default", Name: "conversion-" + utilrand.String(8)}
+		toCreate := &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "release/resnet:v1",
+				DesiredTag: &desiredTag,
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatH5,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				},
+			},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.Background(), toCreate.DeepCopy())
+		}, timeout, interval).Should(Succeed())
+		defer k8sClient.Delete(context.Background(), toCreate)
+
+		By("Expecting to read it in v1beta1")
+		beta := &modeljobsv1beta1.ModelJob{}
+		Expect(k8sClient.Get(context.Background(), key, beta)).Should(Succeed())
+		Expect(beta.Spec.Type).Should(Equal(modeljobsv1beta1.ModelJobTypeConversion))
+		Expect(beta.Spec.Source.Model).Should(Equal(toCreate.Spec.Model))
+		Expect(beta.Spec.Source.Format).Should(Equal(modeljobsv1alpha1.FormatH5))
+		Expect(beta.Spec.Destination).Should(Equal(&modeljobsv1beta1.ModelJobDestination{
+			Model:  desiredTag,
+			Format: modeljobsv1alpha1.FormatSavedModel,
+		}))
+
+		By("Expecting to read it back in v1alpha1 without loss")
+		alpha := &modeljobsv1alpha1.ModelJob{}
+		Expect(k8sClient.Get(context.Background(), key, alpha)).Should(Succeed())
+		Expect(alpha.Spec.Model).Should(Equal(toCreate.Spec.Model))
+		Expect(alpha.Spec.DesiredTag).Should(Equal(toCreate.Spec.DesiredTag))
+		Expect(alpha.Spec.ModelJobSource).Should(Equal(toCreate.Spec.ModelJobSource))
+	})
+
+	It("Should convert v1beta1 modeljobs through the apiserver", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "conversion-" + utilrand.String(8)}
+		toCreate := &modeljobsv1beta1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: modeljobsv1beta1.ModelJobSpec{
+				Type: modeljobsv1beta1.ModelJobTypeValidation,
+				Source: modeljobsv1beta1.ModelJobSource{
+					Model:  "release/resnet:v1",
+					Format: modeljobsv1alpha1.FormatONNX,
+				},
+				Validation: &modeljobsv1beta1.ValidationParameters{Tolerance: "1e-4"},
+			},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.Background(), toCreate.DeepCopy())
+		}, timeout, interval).Should(Succeed())
+		defer k8sClient.Delete(context.Background(), toCreate)
+
+		By("Expecting to read it in v1alpha1")
+		alpha := &modeljobsv1alpha1.ModelJob{}
+		Expect(k8sClient.Get(context.Background(), key, alpha)).Should(Succeed())
+		Expect(alpha.Spec.Model).Should(Equal(toCreate.Spec.Source.Model))
+		Expect(alpha.Spec.Validation).Should(Equal(&modeljobsv1alpha1.ValidationSource{
+			Format:    modeljobsv1alpha1.FormatONNX,
+			Tolerance: "1e-4",
+		}))
+
+		By("Expecting to read it back in v1beta1 without loss")
+		beta := &modeljobsv1beta1.ModelJob{}
+		Expect(k8sClient.Get(context.Background(), key, beta)).Should(Succeed())
+		Expect(beta.Spec).Should(Equal(toCreate.Spec))
+	})
+})
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// PresetModelConverterLabelKey is the label key of the modelconverters created from the preset images
+// of operator, they are updated when the operator starts. Remove the label to keep the changes of them.
+const PresetModelConverterLabelKey = "modelconverter.kleveross.io/preset"
+
+// presetConversionTarget is the target format of the preset conversion images.
+var presetConversionTarget = map[string]modeljobsv1alpha1.Format{
+	"caffemodel-convert":  modeljobsv1alpha1.FormatNetDef,
+	"mxnetparams-convert": modeljobsv1alpha1.FormatONNX,
+	"h5-convert":          modeljobsv1alpha1.FormatSavedModel,
+	"netdef-convert":      modeljobsv1alpha1.FormatONNX,
+}
+
+// converterJobTypes is the job type of each type of modelconverter, it is the suffix of the keys of presetImage.
+var converterJobTypes = map[modeljobsv1alpha1.ModelConverterType]string{
+	modeljobsv1alpha1.ModelConverterTypeExtraction:   "extract",
+	modeljobsv1alpha1.ModelConverterTypeConversion:   "convert",
+	modeljobsv1alpha1.ModelConverterTypeValidation:   "validate",
+	modeljobsv1alpha1.ModelConverterTypeOptimization: "optimize",
+	modeljobsv1alpha1.ModelConverterTypeBenchmark:    "benchmark",
+}
+
+// executor is the executor container of modeljob resolved from modelconverters or preset images.
+type executor struct {
+	image     string
+	command   []string
+	args      []string
+	resources corev1.ResourceRequirements
+	env       []corev1.EnvVar
+	// presetKey is the key of presetImage, eg: savedmodel-extract, it is used to get the operator-wide defaults.
+	presetKey string
+}
+
+// getModelJobFormats returns the type, source format and target format of modeljob.
+func getModelJobFormats(modeljob *modeljobsv1alpha1.ModelJob) (modeljobsv1alpha1.ModelConverterType,
+	modeljobsv1alpha1.Format, modeljobsv1alpha1.Format) {
+	if modeljob.Spec.Conversion != nil && modeljob.Spec.Conversion.MMdnn != nil {
+		return modeljobsv1alpha1.ModelConverterTypeConversion, modeljob.Spec.Conversion.MMdnn.From, modeljob.Spec.Conversion.MMdnn.To
+	}
+	if modeljob.Spec.Extraction != nil {
+		return modeljobsv1alpha1.ModelConverterTypeExtraction, modeljob.Spec.Extraction.Format, modeljob.Spec.Extraction.Format
+	}
+	if modeljob.Spec.Validation != nil {
+		return modeljobsv1alpha1.ModelConverterTypeValidation, modeljob.Spec.Validation.Format, modeljob.Spec.Validation.Format
+	}
+	if modeljob.Spec.Optimization != nil {
+		return modeljobsv1alpha1.ModelConverterTypeOptimization, modeljob.Spec.Optimization.Format, modeljob.Spec.Optimization.Format
+	}
+	if modeljob.Spec.Benchmark != nil {
+		return modeljobsv1alpha1.ModelConverterTypeBenchmark, modeljob.Spec.Benchmark.Format, modeljob.Spec.Benchmark.Format
+	}
+	return "", "", ""
+}
+
+// getModelJobType returns the job type of modeljob, eg: extract, it is the task of executor.
+func getModelJobType(modeljob *modeljobsv1alpha1.ModelJob) string {
+	converterType, _, _ := getModelJobFormats(modeljob)
+	return converterJobTypes[converterType]
+}
+
+// getPresetKey returns the key of presetImage for the type and source format, eg: savedmodel-extract.
+func getPresetKey(converterType modeljobsv1alpha1.ModelConverterType, from modeljobsv1alpha1.Format) string {
+	jobType, ok := converterJobTypes[converterType]
+	if !ok {
+		jobType = "extract"
+	}
+	return strings.ToLower(string(from)) + "-" + jobType
+}
+
+func containsFormat(formats []modeljobsv1alpha1.Format, format modeljobsv1alpha1.Format) bool {
+	for _, f := range formats {
+		if strings.EqualFold(string(f), string(format)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchModelConverter returns true if the modelconverter can run the modeljob.
+func matchModelConverter(converter *modeljobsv1alpha1.ModelConverter, converterType modeljobsv1alpha1.ModelConverterType,
+	from, to modeljobsv1alpha1.Format) bool {
+	if converter.Spec.Type != converterType || converter.Spec.Image == "" {
+		return false
+	}
+	if !containsFormat(converter.Spec.SourceFormats, from) {
+		return false
+	}
+	if converterType == modeljobsv1alpha1.ModelConverterTypeConversion && len(converter.Spec.TargetFormats) != 0 {
+		return containsFormat(converter.Spec.TargetFormats, to)
+	}
+	return true
+}
+
+// findModelConverter returns the first modelconverter ordered by name which can run the modeljob.
+func findModelConverter(modeljob *modeljobsv1alpha1.ModelJob,
+	converters []modeljobsv1alpha1.ModelConverter) *modeljobsv1alpha1.ModelConverter {
+	converterType, from, to := getModelJobFormats(modeljob)
+	if converterType == "" {
+		return nil
+	}
+
+	sorted := make([]*modeljobsv1alpha1.ModelConverter, 0, len(converters))
+	for i := range converters {
+		sorted = append(sorted, &converters[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	for _, converter := range sorted {
+		if matchModelConverter(converter, converterType, from, to) {
+			return converter
+		}
+	}
+	return nil
+}
+
+// resolveExecutor resolves the executor of modeljob from spec.executor or the modelconverters,
+// the preset image of operator is used if no modelconverter can run it.
+func resolveExecutor(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter) (*executor, error) {
+	converterType, from, _ := getModelJobFormats(modeljob)
+	presetKey := getPresetKey(converterType, from)
+
+	// spec.executor takes precedence over the modelconverters and the preset image.
+	if override := modeljob.Spec.Executor; override != nil {
+		return &executor{
+			image:     override.Image,
+			command:   override.Command,
+			args:      override.Args,
+			presetKey: presetKey,
+		}, nil
+	}
+
+	if converter := findModelConverter(modeljob, converters); converter != nil {
+		return &executor{
+			image:     converter.Spec.Image,
+			resources: converter.Spec.Resources,
+			env:       converter.Spec.Env,
+			presetKey: presetKey,
+		}, nil
+	}
+
+	var image string
+	if imageEnv, ok := presetImage[presetKey]; ok {
+		image = viper.GetString(imageEnv)
+	}
+	if image == "" {
+		return nil, fmt.Errorf("failed get %v model %v image", from, strings.TrimPrefix(presetKey, strings.ToLower(string(from))+"-"))
+	}
+
+	return &executor{image: image, presetKey: presetKey}, nil
+}
+
+// generatePresetModelConverters generates the modelconverters of the preset images configured in operator.
+func generatePresetModelConverters() []*modeljobsv1alpha1.ModelConverter {
+	formats := map[string]modeljobsv1alpha1.Format{}
+	for format := range ModelFormatToFrameworkMapping {
+		formats[strings.ToLower(string(format))] = format
+	}
+	formats[strings.ToLower(string(modeljobsv1alpha1.FormatPMML))] = modeljobsv1alpha1.FormatPMML
+
+	converters := []*modeljobsv1alpha1.ModelConverter{}
+	for key, imageEnv := range presetImage {
+		image := viper.GetString(imageEnv)
+		if image == "" {
+			continue
+		}
+
+		var converterType modeljobsv1alpha1.ModelConverterType
+		var formatName string
+		for t, jobType := range converterJobTypes {
+			if strings.HasSuffix(key, "-"+jobType) {
+				converterType, formatName = t, strings.TrimSuffix(key, "-"+jobType)
+			}
+		}
+		if converterType == "" {
+			continue
+		}
+		format, ok := formats[formatName]
+		if !ok {
+			continue
+		}
+
+		converter := &modeljobsv1alpha1.ModelConverter{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: key,
+				Labels: map[string]string{
+					PresetModelConverterLabelKey: "true",
+				},
+			},
+			Spec: modeljobsv1alpha1.ModelConverterSpec{
+				Type:          converterType,
+				SourceFormats: []modeljobsv1alpha1.Format{format},
+				Image:         image,
+			},
+		}
+		if target, ok := presetConversionTarget[key]; ok {
+			converter.Spec.TargetFormats = []modeljobsv1alpha1.Format{target}
+		}
+		converters = append(converters, converter)
+	}
+
+	sort.SliceStable(converters, func(i, j int) bool {
+		return converters[i].Name < converters[j].Name
+	})
+	return converters
+}
+
+// EnsurePresetModelConverters creates or updates the modelconverters of the preset images,
+// the modelconverters without preset label are not changed.
+func EnsurePresetModelConverters(c client.Client) error {
+	for _, converter := range generatePresetModelConverters() {
+		current := &modeljobsv1alpha1.ModelConverter{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: converter.Name}, current)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			if err := c.Create(context.TODO(), converter); err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+
+		if current.Labels[PresetModelConverterLabelKey] != "true" || equality.Semantic.DeepEqual(current.Spec, converter.Spec) {
+			continue
+		}
+		current.Spec = converter.Spec
+		if err := c.Update(context.TODO(), current); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
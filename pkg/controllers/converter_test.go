@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_resolveExecutor(t *testing.T) {
+	viper.AutomaticEnv()
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	converters := []modeljobsv1alpha1.ModelConverter{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mmdnn"},
+			Spec: modeljobsv1alpha1.ModelConverterSpec{
+				Type:          modeljobsv1alpha1.ModelConverterTypeConversion,
+				SourceFormats: []modeljobsv1alpha1.Format{modeljobsv1alpha1.FormatONNX},
+				TargetFormats: []modeljobsv1alpha1.Format{modeljobsv1alpha1.FormatSavedModel},
+				Image:         "mmdnn:v1",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "onnx-extract"},
+			Spec: modeljobsv1alpha1.ModelConverterSpec{
+				Type:          modeljobsv1alpha1.ModelConverterTypeExtraction,
+				SourceFormats: []modeljobsv1alpha1.Format{"onnx"},
+				Image:         "onnx-extract:v1",
+			},
+		},
+	}
+	newConversion := func(from, to modeljobsv1alpha1.Format) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{From: from, To: to},
+						},
+					},
+				},
+			},
+		}
+	}
+	newExtraction := func(format modeljobsv1alpha1.Format) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: format},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		modeljob      *modeljobsv1alpha1.ModelJob
+		wantImage     string
+		wantPresetKey string
+		wantErr       bool
+	}{
+		{
+			name:          "conversion from modelconverter",
+			modeljob:      newConversion(modeljobsv1alpha1.FormatONNX, modeljobsv1alpha1.FormatSavedModel),
+			wantImage:     "mmdnn:v1",
+			wantPresetKey: "onnx-convert",
+		},
+		{
+			name:     "conversion to unsupported target format",
+			modeljob: newConversion(modeljobsv1alpha1.FormatONNX, modeljobsv1alpha1.FormatNetDef),
+			wantErr:  true,
+		},
+		{
+			name:          "conversion from preset image",
+			modeljob:      newConversion(modeljobsv1alpha1.FormatH5, modeljobsv1alpha1.FormatSavedModel),
+			wantImage:     "demo.goharbor.com/release/h5_to_savedmodel:v0.2.0",
+			wantPresetKey: "h5-convert",
+		},
+		{
+			name:          "extraction from modelconverter",
+			modeljob:      newExtraction(modeljobsv1alpha1.FormatONNX),
+			wantImage:     "onnx-extract:v1",
+			wantPresetKey: "onnx-extract",
+		},
+		{
+			name:          "extraction from preset image",
+			modeljob:      newExtraction(modeljobsv1alpha1.FormatSavedModel),
+			wantImage:     "demo.goharbor.com/release/savedmodel-extract:v0.2.0",
+			wantPresetKey: "savedmodel-extract",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveExecutor(tt.modeljob, converters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveExecutor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.image != tt.wantImage || got.presetKey != tt.wantPresetKey {
+				t.Errorf("resolveExecutor() = %v, want image %v and preset key %v", got, tt.wantImage, tt.wantPresetKey)
+			}
+		})
+	}
+
+	job, err := generateJobResource(&modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model:      "release/onnx:v1",
+			DesiredTag: &[]string{"release/savedmodel:v1"}[0],
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Conversion: newConversion(modeljobsv1alpha1.FormatONNX, modeljobsv1alpha1.FormatSavedModel).Spec.Conversion,
+			},
+		},
+	}, converters, "")
+	if err != nil {
+		t.Errorf("generateJobResource() error = %v", err)
+		return
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if container.Image != "mmdnn:v1" || !container.Resources.Limits.Cpu().Equal(resource.MustParse("1")) {
+		t.Errorf("generateJobResource() executor = %v, want the image and resources of modelconverter", container)
+	}
+}
+
+func Test_generatePresetModelConverters(t *testing.T) {
+	viper.AutomaticEnv()
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	converters := map[string]*modeljobsv1alpha1.ModelConverter{}
+	for _, converter := range generatePresetModelConverters() {
+		converters[converter.Name] = converter
+	}
+
+	extract, ok := converters["savedmodel-extract"]
+	if !ok || extract.Spec.Type != modeljobsv1alpha1.ModelConverterTypeExtraction ||
+		extract.Spec.SourceFormats[0] != modeljobsv1alpha1.FormatSavedModel ||
+		extract.Labels[PresetModelConverterLabelKey] != "true" {
+		t.Errorf("generatePresetModelConverters() savedmodel-extract = %v", extract)
+	}
+
+	convert, ok := converters["h5-convert"]
+	if !ok || convert.Spec.Type != modeljobsv1alpha1.ModelConverterTypeConversion ||
+		convert.Spec.SourceFormats[0] != modeljobsv1alpha1.FormatH5 ||
+		convert.Spec.TargetFormats[0] != modeljobsv1alpha1.FormatSavedModel {
+		t.Errorf("generatePresetModelConverters() h5-convert = %v", convert)
+	}
+
+	if _, ok := converters["initializer"]; ok {
+		t.Errorf("generatePresetModelConverters() should not generate modelconverter of initializer")
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/common"
+)
+
+// registryCredentialsSecretSuffix is the suffix of the basic-auth Secret created by operator for modeljob,
+// it holds the credentials of dockerconfigjson Secret or the fallback account of operator.
+const registryCredentialsSecretSuffix = "-registry-credentials"
+
+// dockerConfigJSON is the content of kubernetes.io/dockerconfigjson Secret.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// isRegistryCredentialsFallbackEnabled returns true if the account of operator is used for the modeljobs
+// without registry credentials.
+func isRegistryCredentialsFallbackEnabled() bool {
+	return viper.GetBool(ModelJobRegistryCredentialsFallbackEnvKey)
+}
+
+// getRegistryDomains returns the domains which the modeljob pulls model from and pushes model to.
+func getRegistryDomains(modeljob *modeljobsv1alpha1.ModelJob) []string {
+	domains := []string{getORMBDomain(modeljob.Spec.Conversion != nil || modeljob.Spec.Optimization != nil)}
+	if refSlice := strings.Split(modeljob.Spec.Model, "/"); len(refSlice) == 3 {
+		domains = append(domains, refSlice[0])
+	}
+	domains = append(domains, viper.GetString(common.ORMBDomainEnvKey))
+	return domains
+}
+
+// normalizeRegistry removes the scheme and path of registry in dockerconfigjson, eg: https://harbor.io/v2/ is harbor.io.
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	return strings.SplitN(registry, "/", 2)[0]
+}
+
+// getDockerConfigCredentials returns the username and password of the first domain found in dockerconfigjson.
+func getDockerConfigCredentials(data []byte, domains []string) (string, string, error) {
+	config := &dockerConfigJSON{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return "", "", fmt.Errorf("failed to parse %v: %v", corev1.DockerConfigJsonKey, err)
+	}
+
+	entries := map[string]dockerConfigEntry{}
+	for registry, entry := range config.Auths {
+		entries[normalizeRegistry(registry)] = entry
+	}
+	for _, domain := range domains {
+		entry, ok := entries[normalizeRegistry(domain)]
+		if domain == "" || !ok {
+			continue
+		}
+		if entry.Username == "" && entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to decode auth of registry %v: %v", domain, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return "", "", fmt.Errorf("the auth of registry %v is not in the form of username:password", domain)
+			}
+			entry.Username, entry.Password = parts[0], parts[1]
+		}
+		if entry.Username == "" || entry.Password == "" {
+			return "", "", fmt.Errorf("the username or password of registry %v is empty", domain)
+		}
+		return entry.Username, entry.Password, nil
+	}
+
+	return "", "", fmt.Errorf("no credentials of registry %v are found", strings.Join(domains, ","))
+}
+
+// generateRegistryCredentialsSecret generates the basic-auth Secret of modeljob.
+func generateRegistryCredentialsSecret(modeljob *modeljobsv1alpha1.ModelJob, username, password string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	secret.Namespace = modeljob.Namespace
+	secret.Name = modeljob.Name + registryCredentialsSecretSuffix
+	secret.Type = corev1.SecretTypeBasicAuth
+	secret.Data = map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(username),
+		corev1.BasicAuthPasswordKey: []byte(password),
+	}
+	return secret
+}
+
+// reconcileRegistryCredentials returns the name of basic-auth Secret which is referenced by the containers of job.
+// The basic-auth Secret of modeljob is used directly, the credentials of dockerconfigjson Secret or the fallback
+// account of operator are copied to a basic-auth Secret owned by modeljob.
+func (r *ModelJobReconciler) reconcileRegistryCredentials(modeljob *modeljobsv1alpha1.ModelJob) (string, error) {
+	name, username, password, err := resolveRegistryCredentials(modeljob, func(name string) (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: modeljob.Namespace, Name: name}, secret)
+		return secret, err
+	})
+	if err != nil || name != "" {
+		return name, err
+	}
+
+	desired := generateRegistryCredentialsSecret(modeljob, username, password)
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = desired.Namespace, desired.Name
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), r.Client, secret, func() error {
+		secret.Type = desired.Type
+		secret.Data = desired.Data
+		return controllerutil.SetControllerReference(modeljob, secret, r.Scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Name, nil
+}
+
+// resolveRegistryCredentials returns the name of the basic-auth Secret of modeljob if it is used directly,
+// otherwise the username and password which are copied to the Secret owned by modeljob. The Secrets in the
+// namespace of modeljob are read by getSecret.
+func resolveRegistryCredentials(modeljob *modeljobsv1alpha1.ModelJob,
+	getSecret func(name string) (*corev1.Secret, error)) (string, string, string, error) {
+	if modeljob.Spec.RegistryCredentials == nil {
+		if !isRegistryCredentialsFallbackEnabled() {
+			return "", "", "", fmt.Errorf("spec.registryCredentials is not set and the registry credentials fallback of operator is disabled")
+		}
+		username, password := viper.GetString(common.ORMBUsernameEnvkey), viper.GetString(common.ORMBPasswordEnvKey)
+		if username == "" || password == "" {
+			return "", "", "", fmt.Errorf("spec.registryCredentials is not set and the ORMB username or password of operator is empty")
+		}
+		return "", username, password, nil
+	}
+
+	name := modeljob.Spec.RegistryCredentials.SecretName
+	secret, err := getSecret(name)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	switch secret.Type {
+	case corev1.SecretTypeBasicAuth:
+		if len(secret.Data[corev1.BasicAuthUsernameKey]) == 0 || len(secret.Data[corev1.BasicAuthPasswordKey]) == 0 {
+			return "", "", "", fmt.Errorf("the username or password of secret %v is empty", name)
+		}
+		return name, "", "", nil
+	case corev1.SecretTypeDockerConfigJson:
+		username, password, err := getDockerConfigCredentials(secret.Data[corev1.DockerConfigJsonKey], getRegistryDomains(modeljob))
+		if err != nil {
+			return "", "", "", fmt.Errorf("secret %v: %v", name, err)
+		}
+		return "", username, password, nil
+	default:
+		return "", "", "", fmt.Errorf("the type %v of secret %v is not supported, it must be %v or %v",
+			secret.Type, name, corev1.SecretTypeDockerConfigJson, corev1.SecretTypeBasicAuth)
+	}
+}
+
+// generateRegistryCredentialsEnv generates the env of username and password which reference the basic-auth Secret.
+func generateRegistryCredentialsEnv(secretName, usernameEnvKey, passwordEnvKey string) []corev1.EnvVar {
+	if secretName == "" {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: usernameEnvKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  corev1.BasicAuthUsernameKey,
+				},
+			},
+		},
+		{
+			Name: passwordEnvKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  corev1.BasicAuthPasswordKey,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/common"
+)
+
+func Test_getDockerConfigCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	tests := []struct {
+		name         string
+		data         string
+		domains      []string
+		wantUsername string
+		wantPassword string
+		wantErr      bool
+	}{
+		{
+			name:         "username and password",
+			data:         `{"auths":{"https://harbor.io/v2/":{"username":"admin","password":"pass"}}}`,
+			domains:      []string{"harbor.io"},
+			wantUsername: "admin",
+			wantPassword: "pass",
+		},
+		{
+			name:         "auth",
+			data:         `{"auths":{"harbor.io":{"auth":"` + auth + `"}}}`,
+			domains:      []string{"", "harbor.io"},
+			wantUsername: "robot",
+			wantPassword: "secret",
+		},
+		{
+			name:    "registry not found",
+			data:    `{"auths":{"docker.io":{"username":"admin","password":"pass"}}}`,
+			domains: []string{"harbor.io"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			data:    `{`,
+			domains: []string{"harbor.io"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, password, err := getDockerConfigCredentials([]byte(tt.data), tt.domains)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDockerConfigCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if username != tt.wantUsername || password != tt.wantPassword {
+				t.Errorf("getDockerConfigCredentials() = %v, %v, want %v, %v", username, password, tt.wantUsername, tt.wantPassword)
+			}
+		})
+	}
+}
+
+func Test_reconcileRegistryCredentials(t *testing.T) {
+	viper.Set(common.ORMBDomainEnvKey, "harbor.io")
+	viper.Set(common.ORMBUsernameEnvkey, "admin")
+	viper.Set(common.ORMBPasswordEnvKey, "ORMBtest12345")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	basicAuth := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "basic"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("robot"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	}
+	dockerConfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "docker"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"harbor.io":{"username":"robot","password":"secret"}}}`),
+		},
+	}
+	opaque := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "opaque"},
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	tests := []struct {
+		name         string
+		credentials  *modeljobsv1alpha1.RegistryCredentials
+		fallback     bool
+		noAccount    bool
+		wantSecret   string
+		wantUsername string
+		wantErr      bool
+	}{
+		{
+			name:        "basic-auth secret",
+			credentials: &modeljobsv1alpha1.RegistryCredentials{SecretName: "basic"},
+			wantSecret:  "basic",
+		},
+		{
+			name:         "dockerconfigjson secret",
+			credentials:  &modeljobsv1alpha1.RegistryCredentials{SecretName: "docker"},
+			wantSecret:   "test" + registryCredentialsSecretSuffix,
+			wantUsername: "robot",
+		},
+		{
+			name:        "unsupported secret",
+			credentials: &modeljobsv1alpha1.RegistryCredentials{SecretName: "opaque"},
+			wantErr:     true,
+		},
+		{
+			name:    "fallback disabled",
+			wantErr: true,
+		},
+		{
+			name:         "fallback enabled",
+			fallback:     true,
+			wantSecret:   "test" + registryCredentialsSecretSuffix,
+			wantUsername: "admin",
+		},
+		{
+			name:      "fallback without account",
+			fallback:  true,
+			noAccount: true,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, tt.fallback)
+			defer viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, false)
+			if tt.noAccount {
+				viper.Set(common.ORMBPasswordEnvKey, "")
+				defer viper.Set(common.ORMBPasswordEnvKey, "ORMBtest12345")
+			}
+
+			c := fake.NewFakeClientWithScheme(scheme, basicAuth.DeepCopy(), dockerConfig.DeepCopy(), opaque.DeepCopy())
+			r := &ModelJobReconciler{
+				Client:        c,
+				EventRecorder: record.NewFakeRecorder(10),
+				Log:           ctrl.Log.WithName("test"),
+				Scheme:        scheme,
+			}
+			modeljob := &modeljobsv1alpha1.ModelJob{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+				Spec: modeljobsv1alpha1.ModelJobSpec{
+					Model:               "harbor.io/release/savedmodel:v1",
+					RegistryCredentials: tt.credentials,
+				},
+			}
+
+			got, err := r.reconcileRegistryCredentials(modeljob)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileRegistryCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantSecret {
+				t.Errorf("reconcileRegistryCredentials() = %v, want %v", got, tt.wantSecret)
+			}
+			if tt.wantUsername == "" {
+				return
+			}
+			secret := &corev1.Secret{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: got}, secret); err != nil {
+				t.Fatal(err)
+			}
+			if string(secret.Data[corev1.BasicAuthUsernameKey]) != tt.wantUsername || len(secret.OwnerReferences) != 1 {
+				t.Errorf("secret of modeljob = %v, want username %v and owner reference", secret, tt.wantUsername)
+			}
+		})
+	}
+}
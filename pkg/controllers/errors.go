@@ -15,4 +15,43 @@ const (
 	ErrORMBSaveModel = 10004
 	// ErrORMBPushModel is the exit code of ormb push error
 	ErrORMBPushModel = 10005
+	// ErrValidationFailed is the exit code when the model does not pass the validation
+	ErrValidationFailed = 10006
 )
+
+// defines the machine readable reason of executor exit codes
+const (
+	reasonORMBLoginFailed  = "ORMBLoginFailed"
+	reasonORMBPullFailed   = "ORMBPullFailed"
+	reasonORMBExportFailed = "ORMBExportFailed"
+	reasonRunTaskFailed    = "RunTaskFailed"
+	reasonORMBSaveFailed   = "ORMBSaveFailed"
+	reasonORMBPushFailed   = "ORMBPushFailed"
+	reasonValidationFailed = "ValidationFailed"
+	// reasonEphemeralStorageEvicted is the reason when the pod is evicted for the ephemeral storage,
+	// it is not an exit code of executor.
+	reasonEphemeralStorageEvicted = "EphemeralStorageEvicted"
+	reasonUnknownFailed           = "Unknown"
+)
+
+// exitCodeToReason converts the exit code of executor to machine readable reason.
+func exitCodeToReason(exitCode int32) string {
+	switch exitCode {
+	case ErrORMBLogin:
+		return reasonORMBLoginFailed
+	case ErrORMBPullModel:
+		return reasonORMBPullFailed
+	case ErrORMBExportModel:
+		return reasonORMBExportFailed
+	case ErrRunTask:
+		return reasonRunTaskFailed
+	case ErrORMBSaveModel:
+		return reasonORMBSaveFailed
+	case ErrORMBPushModel:
+		return reasonORMBPushFailed
+	case ErrValidationFailed:
+		return reasonValidationFailed
+	}
+
+	return reasonUnknownFailed
+}
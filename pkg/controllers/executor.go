@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// defaultImageDomain is the domain of the images without domain, e.g. ubuntu:20.04.
+const defaultImageDomain = "docker.io"
+
+// getExecutorImageAllowlist returns the registries and prefixes of the images of spec.executor.
+func getExecutorImageAllowlist() []string {
+	allowlist := []string{}
+	for _, item := range strings.Split(viper.GetString(ModelJobExecutorImageAllowlistEnvKey), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			allowlist = append(allowlist, item)
+		}
+	}
+	return allowlist
+}
+
+// isExecutorImageAllowed returns true if the image matches an item of allowlist. The item without "/" is a
+// registry which matches the domain of image, e.g. harbor.io, the others are prefixes which match the image
+// by the path, e.g. harbor.io/release matches harbor.io/release/extract:v1 but not harbor.io/release-dev/extract:v1.
+func isExecutorImageAllowed(image string, allowlist []string) bool {
+	for _, item := range allowlist {
+		if !strings.Contains(item, "/") {
+			if getImageDomain(image) == item {
+				return true
+			}
+			continue
+		}
+		if strings.HasSuffix(item, "/") {
+			if strings.HasPrefix(image, item) {
+				return true
+			}
+			continue
+		}
+		if image == item || strings.HasPrefix(image, item+"/") || strings.HasPrefix(image, item+":") ||
+			strings.HasPrefix(image, item+"@") {
+			return true
+		}
+	}
+	return false
+}
+
+// getImageDomain returns the domain of image, the first component is a domain if it contains "." or ":"
+// or it is localhost.
+func getImageDomain(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0]
+	}
+	return defaultImageDomain
+}
+
+// validateExecutor validates that the image of spec.executor is set and allowed by the operator.
+func validateExecutor(spec *modeljobsv1alpha1.ModelJobSpec, specPath *field.Path) field.ErrorList {
+	if spec.Executor == nil {
+		return nil
+	}
+	imagePath := specPath.Child("executor", "image")
+	image := spec.Executor.Image
+
+	if image == "" {
+		return field.ErrorList{field.Required(imagePath, "")}
+	}
+	if strings.TrimSpace(image) != image {
+		return field.ErrorList{field.Invalid(imagePath, image, "must not have leading or trailing whitespace")}
+	}
+	allowlist := getExecutorImageAllowlist()
+	if len(allowlist) == 0 {
+		return field.ErrorList{field.Forbidden(imagePath,
+			fmt.Sprintf("the executor image override is disabled, %v of operator is empty", ModelJobExecutorImageAllowlistEnvKey))}
+	}
+	if !isExecutorImageAllowed(image, allowlist) {
+		return field.ErrorList{field.Forbidden(imagePath,
+			fmt.Sprintf("image %v is not in the executor image allowlist of operator: %v", image, strings.Join(allowlist, ",")))}
+	}
+	return nil
+}
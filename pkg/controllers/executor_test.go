@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_isExecutorImageAllowed(t *testing.T) {
+	allowlist := []string{"harbor.io", "ghcr.io/kleveross/", "localhost:5000/team-a", "library/ubuntu"}
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{image: "harbor.io/release/savedmodel-extract:v1", want: true},
+		{image: "harbor.io.evil.com/release/savedmodel-extract:v1", want: false},
+		{image: "ghcr.io/kleveross/savedmodel-extract:v1", want: true},
+		{image: "ghcr.io/kleveross-dev/savedmodel-extract:v1", want: false},
+		{image: "localhost:5000/team-a/extract@sha256:0123", want: true},
+		{image: "localhost:5000/team-a:v1", want: true},
+		{image: "localhost:5000/team-ab/extract:v1", want: false},
+		{image: "library/ubuntu:20.04", want: true},
+		{image: "ubuntu:20.04", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := isExecutorImageAllowed(tt.image, allowlist); got != tt.want {
+				t.Errorf("isExecutorImageAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateExecutor(t *testing.T) {
+	defer viper.Set(ModelJobExecutorImageAllowlistEnvKey, "")
+
+	tests := []struct {
+		name      string
+		allowlist string
+		executor  *modeljobsv1alpha1.Executor
+		wantErrs  []string
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:      "allowed",
+			allowlist: "harbor.io, ghcr.io/kleveross/",
+			executor:  &modeljobsv1alpha1.Executor{Image: "ghcr.io/kleveross/savedmodel-extract:fix"},
+		},
+		{
+			name:      "not allowed",
+			allowlist: "harbor.io",
+			executor:  &modeljobsv1alpha1.Executor{Image: "docker.io/someone/savedmodel-extract:fix"},
+			wantErrs:  []string{"spec.executor.image"},
+		},
+		{
+			name:     "allowlist is empty",
+			executor: &modeljobsv1alpha1.Executor{Image: "harbor.io/release/savedmodel-extract:fix"},
+			wantErrs: []string{"spec.executor.image"},
+		},
+		{
+			name:      "image is empty",
+			allowlist: "harbor.io",
+			executor:  &modeljobsv1alpha1.Executor{},
+			wantErrs:  []string{"spec.executor.image"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set(ModelJobExecutorImageAllowlistEnvKey, tt.allowlist)
+			errs := validateExecutor(&modeljobsv1alpha1.ModelJobSpec{Executor: tt.executor}, field.NewPath("spec"))
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateExecutor() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateExecutor() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_generateJobResource_executor(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+	viper.Set(ModelJobExecutorImageAllowlistEnvKey, "harbor.io/team-a/")
+	defer viper.Set(ModelJobExecutorImageAllowlistEnvKey, "")
+
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "resnet"},
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model: "release/savedmodel:v1",
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+			},
+			Executor: &modeljobsv1alpha1.Executor{
+				Image:   "harbor.io/team-a/savedmodel-extract:fix",
+				Command: []string{"/scripts/run.sh"},
+				Args:    []string{"--verbose"},
+			},
+		},
+	}
+	job, err := generateJobResource(modeljob, nil, "test-modeljob-registry-credentials")
+	if err != nil {
+		t.Fatalf("generateJobResource() error = %v", err)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if container.Image != modeljob.Spec.Executor.Image {
+		t.Errorf("generateJobResource() image = %v, want %v", container.Image, modeljob.Spec.Executor.Image)
+	}
+	if !reflect.DeepEqual(container.Command, modeljob.Spec.Executor.Command) || !reflect.DeepEqual(container.Args, modeljob.Spec.Executor.Args) {
+		t.Errorf("generateJobResource() command = %v, args = %v, want %v, %v", container.Command, container.Args,
+			modeljob.Spec.Executor.Command, modeljob.Spec.Executor.Args)
+	}
+
+	modeljob.Spec.Executor.Image = "docker.io/someone/savedmodel-extract:fix"
+	if _, err := generateJobResource(modeljob, nil, "test-modeljob-registry-credentials"); err == nil {
+		t.Errorf("generateJobResource() error = nil, want the image not allowed")
+	}
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// getTTLSecondsAfterFinished returns the ttl of modeljob, the operator-wide default is used if it is not set.
+func getTTLSecondsAfterFinished(modeljob *modeljobsv1alpha1.ModelJob) *int32 {
+	if modeljob.Spec.TTLSecondsAfterFinished != nil {
+		return modeljob.Spec.TTLSecondsAfterFinished
+	}
+
+	ttl, err := strconv.ParseInt(viper.GetString(ModelJobTTLSecondsAfterFinishedEnvKey), 10, 32)
+	if err != nil || ttl < 0 {
+		return nil
+	}
+	ttlSeconds := int32(ttl)
+	return &ttlSeconds
+}
+
+// getModelJobHistoryLimit returns the number of finished modeljobs kept for each model, 0 means no limit.
+func getModelJobHistoryLimit() int {
+	limit, err := strconv.Atoi(viper.GetString(ModelJobHistoryLimitEnvKey))
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// getModelRepository returns the repository of model ref without domain and tag,
+// eg: harbor.io/release/savedmodel:v1.0 => release/savedmodel.
+func getModelRepository(modelRef string) string {
+	refSlice := strings.Split(modelRef, "/")
+	if len(refSlice) > 2 {
+		refSlice = refSlice[len(refSlice)-2:]
+	}
+	repository := strings.Join(refSlice, "/")
+	if index := strings.LastIndex(repository, ":"); index != -1 {
+		repository = repository[:index]
+	}
+
+	return repository
+}
+
+// isModelJobOwnedByPipeline returns true if the modeljob is a stage of modelpipeline, it is deleted with the
+// modelpipeline instead of its ttl.
+func isModelJobOwnedByPipeline(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	owner := metav1.GetControllerOf(modeljob)
+	return owner != nil && owner.Kind == "ModelPipeline" && owner.APIVersion == modeljobsv1alpha1.GroupVersion.String()
+}
+
+// getModelJobExpireTime returns the time when the ttl of finished modeljob expires.
+func getModelJobExpireTime(modeljob *modeljobsv1alpha1.ModelJob) *time.Time {
+	ttl := getTTLSecondsAfterFinished(modeljob)
+	if ttl == nil || !isModelJobFinished(modeljob) || modeljob.Status.CompletionTime == nil {
+		return nil
+	}
+
+	expireTime := modeljob.Status.CompletionTime.Add(time.Duration(*ttl) * time.Second)
+	return &expireTime
+}
+
+// selectExpiredModelJobs selects the modeljobs whose ttl expired, and the latest
+// historyLimit finished modeljobs of each model are kept. The modeljobs of modelpipelines are not selected.
+func selectExpiredModelJobs(modeljobs []modeljobsv1alpha1.ModelJob, historyLimit int, now time.Time) []*modeljobsv1alpha1.ModelJob {
+	finished := map[string][]*modeljobsv1alpha1.ModelJob{}
+	for i := range modeljobs {
+		if !isModelJobFinished(&modeljobs[i]) || modeljobs[i].Status.CompletionTime == nil ||
+			isModelJobOwnedByPipeline(&modeljobs[i]) {
+			continue
+		}
+		repository := getModelRepository(modeljobs[i].Spec.Model)
+		finished[repository] = append(finished[repository], &modeljobs[i])
+	}
+
+	expired := []*modeljobsv1alpha1.ModelJob{}
+	for _, items := range finished {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[j].Status.CompletionTime.Before(items[i].Status.CompletionTime)
+		})
+		for i, item := range items {
+			if historyLimit > 0 && i < historyLimit {
+				continue
+			}
+			if expireTime := getModelJobExpireTime(item); expireTime != nil && !now.Before(*expireTime) {
+				expired = append(expired, item)
+			}
+		}
+	}
+
+	return expired
+}
+
+// reconcileTTL deletes the finished modeljobs of the same model whose ttl expired.
+// The jobs are owned by modeljob, so they are deleted by garbage collector.
+func (r *ModelJobReconciler) reconcileTTL(modeljob *modeljobsv1alpha1.ModelJob) (ctrl.Result, error) {
+	expireTime := getModelJobExpireTime(modeljob)
+	if expireTime == nil || isModelJobOwnedByPipeline(modeljob) {
+		return ctrl.Result{}, nil
+	}
+	if remaining := time.Until(*expireTime); remaining > 0 {
+		return ctrl.Result{Requeue: true, RequeueAfter: remaining}, nil
+	}
+
+	modeljobs := &modeljobsv1alpha1.ModelJobList{}
+	if err := r.List(context.TODO(), modeljobs, client.InNamespace(modeljob.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, expired := range selectExpiredModelJobs(modeljobs.Items, getModelJobHistoryLimit(), time.Now()) {
+		err := r.Delete(context.TODO(), expired, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.Log.Info("deleted modeljob whose ttl expired", "modelJobName", expired.Name, "namespace", expired.Namespace)
+	}
+
+	return ctrl.Result{}, nil
+}
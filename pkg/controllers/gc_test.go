@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_getModelRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		modelRef string
+		want     string
+	}{
+		{
+			name:     "without domain",
+			modelRef: "release/savedmodel:v1.0",
+			want:     "release/savedmodel",
+		},
+		{
+			name:     "with domain",
+			modelRef: "harbor.io/release/savedmodel:v1.0",
+			want:     "release/savedmodel",
+		},
+		{
+			name:     "with port",
+			modelRef: "harbor.io:8080/release/savedmodel:v1.0",
+			want:     "release/savedmodel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getModelRepository(tt.modelRef); got != tt.want {
+				t.Errorf("getModelRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getTTLSecondsAfterFinished(t *testing.T) {
+	viper.AutomaticEnv()
+	defer os.Unsetenv(ModelJobTTLSecondsAfterFinishedEnvKey)
+
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	os.Setenv(ModelJobTTLSecondsAfterFinishedEnvKey, "")
+	if got := getTTLSecondsAfterFinished(modeljob); got != nil {
+		t.Errorf("getTTLSecondsAfterFinished() = %v, want nil", *got)
+	}
+
+	os.Setenv(ModelJobTTLSecondsAfterFinishedEnvKey, "3600")
+	if got := getTTLSecondsAfterFinished(modeljob); got == nil || *got != 3600 {
+		t.Errorf("getTTLSecondsAfterFinished() = %v, want 3600", got)
+	}
+
+	ttl := int32(60)
+	modeljob.Spec.TTLSecondsAfterFinished = &ttl
+	if got := getTTLSecondsAfterFinished(modeljob); got == nil || *got != ttl {
+		t.Errorf("getTTLSecondsAfterFinished() = %v, want %v", got, ttl)
+	}
+}
+
+func Test_selectExpiredModelJobs(t *testing.T) {
+	now := time.Now()
+	ttl := int32(60)
+	newModelJob := func(name, model string, phase modeljobsv1alpha1.ModelJobPhase, completedBefore time.Duration) modeljobsv1alpha1.ModelJob {
+		completionTime := metav1.NewTime(now.Add(-completedBefore))
+		return modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:                   model,
+				TTLSecondsAfterFinished: &ttl,
+			},
+			Status: modeljobsv1alpha1.ModelJobStatus{
+				Phase:          phase,
+				CompletionTime: &completionTime,
+			},
+		}
+	}
+	modeljobs := []modeljobsv1alpha1.ModelJob{
+		newModelJob("a-1", "release/a:v1", modeljobsv1alpha1.ModelJobSucceeded, 3*time.Minute),
+		newModelJob("a-2", "release/a:v2", modeljobsv1alpha1.ModelJobFailed, 2*time.Minute),
+		newModelJob("a-3", "release/a:v3", modeljobsv1alpha1.ModelJobSucceeded, 30*time.Second),
+		newModelJob("b-1", "release/b:v1", modeljobsv1alpha1.ModelJobSucceeded, 2*time.Minute),
+		newModelJob("c-1", "release/c:v1", modeljobsv1alpha1.ModelJobRunning, 2*time.Minute),
+		newModelJob("d-1", "release/d:v1", modeljobsv1alpha1.ModelJobSucceeded, 2*time.Minute),
+	}
+	// The modeljob of modelpipeline is deleted with the modelpipeline.
+	isController := true
+	modeljobs[5].OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: modeljobsv1alpha1.GroupVersion.String(),
+		Kind:       "ModelPipeline",
+		Name:       "pipeline",
+		Controller: &isController,
+	}}
+
+	tests := []struct {
+		name         string
+		historyLimit int
+		want         []string
+	}{
+		{
+			name:         "no history limit",
+			historyLimit: 0,
+			want:         []string{"a-1", "a-2", "b-1"},
+		},
+		{
+			name:         "keep the latest 2 modeljobs of each model",
+			historyLimit: 2,
+			want:         []string{"a-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]bool{}
+			for _, m := range selectExpiredModelJobs(modeljobs, tt.historyLimit, now) {
+				got[m.Name] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("selectExpiredModelJobs() = %v, want %v", got, tt.want)
+				return
+			}
+			for _, name := range tt.want {
+				if !got[name] {
+					t.Errorf("selectExpiredModelJobs() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// metricsNamespace is the prefix of the metrics of modeljob-operator.
+const metricsNamespace = "modeljob_operator"
+
+var (
+	modelJobsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "modeljobs"),
+		"Number of ModelJobs by phase, format and job type.",
+		[]string{"phase", "format", "type"}, nil,
+	)
+	modelJobQueueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "queue_depth"),
+		"Number of ModelJobs waiting in the queue for the limits of concurrent jobs.",
+		[]string{"namespace", "type"}, nil,
+	)
+
+	modelJobStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "stage_duration_seconds",
+			Help:      "Duration of the pull, task and push stages of ModelJobs.",
+			// From 1 second to about 4.5 hours.
+			Buckets: prometheus.ExponentialBuckets(1, 2, 15),
+		},
+		[]string{"stage", "format", "type"},
+	)
+	modelJobFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "failures_total",
+			Help:      "Number of failed ModelJob attempts by reason, format and job type.",
+		},
+		[]string{"reason", "format", "type"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(modelJobStageDuration, modelJobFailures)
+}
+
+// failureMessageToReason maps the messages of failed pods to the machine readable reasons.
+var failureMessageToReason = []struct {
+	message string
+	reason  string
+}{
+	{errPullImage, ReasonImagePull},
+	{errContainerOutOfMemory, ReasonOOMKilled},
+	{errContainerImageInvalid, ReasonInvalidImageName},
+	{errContainerCrashed, ReasonCrashLoopBackOff},
+	{errORMBLogin, reasonORMBLoginFailed},
+	{errORMBPull, reasonORMBPullFailed},
+	{errORMBExport, reasonORMBExportFailed},
+	{errORMBSave, reasonORMBSaveFailed},
+	{errORMBPush, reasonORMBPushFailed},
+	{errRunTask, reasonRunTaskFailed},
+	{errValidationFailed, reasonValidationFailed},
+	{errEphemeralStorageEvicted, reasonEphemeralStorageEvicted},
+}
+
+// getFailureReason returns the reason of the failure message, the detailed error of executor may be appended to it.
+func getFailureReason(message string) string {
+	for _, item := range failureMessageToReason {
+		if strings.HasPrefix(message, item.message) {
+			return item.reason
+		}
+	}
+	return reasonUnknownFailed
+}
+
+// getModelJobFormatAndType returns the source format and the job type of modeljob, the job type is
+// extract, convert or validate as the keys of preset images.
+func getModelJobFormatAndType(modeljob *modeljobsv1alpha1.ModelJob) (string, string) {
+	converterType, from, _ := getModelJobFormats(modeljob)
+	if jobType, ok := converterJobTypes[converterType]; ok {
+		return string(from), jobType
+	}
+	return "", "unknown"
+}
+
+// observeModelJobMetrics records the failures and stage durations when the status of modeljob changes.
+func observeModelJobMetrics(oldModelJob, modeljob *modeljobsv1alpha1.ModelJob) {
+	format, jobType := getModelJobFormatAndType(modeljob)
+
+	// Each failed attempt is counted once, including the retried ones.
+	newAttempts := 0
+	if len(modeljob.Status.FailedAttempts) > len(oldModelJob.Status.FailedAttempts) {
+		for _, attempt := range modeljob.Status.FailedAttempts[len(oldModelJob.Status.FailedAttempts):] {
+			reason := attempt.Reason
+			if reason == "" {
+				reason = reasonUnknownFailed
+			}
+			modelJobFailures.WithLabelValues(reason, format, jobType).Inc()
+			newAttempts++
+		}
+	}
+
+	if isModelJobFinished(oldModelJob) || !isModelJobFinished(modeljob) {
+		return
+	}
+	// The modeljob failed before its executor ran, e.g. the job can not be generated.
+	if modeljob.Status.Phase == modeljobsv1alpha1.ModelJobFailed && newAttempts == 0 {
+		modelJobFailures.WithLabelValues(getFailureReason(modeljob.Status.Message), format, jobType).Inc()
+	}
+
+	if modeljob.Status.Phase == modeljobsv1alpha1.ModelJobCancelled {
+		return
+	}
+	for stage, seconds := range getModelJobStageDurations(&modeljob.Status) {
+		modelJobStageDuration.WithLabelValues(stage, format, jobType).Observe(seconds)
+	}
+}
+
+// getModelJobStageDurations returns the seconds of the stages which completed. The pull stage is observed
+// from the conditions, the task and push stages are reported by the executor. The task stage includes the push
+// stage if the executor does not report them.
+func getModelJobStageDurations(status *modeljobsv1alpha1.ModelJobStatus) map[string]float64 {
+	durations := map[string]float64{}
+
+	scheduled := getModelJobCondition(status, modeljobsv1alpha1.ModelJobScheduled)
+	pulled := getModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPulled)
+	if isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobScheduled) &&
+		isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobModelPulled) {
+		durations[modelJobStagePull] = pulled.LastTransitionTime.Sub(scheduled.LastTransitionTime.Time).Seconds()
+	}
+
+	result := status.Result
+	if result != nil && result.TaskDuration != nil {
+		durations[modelJobStageTask] = result.TaskDuration.Seconds()
+	} else if completed := getModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted); completed != nil &&
+		isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobModelPulled) {
+		durations[modelJobStageTask] = completed.LastTransitionTime.Sub(pulled.LastTransitionTime.Time).Seconds()
+	}
+	if result != nil && result.PushDuration != nil {
+		durations[modelJobStagePush] = result.PushDuration.Seconds()
+	}
+
+	for stage, seconds := range durations {
+		if seconds < 0 {
+			delete(durations, stage)
+		}
+	}
+	return durations
+}
+
+// modelJobCollector collects the number of modeljobs and the depth of queue from the cache when it is scraped.
+type modelJobCollector struct {
+	client.Reader
+	Log logr.Logger
+}
+
+// Describe implements prometheus.Collector.
+func (c *modelJobCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- modelJobsDesc
+	ch <- modelJobQueueDepthDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *modelJobCollector) Collect(ch chan<- prometheus.Metric) {
+	modeljobs := &modeljobsv1alpha1.ModelJobList{}
+	if err := c.List(context.TODO(), modeljobs); err != nil {
+		c.Log.Error(err, "failed to list modeljobs for metrics")
+		return
+	}
+
+	counts := map[[3]string]int{}
+	queued := map[[2]string]int{}
+	for i := range modeljobs.Items {
+		modeljob := &modeljobs.Items[i]
+		format, jobType := getModelJobFormatAndType(modeljob)
+		counts[[3]string{string(modeljob.Status.Phase), format, jobType}]++
+		if modeljob.Status.Phase == modeljobsv1alpha1.ModelJobQueued {
+			queued[[2]string{modeljob.Namespace, jobType}]++
+		}
+	}
+
+	for labels, count := range counts {
+		ch <- prometheus.MustNewConstMetric(modelJobsDesc, prometheus.GaugeValue, float64(count), labels[:]...)
+	}
+	for labels, count := range queued {
+		ch <- prometheus.MustNewConstMetric(modelJobQueueDepthDesc, prometheus.GaugeValue, float64(count), labels[:]...)
+	}
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_getFailureReason(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{message: errORMBPush + ": unauthorized", want: reasonORMBPushFailed},
+		{message: errPullImage, want: ReasonImagePull},
+		{message: errContainerOutOfMemory, want: ReasonOOMKilled},
+		{message: "failed to generate job", want: reasonUnknownFailed},
+	}
+	for _, tt := range tests {
+		if got := getFailureReason(tt.message); got != tt.want {
+			t.Errorf("getFailureReason(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func Test_getModelJobStageDurations(t *testing.T) {
+	now := time.Now()
+	status := &modeljobsv1alpha1.ModelJobStatus{
+		Conditions: []modeljobsv1alpha1.ModelJobCondition{
+			{Type: modeljobsv1alpha1.ModelJobScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now)},
+			{Type: modeljobsv1alpha1.ModelJobModelPulled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(10 * time.Second))},
+			{Type: modeljobsv1alpha1.ModelJobTaskCompleted, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(70 * time.Second))},
+		},
+	}
+
+	got := getModelJobStageDurations(status)
+	if got[modelJobStagePull] != 10 || got[modelJobStageTask] != 60 || len(got) != 2 {
+		t.Errorf("getModelJobStageDurations() = %v, want pull 10s and task 60s from conditions", got)
+	}
+
+	status.Result = &modeljobsv1alpha1.ModelJobResult{
+		TaskDuration: &metav1.Duration{Duration: 40 * time.Second},
+		PushDuration: &metav1.Duration{Duration: 20 * time.Second},
+	}
+	got = getModelJobStageDurations(status)
+	if got[modelJobStagePull] != 10 || got[modelJobStageTask] != 40 || got[modelJobStagePush] != 20 {
+		t.Errorf("getModelJobStageDurations() = %v, want task and push from the result", got)
+	}
+}
+
+func Test_observeModelJobMetrics(t *testing.T) {
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatPMML},
+			},
+		},
+	}
+	oldModelJob := modeljob.DeepCopy()
+	modeljob.Status.Phase = modeljobsv1alpha1.ModelJobFailed
+	modeljob.Status.FailedAttempts = []modeljobsv1alpha1.ModelJobAttempt{{Attempt: 1, Reason: reasonORMBPushFailed}}
+
+	before := getMetricValue(t, metrics.Registry, "modeljob_operator_failures_total", reasonORMBPushFailed, "PMML")
+	observeModelJobMetrics(oldModelJob, modeljob)
+	// The status is not changed, the failure is not counted again.
+	observeModelJobMetrics(modeljob.DeepCopy(), modeljob)
+	after := getMetricValue(t, metrics.Registry, "modeljob_operator_failures_total", reasonORMBPushFailed, "PMML")
+	if after-before != 1 {
+		t.Errorf("observeModelJobMetrics() counted %v failures, want 1", after-before)
+	}
+}
+
+func Test_modelJobCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	objs := []runtime.Object{}
+	for i, phase := range []modeljobsv1alpha1.ModelJobPhase{
+		modeljobsv1alpha1.ModelJobQueued, modeljobsv1alpha1.ModelJobQueued, modeljobsv1alpha1.ModelJobRunning,
+	} {
+		modeljob := &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("conversion-%d", i)},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatH5,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				},
+			},
+			Status: modeljobsv1alpha1.ModelJobStatus{Phase: phase},
+		}
+		objs = append(objs, modeljob)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(&modelJobCollector{Reader: fake.NewFakeClientWithScheme(scheme, objs...), Log: ctrl.Log.WithName("test")})
+
+	if got := getMetricValue(t, registry, "modeljob_operator_modeljobs", "Queued", "H5", "convert"); got != 2 {
+		t.Errorf("modeljobs{phase=Queued} = %v, want 2", got)
+	}
+	if got := getMetricValue(t, registry, "modeljob_operator_queue_depth", "default", "convert"); got != 2 {
+		t.Errorf("queue_depth = %v, want 2", got)
+	}
+}
+
+// getMetricValue returns the value of the metric whose label values contain all of the values.
+func getMetricValue(t *testing.T, gatherer prometheus.Gatherer, name string, values ...string) float64 {
+	families, err := gatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]bool{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetValue()] = true
+			}
+			matched := true
+			for _, value := range values {
+				matched = matched && labels[value]
+			}
+			if !matched {
+				continue
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// MigrateModelJobStorageVersion rewrites the modeljobs in the storage version of the CustomResourceDefinition
+// if some of them were stored in the other versions, then removes the other versions from the stored versions
+// of the CustomResourceDefinition, so that they can be removed from the CustomResourceDefinition later.
+// The modeljobs and the CustomResourceDefinition are read by reader and updated by c, the apiserver converts
+// the modeljobs to the storage version when they are written.
+func MigrateModelJobStorageVersion(reader client.Reader, c client.Client) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := reader.Get(context.TODO(), types.NamespacedName{Name: ModelJobCRDName}, crd); err != nil {
+		return err
+	}
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version in crd %v", ModelJobCRDName)
+	}
+	storedVersions := crd.Status.StoredVersions
+	if len(storedVersions) == 0 || (len(storedVersions) == 1 && storedVersions[0] == storageVersion) {
+		return nil
+	}
+
+	modeljobs := &modeljobsv1alpha1.ModelJobList{}
+	if err := reader.List(context.TODO(), modeljobs); err != nil {
+		return err
+	}
+	for _, item := range modeljobs.Items {
+		key := types.NamespacedName{Namespace: item.Namespace, Name: item.Name}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			modeljob := &modeljobsv1alpha1.ModelJob{}
+			if err := reader.Get(context.TODO(), key, modeljob); err != nil {
+				return err
+			}
+			return c.Update(context.TODO(), modeljob)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to migrate modeljob %v: %w", key, err)
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := reader.Get(context.TODO(), types.NamespacedName{Name: ModelJobCRDName}, crd); err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		return c.Status().Update(context.TODO(), crd)
+	})
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_MigrateModelJobStorageVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+
+	newCRD := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: ModelJobCRDName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1beta1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet", ResourceVersion: "1"},
+	}
+
+	tests := []struct {
+		name               string
+		crd                *apiextensionsv1.CustomResourceDefinition
+		wantStoredVersions []string
+		wantMigrated       bool
+	}{
+		{
+			name:               "stored in v1alpha1",
+			crd:                newCRD("v1alpha1", "v1beta1"),
+			wantStoredVersions: []string{"v1beta1"},
+			wantMigrated:       true,
+		},
+		{
+			name:               "stored in v1beta1",
+			crd:                newCRD("v1beta1"),
+			wantStoredVersions: []string{"v1beta1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme, tt.crd, modeljob.DeepCopy())
+
+			if err := MigrateModelJobStorageVersion(c, c); err != nil {
+				t.Fatalf("MigrateModelJobStorageVersion() error = %v", err)
+			}
+
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: ModelJobCRDName}, crd); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(crd.Status.StoredVersions, tt.wantStoredVersions) {
+				t.Errorf("MigrateModelJobStorageVersion() storedVersions = %v, want %v", crd.Status.StoredVersions, tt.wantStoredVersions)
+			}
+			current := &modeljobsv1alpha1.ModelJob{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "resnet"}, current); err != nil {
+				t.Fatal(err)
+			}
+			if migrated := current.ResourceVersion != modeljob.ResourceVersion; migrated != tt.wantMigrated {
+				t.Errorf("MigrateModelJobStorageVersion() migrated = %v, want %v", migrated, tt.wantMigrated)
+			}
+		})
+	}
+}
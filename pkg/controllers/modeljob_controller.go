@@ -21,12 +21,22 @@ import (
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
 )
@@ -34,6 +44,9 @@ import (
 const (
 	// ControllerName is the name of the controller.
 	ControllerName = "modeljob-operator"
+
+	// podJobOwnerIndexKey is the index of pods by the name of job which controls them.
+	podJobOwnerIndexKey = ".metadata.controller.job"
 )
 
 // ModelJobReconciler reconciles a ModelJob object
@@ -42,10 +55,14 @@ type ModelJobReconciler struct {
 	record.EventRecorder
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	queue modelJobQueue
 }
 
 // +kubebuilder:rbac:groups=modeljobs.kleveross.io,resources=modeljobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=modeljobs.kleveross.io,resources=modeljobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=modeljobs.kleveross.io,resources=modelconverters,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
 
 func (r *ModelJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -68,9 +85,80 @@ func (r *ModelJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return r.reconcile(instance)
 }
 
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
 func (r *ModelJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &corev1.Pod{}, podJobOwnerIndexKey, indexPodByJobOwner); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &modeljobsv1alpha1.ModelJob{}, modelJobWaitingIndexKey, indexModelJobWaiting); err != nil {
+		return err
+	}
+	if err := metrics.Registry.Register(&modelJobCollector{Reader: mgr.GetClient(), Log: r.Log}); err != nil {
+		return err
+	}
+
+	// The pods of modeljob are watched, so the status of modeljob is updated when the state of containers changes.
+	// The jobs are also watched by the queue, the queued modeljobs are enqueued when the others finish.
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&modeljobsv1alpha1.ModelJob{}).
 		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, &modelJobQueueHandler{r: r}).
+		Watches(&source.Kind{Type: &corev1.Pod{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(mapPodToModelJob)},
+			builder.WithPredicates(modelJobPodPredicate())).
 		Complete(r)
 }
+
+// indexPodByJobOwner returns the name of job which controls the pod.
+func indexPodByJobOwner(obj runtime.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "Job" || owner.APIVersion != batchv1.SchemeGroupVersion.String() {
+		return nil
+	}
+	return []string{owner.Name}
+}
+
+// mapPodToModelJob returns the request of modeljob which the pod belongs to.
+func mapPodToModelJob(obj handler.MapObject) []reconcile.Request {
+	name, ok := obj.Meta.GetLabels()[modeljobsv1alpha1.ModelJobNameLabelKey]
+	if !ok || len(indexPodByJobOwner(obj.Object)) == 0 {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: name}}}
+}
+
+// modelJobPodPredicate filters out the pods which do not belong to modeljobs and the updates
+// which do not change the status of pods.
+func modelJobPodPredicate() predicate.Predicate {
+	isModelJobPod := func(meta metav1.Object) bool {
+		_, ok := meta.GetLabels()[modeljobsv1alpha1.ModelJobNameLabelKey]
+		return ok
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isModelJobPod(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isModelJobPod(e.MetaNew) {
+				return false
+			}
+			oldPod, oldOK := e.ObjectOld.(*corev1.Pod)
+			newPod, newOK := e.ObjectNew.(*corev1.Pod)
+			if !oldOK || !newOK {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(oldPod.Status, newPod.Status)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isModelJobPod(e.Meta)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return isModelJobPod(e.Meta)
+		},
+	}
+}
@@ -135,6 +135,15 @@ var _ = Describe("Modeljob Controller", func() {
 				return k8sClient.Get(context.Background(), key, job)
 			}, timeout, interval).Should(Succeed())
 
+			By("Expecting the modejob status to record start time and completion time")
+			Eventually(func() bool {
+				getedModelJob := &modeljobsv1alpha1.ModelJob{}
+				if err := k8sClient.Get(context.Background(), key, getedModelJob); err != nil {
+					return false
+				}
+				return getedModelJob.Status.StartTime != nil && getedModelJob.Status.CompletionTime != nil
+			}, timeout, interval).Should(BeTrue())
+
 			By("Create pod for modejob -> job -> pod")
 			job := &batchv1.Job{}
 			err := k8sClient.Get(context.Background(), key, job)
@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+const (
+	// ModelJobMutatingWebhookPath is the path of modeljob defaulting webhook.
+	ModelJobMutatingWebhookPath = "/mutate-kleveross-io-v1alpha1-modeljob"
+	// ModelJobValidatingWebhookPath is the path of modeljob validating webhook.
+	ModelJobValidatingWebhookPath = "/validate-kleveross-io-v1alpha1-modeljob"
+)
+
+// +kubebuilder:webhook:path=/mutate-kleveross-io-v1alpha1-modeljob,mutating=true,failurePolicy=fail,groups=kleveross.io,resources=modeljobs,verbs=create,versions=v1alpha1,name=mmodeljob.kleveross.io
+// +kubebuilder:webhook:path=/validate-kleveross-io-v1alpha1-modeljob,mutating=false,failurePolicy=fail,groups=kleveross.io,resources=modeljobs,verbs=create;update,versions=v1alpha1,name=vmodeljob.kleveross.io
+
+// SetupWebhookWithManager registers the defaulting, validating and conversion webhooks of modeljob.
+func SetupWebhookWithManager(mgr ctrl.Manager) {
+	server := mgr.GetWebhookServer()
+	server.Register(ModelJobMutatingWebhookPath, &webhook.Admission{Handler: &modelJobDefaulter{}})
+	server.Register(ModelJobValidatingWebhookPath, &webhook.Admission{Handler: &modelJobValidator{Client: mgr.GetClient()}})
+	server.Register(ModelJobConversionWebhookPath, &conversion.Webhook{})
+}
+
+// modelJobDefaulter fills in the defaults of modeljob.
+type modelJobDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder into modelJobDefaulter.
+func (d *modelJobDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle implements admission.Handler. The modeljobs are defaulted only when they are created, the defaults
+// which depend on the operator configuration are not changed by the updates of running modeljobs.
+func (d *modelJobDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create {
+		return admission.Allowed("")
+	}
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	if err := d.decoder.Decode(req, modeljob); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	defaultModelJob(modeljob)
+
+	marshaled, err := json.Marshal(modeljob)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// modelJobValidator rejects the modeljobs which can not be run by operator.
+type modelJobValidator struct {
+	client.Client
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder into modelJobValidator.
+func (v *modelJobValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle implements admission.Handler.
+func (v *modelJobValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	if err := v.decoder.Decode(req, modeljob); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The spec is not changed except the fields which control the modeljob, e.g. only labels are updated or
+	// the modeljob is suspended, do not reject it even if the operator configuration changed after it was created.
+	if req.Operation == admissionv1beta1.Update {
+		oldModelJob := &modeljobsv1alpha1.ModelJob{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldModelJob); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if isModelJobSpecUnchanged(&oldModelJob.Spec, &modeljob.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	converters := &modeljobsv1alpha1.ModelConverterList{}
+	if err := v.List(ctx, converters); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	errs := validateModelJob(modeljob, converters.Items)
+	if len(errs) == 0 {
+		return admission.Allowed("")
+	}
+
+	invalid := errors.NewInvalid(modeljobsv1alpha1.GroupVersion.WithKind("ModelJob").GroupKind(), modeljob.Name, errs)
+	return admission.Response{
+		AdmissionResponse: admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &invalid.ErrStatus,
+		},
+	}
+}
+
+// isModelJobSpecUnchanged returns true if the specs are the same except spec.suspend and spec.priority,
+// which only control when the job of modeljob runs.
+func isModelJobSpecUnchanged(oldSpec, newSpec *modeljobsv1alpha1.ModelJobSpec) bool {
+	oldSpec, newSpec = oldSpec.DeepCopy(), newSpec.DeepCopy()
+	oldSpec.Suspend, newSpec.Suspend = nil, nil
+	oldSpec.Priority, newSpec.Priority = nil, nil
+	return equality.Semantic.DeepEqual(oldSpec, newSpec)
+}
+
+// defaultModelJob fills in the labels of modeljob type and the default resources of operator.
+func defaultModelJob(modeljob *modeljobsv1alpha1.ModelJob) {
+	if modeljob.Labels == nil {
+		modeljob.Labels = map[string]string{}
+	}
+	if sources := getModelJobSources(&modeljob.Spec); len(sources) == 1 {
+		if _, ok := modeljob.Labels[sources[0].labelKey]; !ok {
+			modeljob.Labels[sources[0].labelKey] = "true"
+		}
+	}
+
+	// The default resources of operator are used when neither the typed resources nor the deprecated env are set,
+	// the cpu and mem are set together, otherwise they are ignored by generateResources.
+	for _, item := range []struct {
+		resources **corev1.ResourceRequirements
+		keys      []string
+	}{
+		{&modeljob.Spec.Resources, []string{ModelJobTaskCPUEnvKey, ModelJobTaskMEMEnvKey}},
+		{&modeljob.Spec.InitializerResources, []string{ModelInitializerCPUEnvKey, ModelInitializerMEMEnvKey}},
+	} {
+		if *item.resources != nil || hasEnv(modeljob.Spec.Env, item.keys[0]) || hasEnv(modeljob.Spec.Env, item.keys[1]) {
+			continue
+		}
+		cpu, mem := viper.GetString(item.keys[0]), viper.GetString(item.keys[1])
+		if cpu == "" || mem == "" {
+			continue
+		}
+		resources, err := generateResources(cpu, mem)
+		if err != nil {
+			continue
+		}
+		*item.resources = resources
+	}
+}
+
+// modelJobSource is a source of modeljob which is set.
+type modelJobSource struct {
+	name     string
+	labelKey string
+}
+
+// getModelJobSources returns the sources set in modeljob in the order of precedence, only one of them may be set.
+func getModelJobSources(spec *modeljobsv1alpha1.ModelJobSpec) []modelJobSource {
+	sources := []modelJobSource{}
+	for _, source := range []struct {
+		modelJobSource
+		set bool
+	}{
+		{modelJobSource{"extraction", modeljobsv1alpha1.ExtractLabelKey}, spec.Extraction != nil},
+		{modelJobSource{"conversion", modeljobsv1alpha1.ConvertLabelKey}, spec.Conversion != nil},
+		{modelJobSource{"validation", modeljobsv1alpha1.ValidateLabelKey}, spec.Validation != nil},
+		{modelJobSource{"optimization", modeljobsv1alpha1.OptimizeLabelKey}, spec.Optimization != nil},
+		{modelJobSource{"benchmark", modeljobsv1alpha1.BenchmarkLabelKey}, spec.Benchmark != nil},
+	} {
+		if source.set {
+			sources = append(sources, source.modelJobSource)
+		}
+	}
+	return sources
+}
+
+// validateModelJob validates the modeljob as generateJobResource does.
+func validateModelJob(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter) field.ErrorList {
+	errs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if _, err := replaceModelRefDomain(modeljob.Spec.Model, ""); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("model"), modeljob.Spec.Model,
+			"must be in the form of [domain/]project/model:version"))
+	}
+
+	sources := getModelJobSources(&modeljob.Spec)
+	switch {
+	case len(sources) > 1:
+		errs = append(errs, field.Forbidden(specPath.Child(sources[1].name),
+			fmt.Sprintf("may not be set when %v is set", sources[0].name)))
+	case modeljob.Spec.Extraction != nil:
+		errs = append(errs, validateExtractionSource(modeljob, converters, specPath.Child("extraction"))...)
+	case modeljob.Spec.Conversion != nil:
+		errs = append(errs, validateConversionSource(modeljob, converters, specPath)...)
+	case modeljob.Spec.Validation != nil:
+		errs = append(errs, validateValidationSource(modeljob, converters, specPath.Child("validation"))...)
+	case modeljob.Spec.Optimization != nil:
+		errs = append(errs, validateOptimizationSource(modeljob, converters, specPath)...)
+	case modeljob.Spec.Benchmark != nil:
+		errs = append(errs, validateBenchmarkSource(modeljob, converters, specPath.Child("benchmark"))...)
+	default:
+		errs = append(errs, field.Required(specPath, "one of extraction, conversion, validation, optimization or benchmark must be set"))
+	}
+
+	if modeljob.Spec.RegistryCredentials == nil && !isRegistryCredentialsFallbackEnabled() {
+		errs = append(errs, field.Required(specPath.Child("registryCredentials"),
+			"must be set when the registry credentials fallback of operator is disabled"))
+	}
+
+	errs = append(errs, validateModelJobScheduling(&modeljob.Spec, specPath)...)
+	errs = append(errs, validateWorkspace(modeljob.Spec.Workspace, specPath.Child("workspace"))...)
+	errs = append(errs, validateModelSource(&modeljob.Spec, specPath)...)
+	errs = append(errs, validateExecutor(&modeljob.Spec, specPath)...)
+
+	return errs
+}
+
+func validateExtractionSource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	fldPath *field.Path) field.ErrorList {
+	if hasExecutor(modeljob, converters) {
+		return nil
+	}
+	return validatePresetImage(modeljob.Spec.Extraction.Format, "extract", converters, fldPath.Child("format"))
+}
+
+func validateConversionSource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	specPath *field.Path) field.ErrorList {
+	errs := validateDesiredTag(modeljob, "conversion", specPath)
+
+	mmdnnPath := specPath.Child("conversion", "mmdnn")
+	mmdnn := modeljob.Spec.Conversion.MMdnn
+	if mmdnn == nil {
+		return append(errs, field.Required(mmdnnPath, "must be set for conversion"))
+	}
+	if mmdnn.To == "" {
+		errs = append(errs, field.Required(mmdnnPath.Child("to"), ""))
+	}
+	if hasExecutor(modeljob, converters) {
+		return errs
+	}
+	return append(errs, validatePresetImage(mmdnn.From, "convert", converters, mmdnnPath.Child("from"))...)
+}
+
+func validateValidationSource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	fldPath *field.Path) field.ErrorList {
+	errs := validateValidationParameters(modeljob.Spec.Validation, fldPath)
+	if hasExecutor(modeljob, converters) {
+		return errs
+	}
+	return append(errs, validatePresetImage(modeljob.Spec.Validation.Format, "validate", converters, fldPath.Child("format"))...)
+}
+
+func validateOptimizationSource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	specPath *field.Path) field.ErrorList {
+	errs := validateDesiredTag(modeljob, "optimization", specPath)
+
+	optimizationPath := specPath.Child("optimization")
+	if hasExecutor(modeljob, converters) {
+		return append(errs, validateOptimizationParameters(modeljob.Spec.Optimization, false, optimizationPath)...)
+	}
+	errs = append(errs, validateOptimizationParameters(modeljob.Spec.Optimization, true, optimizationPath)...)
+	return append(errs, validatePresetImage(modeljob.Spec.Optimization.Format, "optimize", converters, optimizationPath.Child("format"))...)
+}
+
+func validateBenchmarkSource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	fldPath *field.Path) field.ErrorList {
+	errs := validateBenchmarkParameters(modeljob.Spec.Benchmark, fldPath)
+	if hasExecutor(modeljob, converters) {
+		return errs
+	}
+	return append(errs, validatePresetImage(modeljob.Spec.Benchmark.Format, "benchmark", converters, fldPath.Child("format"))...)
+}
+
+// hasExecutor returns true if the executor of modeljob is spec.executor or a modelconverter, otherwise it is
+// the preset image of operator.
+func hasExecutor(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter) bool {
+	return modeljob.Spec.Executor != nil || findModelConverter(modeljob, converters) != nil
+}
+
+// validateDesiredTag validates the desired tag of the modeljobs which push new model.
+func validateDesiredTag(modeljob *modeljobsv1alpha1.ModelJob, source string, specPath *field.Path) field.ErrorList {
+	if modeljob.Spec.DesiredTag == nil {
+		return field.ErrorList{field.Required(specPath.Child("desiredTag"), "must be set for "+source)}
+	}
+	if _, err := replaceModelRefDomain(*modeljob.Spec.DesiredTag, ""); err != nil {
+		return field.ErrorList{field.Invalid(specPath.Child("desiredTag"), *modeljob.Spec.DesiredTag,
+			"must be in the form of [domain/]project/model:version")}
+	}
+	return nil
+}
+
+// validatePresetImage validates that the image of the format and job type is configured in operator,
+// it is used when no modelconverter can run the modeljob.
+func validatePresetImage(format modeljobsv1alpha1.Format, jobType string,
+	converters []modeljobsv1alpha1.ModelConverter, fldPath *field.Path) field.ErrorList {
+	if format == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+
+	imageEnv, ok := presetImage[strings.ToLower(string(format))+"-"+jobType]
+	if !ok {
+		return field.ErrorList{field.NotSupported(fldPath, format, getSupportedFormats(jobType, converters))}
+	}
+	if viper.GetString(imageEnv) == "" {
+		return field.ErrorList{field.Invalid(fldPath, format, fmt.Sprintf("the %v image of format is not configured", jobType))}
+	}
+
+	return nil
+}
+
+// getSupportedFormats returns the source formats which have preset images or modelconverters of the job type.
+func getSupportedFormats(jobType string, converters []modeljobsv1alpha1.ModelConverter) []string {
+	var converterType modeljobsv1alpha1.ModelConverterType
+	for t, name := range converterJobTypes {
+		if name == jobType {
+			converterType = t
+		}
+	}
+
+	formats := sets.NewString()
+	for key := range presetImage {
+		if strings.HasSuffix(key, "-"+jobType) {
+			formats.Insert(strings.TrimSuffix(key, "-"+jobType))
+		}
+	}
+	for _, converter := range converters {
+		if converter.Spec.Type != converterType {
+			continue
+		}
+		for _, format := range converter.Spec.SourceFormats {
+			formats.Insert(strings.ToLower(string(format)))
+		}
+	}
+	return formats.List()
+}
+
+func hasEnv(envs []corev1.EnvVar, name string) bool {
+	for _, env := range envs {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,428 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_validateModelJob(t *testing.T) {
+	viper.AutomaticEnv()
+	test.InitPresetModelImage()
+	viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, true)
+	viper.Set(ModelJobExecutorImageAllowlistEnvKey, "harbor.io")
+	defer viper.Set(ModelJobExecutorImageAllowlistEnvKey, "")
+
+	desiredTag := "release/savedmodel:v1"
+	invalidTag := "savedmodel:v1"
+	zero := int32(0)
+	tests := []struct {
+		name     string
+		spec     modeljobsv1alpha1.ModelJobSpec
+		wantErrs []string
+	}{
+		{
+			name: "extraction",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "harbor.io/release/savedmodel:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+		},
+		{
+			name: "conversion",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "release/h5:v1",
+				DesiredTag: &desiredTag,
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatH5,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "validation",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/savedmodel:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Validation: &modeljobsv1alpha1.ValidationSource{
+						Format:       modeljobsv1alpha1.FormatSavedModel,
+						SampleInputs: "samples/inputs.json",
+						Tolerance:    "1e-4",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid validation",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/graphdef:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Validation: &modeljobsv1alpha1.ValidationSource{
+						Format:       modeljobsv1alpha1.FormatGraphDef,
+						SampleInputs: "../inputs.json",
+						Tolerance:    "-1",
+					},
+				},
+			},
+			wantErrs: []string{"spec.validation.sampleInputs", "spec.validation.tolerance", "spec.validation.format"},
+		},
+		{
+			name: "both extraction and validation",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/savedmodel:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+					Validation: &modeljobsv1alpha1.ValidationSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.validation"},
+		},
+		{
+			name: "invalid benchmark",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/pmml:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Benchmark: &modeljobsv1alpha1.BenchmarkSource{
+						Format:      modeljobsv1alpha1.FormatPMML,
+						BatchSizes:  []int32{4},
+						Concurrency: []int32{0},
+						Requests:    &zero,
+					},
+				},
+			},
+			wantErrs: []string{"spec.benchmark.batchSizes[0]", "spec.benchmark.concurrency[0]",
+				"spec.benchmark.requests", "spec.benchmark.format"},
+		},
+		{
+			name: "both validation and benchmark",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/savedmodel:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Validation: &modeljobsv1alpha1.ValidationSource{Format: modeljobsv1alpha1.FormatSavedModel},
+					Benchmark:  &modeljobsv1alpha1.BenchmarkSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.benchmark"},
+		},
+		{
+			name: "neither extraction nor conversion",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/savedmodel:v1",
+			},
+			wantErrs: []string{"spec"},
+		},
+		{
+			name: "both extraction and conversion",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "release/savedmodel:v1",
+				DesiredTag: &desiredTag,
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+					Conversion: &modeljobsv1alpha1.ConversionSource{},
+				},
+			},
+			wantErrs: []string{"spec.conversion"},
+		},
+		{
+			name: "conversion without desired tag and mmdnn",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/h5:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{},
+				},
+			},
+			wantErrs: []string{"spec.desiredTag", "spec.conversion.mmdnn"},
+		},
+		{
+			name: "unsupported conversion format",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "release/onnx:v1",
+				DesiredTag: &desiredTag,
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatONNX,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				},
+			},
+			wantErrs: []string{"spec.conversion.mmdnn.from"},
+		},
+		{
+			name: "conversion format of executor",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "release/onnx:v1",
+				DesiredTag: &desiredTag,
+				Executor:   &modeljobsv1alpha1.Executor{Image: "harbor.io/team-a/onnx-to-savedmodel:v1"},
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Conversion: &modeljobsv1alpha1.ConversionSource{
+						MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+							ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+								From: modeljobsv1alpha1.FormatONNX,
+								To:   modeljobsv1alpha1.FormatSavedModel,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "executor image not allowed",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:    "release/savedmodel:v1",
+				Executor: &modeljobsv1alpha1.Executor{Image: "docker.io/someone/savedmodel-extract:v1"},
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.executor.image"},
+		},
+		{
+			name: "invalid model ref and resources",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:      "savedmodel:v1",
+				DesiredTag: &invalidTag,
+				Env: []corev1.EnvVar{
+					{Name: ModelJobTaskCPUEnvKey, Value: "one"},
+				},
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.model", "spec.env[0].value"},
+		},
+		{
+			name: "invalid scheduling",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/savedmodel:v1",
+				Resources: &corev1.ResourceRequirements{
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				},
+				NodeSelector: map[string]string{"gpu": "nvidia/tesla"},
+				Tolerations: []corev1.Toleration{
+					{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+					{Operator: corev1.TolerationOpEqual, Value: "true"},
+				},
+				PriorityClassName:  "High",
+				ServiceAccountName: "model-runner",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.resources.requests[cpu]", "spec.nodeSelector", "spec.tolerations[1].operator", "spec.priorityClassName"},
+		},
+		{
+			name: "registry credentials",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Model:               "harbor.io/release/savedmodel:v1",
+				RegistryCredentials: &modeljobsv1alpha1.RegistryCredentials{SecretName: "harbor"},
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateModelJob(&modeljobsv1alpha1.ModelJob{Spec: tt.spec}, nil)
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateModelJob() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateModelJob() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+	viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, false)
+	errs := validateModelJob(&modeljobsv1alpha1.ModelJob{Spec: tests[0].spec}, nil)
+	if len(errs) != 1 || errs[0].Field != "spec.registryCredentials" {
+		t.Errorf("validateModelJob() = %v, want error of spec.registryCredentials when fallback is disabled", errs)
+	}
+}
+
+func Test_validatePresetImage(t *testing.T) {
+	viper.AutomaticEnv()
+	test.InitPresetModelImage()
+	defer os.Setenv("SAVEDMODEL_EXTRACT_IMAGE", "demo.goharbor.com/release/savedmodel-extract:v0.2.0")
+
+	fldPath := field.NewPath("spec", "extraction", "format")
+	if errs := validatePresetImage(modeljobsv1alpha1.FormatSavedModel, "extract", nil, fldPath); len(errs) != 0 {
+		t.Errorf("validatePresetImage() = %v, want no errors", errs)
+	}
+
+	os.Setenv("SAVEDMODEL_EXTRACT_IMAGE", "")
+	errs := validatePresetImage(modeljobsv1alpha1.FormatSavedModel, "extract", nil, fldPath)
+	if len(errs) != 1 || errs[0].Type != field.ErrorTypeInvalid {
+		t.Errorf("validatePresetImage() = %v, want invalid error", errs)
+	}
+
+	errs = validatePresetImage(modeljobsv1alpha1.FormatTensorRT, "extract", nil, fldPath)
+	if len(errs) != 1 || errs[0].Type != field.ErrorTypeNotSupported {
+		t.Errorf("validatePresetImage() = %v, want not supported error", errs)
+	}
+}
+
+func Test_isModelJobSpecUnchanged(t *testing.T) {
+	suspend := true
+	priority := int32(10)
+	oldSpec := modeljobsv1alpha1.ModelJobSpec{
+		Model: "harbor.io/release/savedmodel:v1",
+		ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+			Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		update func(spec *modeljobsv1alpha1.ModelJobSpec)
+		want   bool
+	}{
+		{
+			name:   "not changed",
+			update: func(spec *modeljobsv1alpha1.ModelJobSpec) {},
+			want:   true,
+		},
+		{
+			name:   "suspend changed",
+			update: func(spec *modeljobsv1alpha1.ModelJobSpec) { spec.Suspend = &suspend },
+			want:   true,
+		},
+		{
+			name:   "priority changed",
+			update: func(spec *modeljobsv1alpha1.ModelJobSpec) { spec.Priority = &priority },
+			want:   true,
+		},
+		{
+			name:   "model changed",
+			update: func(spec *modeljobsv1alpha1.ModelJobSpec) { spec.Model = "harbor.io/release/savedmodel:v2" },
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newSpec := oldSpec.DeepCopy()
+			tt.update(newSpec)
+			if got := isModelJobSpecUnchanged(&oldSpec, newSpec); got != tt.want {
+				t.Errorf("isModelJobSpecUnchanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_defaultModelJob(t *testing.T) {
+	viper.AutomaticEnv()
+	os.Setenv(ModelJobTaskCPUEnvKey, "1")
+	os.Setenv(ModelJobTaskMEMEnvKey, "1Gi")
+	defer os.Unsetenv(ModelJobTaskCPUEnvKey)
+	defer os.Unsetenv(ModelJobTaskMEMEnvKey)
+
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Env: []corev1.EnvVar{
+				{Name: ModelInitializerCPUEnvKey, Value: "500m"},
+			},
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+			},
+		},
+	}
+	defaultModelJob(modeljob)
+
+	if modeljob.Labels[modeljobsv1alpha1.ExtractLabelKey] != "true" {
+		t.Errorf("defaultModelJob() labels = %v, want %v label", modeljob.Labels, modeljobsv1alpha1.ExtractLabelKey)
+	}
+	if _, ok := modeljob.Labels[modeljobsv1alpha1.ConvertLabelKey]; ok {
+		t.Errorf("defaultModelJob() labels = %v, want no %v label", modeljob.Labels, modeljobsv1alpha1.ConvertLabelKey)
+	}
+	if len(modeljob.Spec.Env) != 1 {
+		t.Errorf("defaultModelJob() env = %v, want the env unchanged", modeljob.Spec.Env)
+	}
+	if modeljob.Spec.Resources == nil || !modeljob.Spec.Resources.Limits.Cpu().Equal(resource.MustParse("1")) ||
+		!modeljob.Spec.Resources.Requests.Memory().Equal(resource.MustParse("1Gi")) {
+		t.Errorf("defaultModelJob() resources = %v, want the default resources of operator", modeljob.Spec.Resources)
+	}
+	if modeljob.Spec.InitializerResources != nil {
+		t.Errorf("defaultModelJob() initializerResources = %v, want nil when the deprecated env is set", modeljob.Spec.InitializerResources)
+	}
+}
+
+func Test_modelJobDefaulter_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaulter := &modelJobDefaulter{decoder: decoder}
+
+	raw, err := json.Marshal(&modeljobsv1alpha1.ModelJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: modeljobsv1alpha1.GroupVersion.String(), Kind: "ModelJob"},
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1beta1.Operation
+		wantPatched bool
+	}{
+		{
+			name:        "created",
+			operation:   admissionv1beta1.Create,
+			wantPatched: true,
+		},
+		{
+			name:      "updated",
+			operation: admissionv1beta1.Update,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := defaulter.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: tt.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+				OldObject: runtime.RawExtension{Raw: raw},
+			}})
+			if !resp.Allowed {
+				t.Fatalf("Handle() = %v, want allowed", resp.Result)
+			}
+			if (len(resp.Patches) != 0) != tt.wantPatched {
+				t.Errorf("Handle() patches = %v, want patched %v", resp.Patches, tt.wantPatched)
+			}
+		})
+	}
+}
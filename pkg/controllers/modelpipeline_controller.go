@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// ModelPipelineReconciler reconciles a ModelPipeline object
+type ModelPipelineReconciler struct {
+	client.Client
+	record.EventRecorder
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=modeljobs.kleveross.io,resources=modelpipelines,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=modeljobs.kleveross.io,resources=modelpipelines/status,verbs=get;update;patch
+
+func (r *ModelPipelineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("modelpipeline", req.NamespacedName)
+
+	pipeline := &modeljobsv1alpha1.ModelPipeline{}
+	err := r.Get(context.TODO(), req.NamespacedName, pipeline)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if pipeline.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	return r.reconcile(pipeline)
+}
+
+func (r *ModelPipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&modeljobsv1alpha1.ModelPipeline{}).
+		Owns(&modeljobsv1alpha1.ModelJob{}).
+		Complete(r)
+}
+
+func (r *ModelPipelineReconciler) reconcile(pipeline *modeljobsv1alpha1.ModelPipeline) (ctrl.Result, error) {
+	oldStatus := pipeline.Status.DeepCopy()
+	pipeline.Status.ObservedGeneration = pipeline.Generation
+
+	deps := getStepDependencies(&pipeline.Spec)
+	sorted, err := sortModelPipelineSteps(&pipeline.Spec, deps)
+	if err != nil {
+		if pipeline.Status.Phase != modeljobsv1alpha1.ModelPipelineFailed {
+			r.Event(pipeline, corev1.EventTypeWarning, "InvalidSpec", err.Error())
+		}
+		pipeline.Status.Phase = modeljobsv1alpha1.ModelPipelineFailed
+		pipeline.Status.Message = err.Error()
+		return reconcile.Result{}, r.updateStatus(pipeline, oldStatus)
+	}
+
+	initModelPipelineStepStatuses(pipeline)
+
+	if stepName, ok := pipeline.Annotations[modeljobsv1alpha1.ModelPipelineRerunFromStepAnnotationKey]; ok {
+		return reconcile.Result{}, r.rerunFromStep(pipeline, oldStatus, deps, stepName)
+	}
+
+	for _, name := range sorted {
+		if err := r.syncStep(pipeline, deps, name); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	updateModelPipelinePhase(&pipeline.Status)
+	if pipeline.Status.Phase != modeljobsv1alpha1.ModelPipelinePending && pipeline.Status.StartTime == nil {
+		now := metav1.Now()
+		pipeline.Status.StartTime = &now
+	}
+	if pipeline.Status.Phase != oldStatus.Phase {
+		switch pipeline.Status.Phase {
+		case modeljobsv1alpha1.ModelPipelineSucceeded:
+			now := metav1.Now()
+			pipeline.Status.CompletionTime = &now
+			r.Event(pipeline, corev1.EventTypeNormal, "Succeeded", "all steps of modelpipeline succeeded")
+		case modeljobsv1alpha1.ModelPipelineFailed:
+			now := metav1.Now()
+			pipeline.Status.CompletionTime = &now
+			r.Event(pipeline, corev1.EventTypeWarning, "Failed", pipeline.Status.Message)
+		}
+	}
+
+	return reconcile.Result{}, r.updateStatus(pipeline, oldStatus)
+}
+
+// syncStep starts the modeljob of step after its dependencies succeeded, and syncs the phase of step from the modeljob.
+func (r *ModelPipelineReconciler) syncStep(pipeline *modeljobsv1alpha1.ModelPipeline, deps map[string][]string, name string) error {
+	status := getModelPipelineStepStatus(&pipeline.Status, name)
+	if isModelPipelineStepFinished(status) {
+		return nil
+	}
+
+	if status.ModelJob == "" {
+		for _, dep := range deps[name] {
+			depStatus := getModelPipelineStepStatus(&pipeline.Status, dep)
+			switch depStatus.Phase {
+			case modeljobsv1alpha1.ModelPipelineFailed, modeljobsv1alpha1.ModelPipelineSkipped:
+				status.Phase = modeljobsv1alpha1.ModelPipelineSkipped
+				status.Message = fmt.Sprintf("step %v it depends on is %v", dep, depStatus.Phase)
+				r.Eventf(pipeline, corev1.EventTypeWarning, "StepSkipped", "step %v is skipped: %v", name, status.Message)
+				return nil
+			case modeljobsv1alpha1.ModelPipelineSucceeded:
+			default:
+				return nil
+			}
+		}
+		return r.startStep(pipeline, deps, status)
+	}
+
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: pipeline.Namespace, Name: status.ModelJob}, modeljob)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		status.Phase = modeljobsv1alpha1.ModelPipelineFailed
+		status.Message = fmt.Sprintf("modeljob %v is not found", status.ModelJob)
+	} else {
+		switch modeljob.Status.Phase {
+		case modeljobsv1alpha1.ModelJobSucceeded:
+			status.Phase = modeljobsv1alpha1.ModelPipelineSucceeded
+			status.Message = ""
+			status.OutputModel = getStepOutputModel(modeljob)
+		case modeljobsv1alpha1.ModelJobFailed, modeljobsv1alpha1.ModelJobCancelled:
+			status.Phase = modeljobsv1alpha1.ModelPipelineFailed
+			status.Message = modeljob.Status.Message
+		default:
+			return nil
+		}
+	}
+
+	now := metav1.Now()
+	status.CompletionTime = &now
+	if status.Phase == modeljobsv1alpha1.ModelPipelineSucceeded {
+		r.Eventf(pipeline, corev1.EventTypeNormal, "StepSucceeded", "step %v succeeded, output model is %v", name, status.OutputModel)
+	} else {
+		r.Eventf(pipeline, corev1.EventTypeWarning, "StepFailed", "step %v failed: %v", name, status.Message)
+	}
+	return nil
+}
+
+// startStep creates the modeljob of step, the modeljob which already exists is reused.
+func (r *ModelPipelineReconciler) startStep(pipeline *modeljobsv1alpha1.ModelPipeline, deps map[string][]string,
+	status *modeljobsv1alpha1.ModelPipelineStepStatus) error {
+	step := getModelPipelineStep(&pipeline.Spec, status.Name)
+	model := getStepInputModel(pipeline, step, deps)
+	modeljob := generateStepModelJob(pipeline, step, getStepModelJobName(pipeline, step.Name, status.Runs), model)
+	if err := controllerutil.SetControllerReference(pipeline, modeljob, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(context.TODO(), modeljob); err != nil && !errors.IsAlreadyExists(err) {
+		r.Eventf(pipeline, corev1.EventTypeWarning, "FailedCreate", "failed to create modeljob of step %v: %v", step.Name, err)
+		return err
+	}
+
+	now := metav1.Now()
+	status.Phase = modeljobsv1alpha1.ModelPipelineRunning
+	status.Message = ""
+	status.ModelJob = modeljob.Name
+	status.Model = model
+	status.StartTime = &now
+	r.Eventf(pipeline, corev1.EventTypeNormal, "StepStarted", "step %v started modeljob %v with model %v", step.Name, modeljob.Name, model)
+	return nil
+}
+
+// rerunFromStep resets the step and all steps depending on it, then removes the rerun annotation.
+func (r *ModelPipelineReconciler) rerunFromStep(pipeline *modeljobsv1alpha1.ModelPipeline,
+	oldStatus *modeljobsv1alpha1.ModelPipelineStatus, deps map[string][]string, stepName string) error {
+	if getModelPipelineStep(&pipeline.Spec, stepName) == nil {
+		r.Eventf(pipeline, corev1.EventTypeWarning, "InvalidRerun", "step %v to rerun from is not found", stepName)
+	} else {
+		descendants := getDescendantSteps(deps, stepName)
+		for i := range pipeline.Status.Steps {
+			status := &pipeline.Status.Steps[i]
+			if !descendants.Has(status.Name) {
+				continue
+			}
+			if status.Phase == modeljobsv1alpha1.ModelPipelineRunning && status.ModelJob != "" {
+				modeljob := &modeljobsv1alpha1.ModelJob{}
+				modeljob.Namespace, modeljob.Name = pipeline.Namespace, status.ModelJob
+				if err := r.Delete(context.TODO(), modeljob); err != nil && !errors.IsNotFound(err) {
+					return err
+				}
+			}
+			if status.ModelJob != "" {
+				status.Runs++
+			}
+			*status = modeljobsv1alpha1.ModelPipelineStepStatus{
+				Name:  status.Name,
+				Phase: modeljobsv1alpha1.ModelPipelinePending,
+				Runs:  status.Runs,
+			}
+		}
+		updateModelPipelinePhase(&pipeline.Status)
+		pipeline.Status.CompletionTime = nil
+		r.Eventf(pipeline, corev1.EventTypeNormal, "Rerun", "rerun modelpipeline from step %v", stepName)
+	}
+
+	if err := r.updateStatus(pipeline, oldStatus); err != nil {
+		return err
+	}
+
+	delete(pipeline.Annotations, modeljobsv1alpha1.ModelPipelineRerunFromStepAnnotationKey)
+	return r.Update(context.TODO(), pipeline)
+}
+
+func (r *ModelPipelineReconciler) updateStatus(pipeline *modeljobsv1alpha1.ModelPipeline,
+	oldStatus *modeljobsv1alpha1.ModelPipelineStatus) error {
+	if equality.Semantic.DeepEqual(oldStatus, &pipeline.Status) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), pipeline)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// presetOptimizations is the optimizations which the preset optimization images support for each format.
+var presetOptimizations = map[modeljobsv1alpha1.Format][]modeljobsv1alpha1.OptimizationType{
+	modeljobsv1alpha1.FormatONNX: {
+		modeljobsv1alpha1.OptimizationUpgradeOpset,
+		modeljobsv1alpha1.OptimizationSimplify,
+		modeljobsv1alpha1.OptimizationDynamicQuantization,
+	},
+	modeljobsv1alpha1.FormatSavedModel: {
+		modeljobsv1alpha1.OptimizationConstantFolding,
+	},
+}
+
+// generateOptimizationEnv generates the env of executor for the optimization source.
+func generateOptimizationEnv(optimization *modeljobsv1alpha1.OptimizationSource) []corev1.EnvVar {
+	if optimization == nil {
+		return nil
+	}
+
+	optimizations := make([]string, 0, len(optimization.Optimizations))
+	for _, o := range optimization.Optimizations {
+		optimizations = append(optimizations, string(o))
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  modeljobsv1alpha1.OptimizationsEnvKey,
+			Value: strings.Join(optimizations, ","),
+		},
+	}
+	if optimization.TargetOpset != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  modeljobsv1alpha1.TargetOpsetEnvKey,
+			Value: strconv.Itoa(int(*optimization.TargetOpset)),
+		})
+	}
+	return envs
+}
+
+// validateOptimizationParameters validates the optimizations and target opset of optimization source, the
+// optimizations are checked against those of the preset images if presetOnly is true.
+func validateOptimizationParameters(optimization *modeljobsv1alpha1.OptimizationSource, presetOnly bool,
+	fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	optimizationsPath := fldPath.Child("optimizations")
+
+	if len(optimization.Optimizations) == 0 {
+		errs = append(errs, field.Required(optimizationsPath, "must have at least one optimization"))
+	}
+	supported := sets.NewString()
+	for _, o := range presetOptimizations[optimization.Format] {
+		supported.Insert(string(o))
+	}
+	seen := sets.NewString()
+	for i, o := range optimization.Optimizations {
+		if seen.Has(string(o)) {
+			errs = append(errs, field.Duplicate(optimizationsPath.Index(i), o))
+			continue
+		}
+		seen.Insert(string(o))
+		if presetOnly && supported.Len() != 0 && !supported.Has(string(o)) {
+			errs = append(errs, field.NotSupported(optimizationsPath.Index(i), o, supported.List()))
+		}
+	}
+
+	opsetPath := fldPath.Child("targetOpset")
+	if optimization.TargetOpset != nil && *optimization.TargetOpset <= 0 {
+		errs = append(errs, field.Invalid(opsetPath, *optimization.TargetOpset, "must be greater than 0"))
+	}
+	if seen.Has(string(modeljobsv1alpha1.OptimizationUpgradeOpset)) && optimization.TargetOpset == nil {
+		errs = append(errs, field.Required(opsetPath, "must be set for UpgradeOpset"))
+	}
+
+	return errs
+}
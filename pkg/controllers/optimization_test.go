@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_generateJobResource_optimization(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+	viper.Set("ONNX_OPTIMIZATION_IMAGE", "demo.goharbor.com/release/onnx-optimize:v0.3.0")
+	defer viper.Set("ONNX_OPTIMIZATION_IMAGE", "")
+	viper.Set(KleverModelRegistryAddressEnvKey, "klever-model-registry:8080")
+	defer viper.Set(KleverModelRegistryAddressEnvKey, "")
+
+	desiredTag := "release/onnx:v1-int8"
+	targetOpset := int32(13)
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model:      "release/onnx:v1",
+			DesiredTag: &desiredTag,
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Optimization: &modeljobsv1alpha1.OptimizationSource{
+					Format: modeljobsv1alpha1.FormatONNX,
+					Optimizations: []modeljobsv1alpha1.OptimizationType{
+						modeljobsv1alpha1.OptimizationSimplify,
+						modeljobsv1alpha1.OptimizationUpgradeOpset,
+					},
+					TargetOpset: &targetOpset,
+				},
+			},
+		},
+	}
+	job, err := generateJobResource(modeljob, nil, "")
+	if err != nil {
+		t.Fatalf("generateJobResource() error = %v", err)
+	}
+
+	envs := map[string]string{}
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		envs[env.Name] = env.Value
+	}
+	for name, want := range map[string]string{
+		modeljobsv1alpha1.TaskEnvKey:                "optimize",
+		modeljobsv1alpha1.DestinationModelTagEnvKey: "klever-model-registry:8080/release/onnx:v1-int8",
+		modeljobsv1alpha1.OptimizationsEnvKey:       "Simplify,UpgradeOpset",
+		modeljobsv1alpha1.TargetOpsetEnvKey:         "13",
+	} {
+		if envs[name] != want {
+			t.Errorf("generateJobResource() env %v = %v, want %v", name, envs[name], want)
+		}
+	}
+	if got := job.Spec.Template.Spec.Containers[0].Image; got != "demo.goharbor.com/release/onnx-optimize:v0.3.0" {
+		t.Errorf("generateJobResource() image = %v, want the optimization image", got)
+	}
+}
+
+func Test_validateOptimizationParameters(t *testing.T) {
+	targetOpset := int32(0)
+	tests := []struct {
+		name         string
+		optimization *modeljobsv1alpha1.OptimizationSource
+		presetOnly   bool
+		wantErrs     []string
+	}{
+		{
+			name: "onnx",
+			optimization: &modeljobsv1alpha1.OptimizationSource{
+				Format: modeljobsv1alpha1.FormatONNX,
+				Optimizations: []modeljobsv1alpha1.OptimizationType{
+					modeljobsv1alpha1.OptimizationSimplify,
+					modeljobsv1alpha1.OptimizationDynamicQuantization,
+				},
+			},
+			presetOnly: true,
+		},
+		{
+			name:         "no optimizations",
+			optimization: &modeljobsv1alpha1.OptimizationSource{Format: modeljobsv1alpha1.FormatSavedModel},
+			presetOnly:   true,
+			wantErrs:     []string{"optimizations"},
+		},
+		{
+			name: "unsupported and duplicate optimizations",
+			optimization: &modeljobsv1alpha1.OptimizationSource{
+				Format: modeljobsv1alpha1.FormatSavedModel,
+				Optimizations: []modeljobsv1alpha1.OptimizationType{
+					modeljobsv1alpha1.OptimizationSimplify,
+					modeljobsv1alpha1.OptimizationSimplify,
+				},
+			},
+			presetOnly: true,
+			wantErrs:   []string{"optimizations[0]", "optimizations[1]"},
+		},
+		{
+			name: "optimizations of modelconverter",
+			optimization: &modeljobsv1alpha1.OptimizationSource{
+				Format:        modeljobsv1alpha1.FormatSavedModel,
+				Optimizations: []modeljobsv1alpha1.OptimizationType{"Pruning"},
+			},
+		},
+		{
+			name: "invalid target opset",
+			optimization: &modeljobsv1alpha1.OptimizationSource{
+				Format:        modeljobsv1alpha1.FormatONNX,
+				Optimizations: []modeljobsv1alpha1.OptimizationType{modeljobsv1alpha1.OptimizationUpgradeOpset},
+				TargetOpset:   &targetOpset,
+			},
+			presetOnly: true,
+			wantErrs:   []string{"targetOpset"},
+		},
+		{
+			name: "no target opset",
+			optimization: &modeljobsv1alpha1.OptimizationSource{
+				Format:        modeljobsv1alpha1.FormatONNX,
+				Optimizations: []modeljobsv1alpha1.OptimizationType{modeljobsv1alpha1.OptimizationUpgradeOpset},
+			},
+			presetOnly: true,
+			wantErrs:   []string{"targetOpset"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateOptimizationParameters(tt.optimization, tt.presetOnly, nil)
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateOptimizationParameters() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateOptimizationParameters() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
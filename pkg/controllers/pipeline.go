@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// getStepDependencies returns the dependencies of each step. If no step declares dependsOn,
+// each step depends on the previous one, otherwise the steps run as a DAG.
+func getStepDependencies(spec *modeljobsv1alpha1.ModelPipelineSpec) map[string][]string {
+	isDAG := false
+	for _, step := range spec.Steps {
+		if len(step.DependsOn) != 0 {
+			isDAG = true
+			break
+		}
+	}
+
+	deps := map[string][]string{}
+	for i, step := range spec.Steps {
+		switch {
+		case isDAG:
+			deps[step.Name] = step.DependsOn
+		case i > 0:
+			deps[step.Name] = []string{spec.Steps[i-1].Name}
+		default:
+			deps[step.Name] = nil
+		}
+	}
+	return deps
+}
+
+// sortModelPipelineSteps validates the steps of pipeline and returns the step names in topological order,
+// the steps which have no dependencies between them keep the order of declaration.
+func sortModelPipelineSteps(spec *modeljobsv1alpha1.ModelPipelineSpec, deps map[string][]string) ([]string, error) {
+	names := sets.NewString()
+	for _, step := range spec.Steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("the name of step is empty")
+		}
+		if names.Has(step.Name) {
+			return nil, fmt.Errorf("the name of step %v is duplicated", step.Name)
+		}
+		names.Insert(step.Name)
+	}
+
+	for _, step := range spec.Steps {
+		for _, dep := range deps[step.Name] {
+			if !names.Has(dep) {
+				return nil, fmt.Errorf("step %v depends on nonexistent step %v", step.Name, dep)
+			}
+		}
+		if step.ModelFrom != "" && !sets.NewString(deps[step.Name]...).Has(step.ModelFrom) {
+			return nil, fmt.Errorf("the modelFrom %v of step %v is not in its dependencies", step.ModelFrom, step.Name)
+		}
+	}
+
+	sorted := []string{}
+	done := sets.NewString()
+	for len(sorted) < len(spec.Steps) {
+		progressed := false
+		for _, step := range spec.Steps {
+			if done.Has(step.Name) || !done.HasAll(deps[step.Name]...) {
+				continue
+			}
+			sorted = append(sorted, step.Name)
+			done.Insert(step.Name)
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("the dependencies of steps %v are cyclic", names.Difference(done).List())
+		}
+	}
+
+	return sorted, nil
+}
+
+// getDescendantSteps returns the step and all steps which depend on it directly or indirectly.
+func getDescendantSteps(deps map[string][]string, stepName string) sets.String {
+	descendants := sets.NewString(stepName)
+	for {
+		added := false
+		for name, stepDeps := range deps {
+			if !descendants.Has(name) && descendants.HasAny(stepDeps...) {
+				descendants.Insert(name)
+				added = true
+			}
+		}
+		if !added {
+			return descendants
+		}
+	}
+}
+
+// getModelPipelineStep returns the step with the name.
+func getModelPipelineStep(spec *modeljobsv1alpha1.ModelPipelineSpec, name string) *modeljobsv1alpha1.ModelPipelineStep {
+	for i := range spec.Steps {
+		if spec.Steps[i].Name == name {
+			return &spec.Steps[i]
+		}
+	}
+	return nil
+}
+
+// getModelPipelineStepStatus returns the status of step with the name.
+func getModelPipelineStepStatus(status *modeljobsv1alpha1.ModelPipelineStatus, name string) *modeljobsv1alpha1.ModelPipelineStepStatus {
+	for i := range status.Steps {
+		if status.Steps[i].Name == name {
+			return &status.Steps[i]
+		}
+	}
+	return nil
+}
+
+// initModelPipelineStepStatuses keeps the status of each step in the order of spec.
+func initModelPipelineStepStatuses(pipeline *modeljobsv1alpha1.ModelPipeline) {
+	statuses := make([]modeljobsv1alpha1.ModelPipelineStepStatus, 0, len(pipeline.Spec.Steps))
+	for _, step := range pipeline.Spec.Steps {
+		status := getModelPipelineStepStatus(&pipeline.Status, step.Name)
+		if status == nil {
+			status = &modeljobsv1alpha1.ModelPipelineStepStatus{
+				Name:  step.Name,
+				Phase: modeljobsv1alpha1.ModelPipelinePending,
+			}
+		}
+		statuses = append(statuses, *status)
+	}
+	pipeline.Status.Steps = statuses
+}
+
+// isModelPipelineStepFinished returns true if the step is succeeded, failed or skipped.
+func isModelPipelineStepFinished(status *modeljobsv1alpha1.ModelPipelineStepStatus) bool {
+	return status.Phase == modeljobsv1alpha1.ModelPipelineSucceeded ||
+		status.Phase == modeljobsv1alpha1.ModelPipelineFailed ||
+		status.Phase == modeljobsv1alpha1.ModelPipelineSkipped
+}
+
+// getStepInputModel returns the input model of step, it is the output model of modelFrom step,
+// or the first dependency, or the model of pipeline.
+func getStepInputModel(pipeline *modeljobsv1alpha1.ModelPipeline, step *modeljobsv1alpha1.ModelPipelineStep,
+	deps map[string][]string) string {
+	modelFrom := step.ModelFrom
+	if modelFrom == "" && len(deps[step.Name]) != 0 {
+		modelFrom = deps[step.Name][0]
+	}
+	if modelFrom == "" {
+		return pipeline.Spec.Model
+	}
+
+	if status := getModelPipelineStepStatus(&pipeline.Status, modelFrom); status != nil {
+		return status.OutputModel
+	}
+	return ""
+}
+
+// getStepOutputModel returns the output model of the modeljob of step, it is the desired tag
+// of conversion and optimization, or the input model of others which do not produce new model.
+func getStepOutputModel(modeljob *modeljobsv1alpha1.ModelJob) string {
+	if (modeljob.Spec.Conversion != nil || modeljob.Spec.Optimization != nil) && modeljob.Spec.DesiredTag != nil {
+		return *modeljob.Spec.DesiredTag
+	}
+	return modeljob.Spec.Model
+}
+
+// getStepModelJobName returns the name of modeljob for each run of step.
+func getStepModelJobName(pipeline *modeljobsv1alpha1.ModelPipeline, stepName string, runs int32) string {
+	return fmt.Sprintf("%v-%v-%d", pipeline.Name, stepName, runs)
+}
+
+// generateStepModelJob generates the modeljob of step with the input model.
+func generateStepModelJob(pipeline *modeljobsv1alpha1.ModelPipeline, step *modeljobsv1alpha1.ModelPipelineStep,
+	name, model string) *modeljobsv1alpha1.ModelJob {
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	modeljob.Name = name
+	modeljob.Namespace = pipeline.Namespace
+	modeljob.Labels = map[string]string{
+		modeljobsv1alpha1.ModelPipelineLabelKey:     pipeline.Name,
+		modeljobsv1alpha1.ModelPipelineStepLabelKey: step.Name,
+	}
+	for _, source := range getModelJobSources(&step.Template) {
+		modeljob.Labels[source.labelKey] = "true"
+	}
+	modeljob.Spec = *step.Template.DeepCopy()
+	modeljob.Spec.Model = model
+
+	return modeljob
+}
+
+// updateModelPipelinePhase aggregates the phase of pipeline from its steps. The pipeline fails after
+// all steps finished, the steps which do not depend on the failed step still run.
+func updateModelPipelinePhase(status *modeljobsv1alpha1.ModelPipelineStatus) {
+	succeeded, finished, started := 0, 0, 0
+	var failed *modeljobsv1alpha1.ModelPipelineStepStatus
+	for i := range status.Steps {
+		step := &status.Steps[i]
+		if step.Phase != modeljobsv1alpha1.ModelPipelinePending && step.Phase != "" {
+			started++
+		}
+		if isModelPipelineStepFinished(step) {
+			finished++
+		}
+		if step.Phase == modeljobsv1alpha1.ModelPipelineSucceeded {
+			succeeded++
+		}
+		if step.Phase == modeljobsv1alpha1.ModelPipelineFailed && failed == nil {
+			failed = step
+		}
+	}
+
+	switch {
+	case succeeded == len(status.Steps):
+		status.Phase = modeljobsv1alpha1.ModelPipelineSucceeded
+		status.Message = ""
+	case finished == len(status.Steps):
+		status.Phase = modeljobsv1alpha1.ModelPipelineFailed
+		if failed != nil {
+			status.Message = fmt.Sprintf("step %v failed: %v", failed.Name, failed.Message)
+		}
+	case started != 0:
+		status.Phase = modeljobsv1alpha1.ModelPipelineRunning
+		status.Message = ""
+	default:
+		status.Phase = modeljobsv1alpha1.ModelPipelinePending
+		status.Message = ""
+	}
+}
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_sortModelPipelineSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []modeljobsv1alpha1.ModelPipelineStep
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "sequential",
+			steps: []modeljobsv1alpha1.ModelPipelineStep{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name: "dag",
+			steps: []modeljobsv1alpha1.ModelPipelineStep{
+				{Name: "c", DependsOn: []string{"a", "b"}, ModelFrom: "b"},
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name:    "duplicated",
+			steps:   []modeljobsv1alpha1.ModelPipelineStep{{Name: "a"}, {Name: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "nonexistent dependency",
+			steps:   []modeljobsv1alpha1.ModelPipelineStep{{Name: "a", DependsOn: []string{"b"}}},
+			wantErr: true,
+		},
+		{
+			name: "modelFrom not in dependencies",
+			steps: []modeljobsv1alpha1.ModelPipelineStep{
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c", DependsOn: []string{"a"}, ModelFrom: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cyclic",
+			steps: []modeljobsv1alpha1.ModelPipelineStep{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &modeljobsv1alpha1.ModelPipelineSpec{Steps: tt.steps}
+			got, err := sortModelPipelineSteps(spec, getStepDependencies(spec))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sortModelPipelineSteps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortModelPipelineSteps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getDescendantSteps(t *testing.T) {
+	deps := map[string][]string{
+		"a": nil,
+		"b": {"a"},
+		"c": {"b"},
+		"d": {"a"},
+	}
+	got := getDescendantSteps(deps, "b").List()
+	want := []string{"b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getDescendantSteps() = %v, want %v", got, want)
+	}
+}
+
+func Test_updateModelPipelinePhase(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases []modeljobsv1alpha1.ModelPipelinePhase
+		want   modeljobsv1alpha1.ModelPipelinePhase
+	}{
+		{
+			name:   "pending",
+			phases: []modeljobsv1alpha1.ModelPipelinePhase{modeljobsv1alpha1.ModelPipelinePending, modeljobsv1alpha1.ModelPipelinePending},
+			want:   modeljobsv1alpha1.ModelPipelinePending,
+		},
+		{
+			name:   "running",
+			phases: []modeljobsv1alpha1.ModelPipelinePhase{modeljobsv1alpha1.ModelPipelineSucceeded, modeljobsv1alpha1.ModelPipelinePending},
+			want:   modeljobsv1alpha1.ModelPipelineRunning,
+		},
+		{
+			name:   "failed step with running step",
+			phases: []modeljobsv1alpha1.ModelPipelinePhase{modeljobsv1alpha1.ModelPipelineFailed, modeljobsv1alpha1.ModelPipelineRunning},
+			want:   modeljobsv1alpha1.ModelPipelineRunning,
+		},
+		{
+			name:   "failed",
+			phases: []modeljobsv1alpha1.ModelPipelinePhase{modeljobsv1alpha1.ModelPipelineFailed, modeljobsv1alpha1.ModelPipelineSkipped},
+			want:   modeljobsv1alpha1.ModelPipelineFailed,
+		},
+		{
+			name:   "succeeded",
+			phases: []modeljobsv1alpha1.ModelPipelinePhase{modeljobsv1alpha1.ModelPipelineSucceeded, modeljobsv1alpha1.ModelPipelineSucceeded},
+			want:   modeljobsv1alpha1.ModelPipelineSucceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &modeljobsv1alpha1.ModelPipelineStatus{}
+			for i, phase := range tt.phases {
+				status.Steps = append(status.Steps, modeljobsv1alpha1.ModelPipelineStepStatus{Name: string(rune('a' + i)), Phase: phase})
+			}
+			updateModelPipelinePhase(status)
+			if status.Phase != tt.want {
+				t.Errorf("updateModelPipelinePhase() = %v, want %v", status.Phase, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ModelPipelineReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	desiredTag := "release/resnet:v1-savedmodel"
+	pipeline := &modeljobsv1alpha1.ModelPipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "resnet", Namespace: "default"},
+		Spec: modeljobsv1alpha1.ModelPipelineSpec{
+			Model: "release/resnet:v1",
+			Steps: []modeljobsv1alpha1.ModelPipelineStep{
+				{
+					Name: "convert",
+					Template: modeljobsv1alpha1.ModelJobSpec{
+						DesiredTag: &desiredTag,
+						ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+							Conversion: &modeljobsv1alpha1.ConversionSource{
+								MMdnn: &modeljobsv1alpha1.MMdnnSpec{
+									ConversionBaseSpec: modeljobsv1alpha1.ConversionBaseSpec{
+										From: modeljobsv1alpha1.FormatH5,
+										To:   modeljobsv1alpha1.FormatSavedModel,
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "extract",
+					Template: modeljobsv1alpha1.ModelJobSpec{
+						ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+							Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme, pipeline)
+	r := &ModelPipelineReconciler{
+		Client:        c,
+		EventRecorder: record.NewFakeRecorder(100),
+		Log:           ctrl.Log.WithName("test"),
+		Scheme:        scheme,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "resnet"}}
+
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	convert := &modeljobsv1alpha1.ModelJob{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "resnet-convert-0"}, convert); err != nil {
+		t.Fatalf("failed to get modeljob of step convert: %v", err)
+	}
+	if convert.Spec.Model != "release/resnet:v1" || convert.Labels[modeljobsv1alpha1.ConvertLabelKey] != "true" {
+		t.Errorf("unexpected modeljob of step convert: %v %v", convert.Spec.Model, convert.Labels)
+	}
+
+	convert.Status.Phase = modeljobsv1alpha1.ModelJobSucceeded
+	if err := c.Status().Update(context.TODO(), convert); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	extract := &modeljobsv1alpha1.ModelJob{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "resnet-extract-0"}, extract); err != nil {
+		t.Fatalf("failed to get modeljob of step extract: %v", err)
+	}
+	if extract.Spec.Model != desiredTag {
+		t.Errorf("model of step extract = %v, want %v", extract.Spec.Model, desiredTag)
+	}
+
+	extract.Status.Phase = modeljobsv1alpha1.ModelJobFailed
+	extract.Status.Message = "extract failed"
+	if err := c.Status().Update(context.TODO(), extract); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	current := &modeljobsv1alpha1.ModelPipeline{}
+	if err := c.Get(context.TODO(), req.NamespacedName, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Status.Phase != modeljobsv1alpha1.ModelPipelineFailed || current.Status.CompletionTime == nil {
+		t.Errorf("unexpected status of modelpipeline: %v %v", current.Status.Phase, current.Status.CompletionTime)
+	}
+
+	current.Annotations = map[string]string{modeljobsv1alpha1.ModelPipelineRerunFromStepAnnotationKey: "extract"}
+	if err := c.Update(context.TODO(), current); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	rerun := &modeljobsv1alpha1.ModelJob{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "resnet-extract-1"}, rerun); err != nil {
+		t.Fatalf("failed to get modeljob of rerun step extract: %v", err)
+	}
+	current = &modeljobsv1alpha1.ModelPipeline{}
+	if err := c.Get(context.TODO(), req.NamespacedName, current); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := current.Annotations[modeljobsv1alpha1.ModelPipelineRerunFromStepAnnotationKey]; ok {
+		t.Errorf("rerun annotation is not removed")
+	}
+	if current.Status.Phase != modeljobsv1alpha1.ModelPipelineRunning {
+		t.Errorf("phase of modelpipeline = %v, want Running", current.Status.Phase)
+	}
+}
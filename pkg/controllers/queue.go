@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// modelJobWaitingIndexKey is the index of the modeljobs which may wait in the queue, they have not started
+// their jobs or wait for the next attempt.
+const modelJobWaitingIndexKey = ".status.waiting"
+
+// modelJobQueue tracks the modeljobs whose jobs are active, so that the limits of concurrent jobs are checked
+// without listing all jobs. It is filled from the cache once, then the modeljobs are added when their jobs are
+// created and removed when their jobs finish or are deleted. The zero value is ready to use.
+type modelJobQueue struct {
+	mu     sync.Mutex
+	filled bool
+	active map[types.NamespacedName]bool
+}
+
+// getActive returns the modeljobs whose jobs are active, they are filled by list at the first time.
+func (q *modelJobQueue) getActive(list func() ([]types.NamespacedName, error)) (map[types.NamespacedName]bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.active == nil {
+		q.active = map[types.NamespacedName]bool{}
+	}
+	if !q.filled {
+		keys, err := list()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			q.active[key] = true
+		}
+		q.filled = true
+	}
+
+	active := make(map[types.NamespacedName]bool, len(q.active))
+	for key := range q.active {
+		active[key] = true
+	}
+	return active, nil
+}
+
+// admit records the modeljob as active after its job is created.
+func (q *modelJobQueue) admit(key types.NamespacedName) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.active == nil {
+		q.active = map[types.NamespacedName]bool{}
+	}
+	q.active[key] = true
+}
+
+// release forgets the modeljob after its job finished or is deleted.
+func (q *modelJobQueue) release(key types.NamespacedName) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.active, key)
+}
+
+// concurrencyLimits is the limits of concurrent jobs, zero means no limit.
+type concurrencyLimits struct {
+	cluster   int
+	namespace int
+}
+
+func getConcurrencyLimits() concurrencyLimits {
+	return concurrencyLimits{
+		cluster:   viper.GetInt(ModelJobMaxConcurrentJobsEnvKey),
+		namespace: viper.GetInt(ModelJobMaxConcurrentJobsPerNamespaceEnvKey),
+	}
+}
+
+func (l concurrencyLimits) unlimited() bool {
+	return l.cluster <= 0 && l.namespace <= 0
+}
+
+func (l concurrencyLimits) fits(clusterActive int, namespaceActive int) bool {
+	if l.cluster > 0 && clusterActive >= l.cluster {
+		return false
+	}
+	if l.namespace > 0 && namespaceActive >= l.namespace {
+		return false
+	}
+	return true
+}
+
+func getModelJobPriority(modeljob *modeljobsv1alpha1.ModelJob) int32 {
+	if modeljob.Spec.Priority == nil {
+		return 0
+	}
+	return *modeljob.Spec.Priority
+}
+
+// isJobActive returns true if the job is not completed or failed.
+func isJobActive(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// getJobModelJobKey returns the key of modeljob which controls the job.
+func getJobModelJobKey(job *batchv1.Job) (types.NamespacedName, bool) {
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.Kind != "ModelJob" || owner.APIVersion != modeljobsv1alpha1.GroupVersion.String() {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: job.Namespace, Name: owner.Name}, true
+}
+
+// isModelJobPhaseWaiting returns true if the modeljob is in the phase of waiting for a job, it is new, pending
+// or queued. The pending modeljobs whose jobs are created are filtered out by their jobs.
+func isModelJobPhaseWaiting(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	switch modeljob.Status.Phase {
+	case "", modeljobsv1alpha1.ModelJobPending, modeljobsv1alpha1.ModelJobQueued:
+		return true
+	}
+	return false
+}
+
+// indexModelJobWaiting indexes the modeljobs in the phase of waiting for a job.
+func indexModelJobWaiting(obj runtime.Object) []string {
+	modeljob, ok := obj.(*modeljobsv1alpha1.ModelJob)
+	if !ok || !isModelJobPhaseWaiting(modeljob) {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// isModelJobWaiting returns true if the modeljob waits for a job, it has no job and is not in the backoff of retry.
+// The suspended and cancelled modeljobs do not wait.
+func isModelJobWaiting(modeljob *modeljobsv1alpha1.ModelJob, hasJob map[types.NamespacedName]bool) bool {
+	if isModelJobFinished(modeljob) || modeljob.DeletionTimestamp != nil ||
+		isModelJobSuspended(modeljob) || isModelJobCancelRequested(modeljob) {
+		return false
+	}
+	if hasJob[types.NamespacedName{Namespace: modeljob.Namespace, Name: modeljob.Name}] {
+		return false
+	}
+	return getRetryDelayRemaining(modeljob) <= 0
+}
+
+// isModelJobBlocked returns true if the modeljob waits for its prerequisites, e.g. the Secret of registry credentials,
+// it does not take a position in queue until they are resolved.
+func isModelJobBlocked(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	return modeljob.Status.Phase == modeljobsv1alpha1.ModelJobPending &&
+		modeljob.Status.Reason == ModelJobReasonRegistryCredentialsNotFound
+}
+
+// sortWaitingModelJobs sorts the waiting modeljobs by priority, then the earlier created first.
+func sortWaitingModelJobs(modeljobs []*modeljobsv1alpha1.ModelJob) {
+	sort.SliceStable(modeljobs, func(i, j int) bool {
+		pi, pj := getModelJobPriority(modeljobs[i]), getModelJobPriority(modeljobs[j])
+		if pi != pj {
+			return pi > pj
+		}
+		ti, tj := modeljobs[i].CreationTimestamp, modeljobs[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		if modeljobs[i].Namespace != modeljobs[j].Namespace {
+			return modeljobs[i].Namespace < modeljobs[j].Namespace
+		}
+		return modeljobs[i].Name < modeljobs[j].Name
+	})
+}
+
+// getQueuePosition returns 0 if the modeljob can start its job now, otherwise it returns the position of modeljob
+// in queue. The waiting modeljobs ahead of it take the free slots first, so a modeljob is admitted only if
+// the slots are left after them, and the position counts the waiting modeljobs ahead of it which can not start.
+func getQueuePosition(modeljob *modeljobsv1alpha1.ModelJob, waiting []*modeljobsv1alpha1.ModelJob,
+	active map[types.NamespacedName]bool, limits concurrencyLimits) int32 {
+	clusterActive := len(active)
+	namespaceActive := map[string]int{}
+	for key := range active {
+		namespaceActive[key.Namespace]++
+	}
+
+	position := int32(1)
+	for _, mj := range waiting {
+		fits := limits.fits(clusterActive, namespaceActive[mj.Namespace])
+		if mj.Namespace == modeljob.Namespace && mj.Name == modeljob.Name {
+			if fits {
+				return 0
+			}
+			return position
+		}
+		if fits {
+			clusterActive++
+			namespaceActive[mj.Namespace]++
+		} else {
+			position++
+		}
+	}
+
+	// The modeljob is not waiting, do not block it.
+	return 0
+}
+
+// admitModelJob decides whether the modeljob can start its job under the limits of concurrent jobs,
+// it returns the position of modeljob in queue if it can not start now. The modeljob is recorded
+// in the queue after its job is created. Only the active and waiting modeljobs are read from the cache.
+func (r *ModelJobReconciler) admitModelJob(modeljob *modeljobsv1alpha1.ModelJob) (int32, error) {
+	limits := getConcurrencyLimits()
+	if limits.unlimited() {
+		return 0, nil
+	}
+
+	active, err := r.queue.getActive(r.listActiveModelJobs)
+	if err != nil {
+		return 0, err
+	}
+	modeljobs := &modeljobsv1alpha1.ModelJobList{}
+	if err := r.List(context.TODO(), modeljobs, client.MatchingFields{modelJobWaitingIndexKey: "true"}); err != nil {
+		return 0, err
+	}
+
+	self := types.NamespacedName{Namespace: modeljob.Namespace, Name: modeljob.Name}
+	// The status of modeljob itself in the cache may not be updated yet, e.g. after its prerequisites are
+	// resolved or it is resumed, so it is always counted by its current state.
+	waiting := []*modeljobsv1alpha1.ModelJob{modeljob}
+	hasJob := map[types.NamespacedName]bool{}
+	for i := range modeljobs.Items {
+		mj := &modeljobs.Items[i]
+		key := types.NamespacedName{Namespace: mj.Namespace, Name: mj.Name}
+		if key == self || active[key] || !isModelJobPhaseWaiting(mj) || isModelJobBlocked(mj) {
+			continue
+		}
+		// The pending modeljobs whose jobs are observed but not active, e.g. the failed job of the last
+		// attempt is not deleted yet, do not wait.
+		err := r.Get(context.TODO(), key, &batchv1.Job{})
+		if err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+		hasJob[key] = err == nil
+		if isModelJobWaiting(mj, hasJob) {
+			waiting = append(waiting, mj)
+		}
+	}
+	sortWaitingModelJobs(waiting)
+
+	return getQueuePosition(modeljob, waiting, active, limits), nil
+}
+
+// listActiveModelJobs lists the modeljobs whose jobs are active from the cache.
+func (r *ModelJobReconciler) listActiveModelJobs() ([]types.NamespacedName, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.List(context.TODO(), jobs); err != nil {
+		return nil, err
+	}
+	keys := []types.NamespacedName{}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if key, ok := getJobModelJobKey(job); ok && isJobActive(job) && job.DeletionTimestamp == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// modelJobQueueHandler releases the modeljob from the queue when its job finishes or is deleted, and enqueues
+// the queued modeljobs since a slot of concurrent jobs is freed, so that they do not poll for the slots.
+type modelJobQueueHandler struct {
+	r *ModelJobReconciler
+}
+
+var _ handler.EventHandler = &modelJobQueueHandler{}
+
+// Create implements handler.EventHandler.
+func (h *modelJobQueueHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {}
+
+// Update implements handler.EventHandler.
+func (h *modelJobQueueHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldJob, ok := e.ObjectOld.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	job, ok := e.ObjectNew.(*batchv1.Job)
+	if !ok || !isJobActive(oldJob) || isJobActive(job) {
+		return
+	}
+	h.release(job, q)
+}
+
+// Delete implements handler.EventHandler.
+func (h *modelJobQueueHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if job, ok := e.Object.(*batchv1.Job); ok {
+		h.release(job, q)
+	}
+}
+
+// Generic implements handler.EventHandler.
+func (h *modelJobQueueHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {}
+
+func (h *modelJobQueueHandler) release(job *batchv1.Job, q workqueue.RateLimitingInterface) {
+	key, ok := getJobModelJobKey(job)
+	if !ok {
+		return
+	}
+	h.r.queue.release(key)
+
+	modeljobs := &modeljobsv1alpha1.ModelJobList{}
+	if err := h.r.List(context.TODO(), modeljobs, client.MatchingFields{modelJobWaitingIndexKey: "true"}); err != nil {
+		h.r.Log.Error(err, "failed to list the queued modeljobs")
+		return
+	}
+	for i := range modeljobs.Items {
+		mj := &modeljobs.Items[i]
+		if mj.Status.Phase == modeljobsv1alpha1.ModelJobQueued {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mj.Namespace, Name: mj.Name}})
+		}
+	}
+}
+
+// recordQueued sets the modeljob Queued, the event is only recorded when it enters the queue.
+func (r *ModelJobReconciler) recordQueued(modeljob *modeljobsv1alpha1.ModelJob, position int32) {
+	message := fmt.Sprintf("waiting for the limits of concurrent jobs, queue position: %d", position)
+	modeljob.Status.QueuePosition = position
+	if modeljob.Status.Phase == modeljobsv1alpha1.ModelJobQueued {
+		modeljob.Status.Message = message
+		return
+	}
+	r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobQueued, corev1.EventTypeNormal, ModelJobReasonQueued, message, nil)
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func newQueuedModelJob(namespace, name string, priority int32, created time.Time) *modeljobsv1alpha1.ModelJob {
+	return &modeljobsv1alpha1.ModelJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: modeljobsv1alpha1.ModelJobSpec{Priority: &priority},
+	}
+}
+
+func Test_getQueuePosition(t *testing.T) {
+	now := time.Now()
+	low := newQueuedModelJob("a", "low", 0, now.Add(-time.Hour))
+	high := newQueuedModelJob("a", "high", 10, now)
+	other := newQueuedModelJob("b", "other", 0, now.Add(-time.Minute))
+
+	tests := []struct {
+		name     string
+		modeljob *modeljobsv1alpha1.ModelJob
+		active   map[types.NamespacedName]bool
+		limits   concurrencyLimits
+		want     int32
+	}{
+		{
+			name:     "higher priority first",
+			modeljob: high,
+			limits:   concurrencyLimits{cluster: 1},
+			want:     0,
+		},
+		{
+			name:     "lower priority waits",
+			modeljob: low,
+			limits:   concurrencyLimits{cluster: 1},
+			want:     1,
+		},
+		{
+			name:     "the earlier created waits behind",
+			modeljob: other,
+			limits:   concurrencyLimits{cluster: 1},
+			want:     2,
+		},
+		{
+			name:     "namespace limit",
+			modeljob: other,
+			limits:   concurrencyLimits{namespace: 1},
+			want:     0,
+		},
+		{
+			name:     "namespace limit reached by active job",
+			modeljob: high,
+			active:   map[types.NamespacedName]bool{{Namespace: "a", Name: "running"}: true},
+			limits:   concurrencyLimits{namespace: 1},
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waiting := []*modeljobsv1alpha1.ModelJob{low, high, other}
+			sortWaitingModelJobs(waiting)
+			if got := getQueuePosition(tt.modeljob, waiting, tt.active, tt.limits); got != tt.want {
+				t.Errorf("getQueuePosition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_admitModelJob(t *testing.T) {
+	viper.Set(ModelJobMaxConcurrentJobsEnvKey, 1)
+	defer viper.Set(ModelJobMaxConcurrentJobsEnvKey, 0)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	running := newQueuedModelJob("default", "running", 0, now.Add(-time.Hour))
+	running.UID = "running"
+	running.Status.Phase = modeljobsv1alpha1.ModelJobRunning
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running"}}
+	if err := controllerutil.SetControllerReference(running, job, scheme); err != nil {
+		t.Fatal(err)
+	}
+	first := newQueuedModelJob("default", "first", 0, now.Add(-time.Minute))
+	first.Status.Phase = modeljobsv1alpha1.ModelJobQueued
+	second := newQueuedModelJob("default", "second", 0, now)
+
+	r := &ModelJobReconciler{
+		Client:        fake.NewFakeClientWithScheme(scheme, running, job, first, second),
+		EventRecorder: record.NewFakeRecorder(10),
+		Log:           ctrl.Log.WithName("test"),
+		Scheme:        scheme,
+	}
+
+	if position, err := r.admitModelJob(second); err != nil || position != 2 {
+		t.Errorf("admitModelJob() = %v, %v, want position 2", position, err)
+	}
+
+	// The job of running modeljob is completed, the queued modeljobs are enqueued and the first one is admitted.
+	completed := job.DeepCopy()
+	completed.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: "True"}}
+	if err := r.Status().Update(context.TODO(), completed); err != nil {
+		t.Fatal(err)
+	}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h := &modelJobQueueHandler{r: r}
+	h.Update(event.UpdateEvent{MetaOld: job, ObjectOld: job, MetaNew: completed, ObjectNew: completed}, q)
+	if q.Len() != 1 {
+		t.Fatalf("queue length = %v, want 1", q.Len())
+	}
+	if item, _ := q.Get(); item != (reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "first"}}) {
+		t.Errorf("queue item = %v, want the first modeljob", item)
+	}
+	if position, err := r.admitModelJob(first); err != nil || position != 0 {
+		t.Errorf("admitModelJob() = %v, %v, want admitted", position, err)
+	}
+
+	// The job of first modeljob is not observed in cache yet, the second one still waits.
+	r.queue.admit(types.NamespacedName{Namespace: "default", Name: "first"})
+	if position, err := r.admitModelJob(second); err != nil || position != 1 {
+		t.Errorf("admitModelJob() = %v, %v, want position 1", position, err)
+	}
+}
+
+func Test_admitModelJob_blocked(t *testing.T) {
+	viper.Set(ModelJobMaxConcurrentJobsEnvKey, 1)
+	defer viper.Set(ModelJobMaxConcurrentJobsEnvKey, 0)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	blocked := newQueuedModelJob("default", "blocked", 10, now.Add(-time.Hour))
+	blocked.Status.Phase = modeljobsv1alpha1.ModelJobPending
+	blocked.Status.Reason = ModelJobReasonRegistryCredentialsNotFound
+	waiting := newQueuedModelJob("default", "waiting", 0, now)
+
+	r := &ModelJobReconciler{
+		Client:        fake.NewFakeClientWithScheme(scheme, blocked, waiting),
+		EventRecorder: record.NewFakeRecorder(10),
+		Log:           ctrl.Log.WithName("test"),
+		Scheme:        scheme,
+	}
+
+	// The modeljob blocked by its registry credentials does not hold the queue.
+	if position, err := r.admitModelJob(waiting); err != nil || position != 0 {
+		t.Errorf("admitModelJob() = %v, %v, want admitted", position, err)
+	}
+	// The blocked modeljob takes its position again once its prerequisites are resolved.
+	r.queue.admit(types.NamespacedName{Namespace: "default", Name: "waiting"})
+	if position, err := r.admitModelJob(blocked); err != nil || position != 1 {
+		t.Errorf("admitModelJob() = %v, %v, want position 1", position, err)
+	}
+}
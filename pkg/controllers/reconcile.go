@@ -3,14 +3,16 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/labels"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -33,6 +35,7 @@ func (r *ModelJobReconciler) reconcile(modeljob *modeljobsv1alpha1.ModelJob) (ct
 
 	// Get a local copy of modeljob's instance.
 	oldModelJob := modeljob.DeepCopy()
+	modeljob.Status.ObservedGeneration = modeljob.Generation
 
 	reconcileJobResult, err := r.reconcileJob(modeljob)
 
@@ -41,27 +44,92 @@ func (r *ModelJobReconciler) reconcile(modeljob *modeljobsv1alpha1.ModelJob) (ct
 		if err := r.Status().Update(context.Background(), modeljob); err != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 		}
+		observeModelJobMetrics(oldModelJob, modeljob)
+	}
+	if err := r.labelResultDigest(modeljob); err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 	}
 
 	if err != nil || reconcileJobResult.Requeue {
 		return reconcileJobResult, nil
 	}
 
+	// Clean up the finished modeljobs after their ttl expired.
+	if isModelJobFinished(modeljob) {
+		return r.reconcileTTL(modeljob)
+	}
+
 	return reconcile.Result{}, nil
 }
 
 func (r *ModelJobReconciler) reconcileJob(modeljob *modeljobsv1alpha1.ModelJob) (ctrl.Result, error) {
 	var err error
 
+	// The job of cancelled or suspended modeljob is deleted.
+	if stopped, result := r.reconcileStop(modeljob); stopped {
+		return result, nil
+	}
+
 	job := &batchv1.Job{}
 	err = r.Get(context.TODO(), types.NamespacedName{Namespace: modeljob.Namespace, Name: modeljob.Name}, job)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			job, err := generateJobResource(modeljob)
+			// The job of finished modeljob may be deleted by others, do not run it again.
+			if isModelJobFinished(modeljob) {
+				return ctrl.Result{}, nil
+			}
+
+			// Wait for the backoff of retry policy after the last attempt failed.
+			if delay := getRetryDelayRemaining(modeljob); delay > 0 {
+				return ctrl.Result{Requeue: true, RequeueAfter: delay}, nil
+			}
+
+			// The allowlist of operator may be changed after the modeljob is admitted by the webhook.
+			if errs := validateExecutor(&modeljob.Spec, field.NewPath("spec")); len(errs) != 0 {
+				r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonExecutorImageNotAllowed,
+					errs.ToAggregate().Error(), nil)
+				return ctrl.Result{}, nil
+			}
+
+			converters := &modeljobsv1alpha1.ModelConverterList{}
+			if err := r.List(context.TODO(), converters); err != nil {
+				r.recordStatus(modeljob, "", corev1.EventTypeWarning, ModelJobReasonPending, "failed to list modelconverters", err)
+				return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+			}
+
+			credentialsSecret, err := r.reconcileRegistryCredentials(modeljob)
+			if err != nil {
+				// The Secret may be created after the modeljob, wait for it.
+				if errors.IsNotFound(err) {
+					r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobPending, corev1.EventTypeWarning, ModelJobReasonRegistryCredentialsNotFound, "registry credentials secret is not found", err)
+					return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+				}
+				r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonFailed, "failed to resolve registry credentials", err)
+				return ctrl.Result{}, nil
+			}
+
+			// Wait in the queue of operator if the limits of concurrent jobs are reached, the prerequisites are
+			// resolved first so that the modeljobs blocked by them do not hold the queue.
+			position, err := r.admitModelJob(modeljob)
+			if err != nil {
+				r.recordStatus(modeljob, "", corev1.EventTypeWarning, ModelJobReasonPending, "failed to admit modeljob", err)
+				return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+			}
+			if position != 0 {
+				// The queued modeljobs are enqueued when the jobs of the others finish.
+				r.recordQueued(modeljob, position)
+				return ctrl.Result{}, nil
+			}
+
+			job, err := generateJobResource(modeljob, converters.Items, credentialsSecret)
 			if err != nil {
 				r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonFailed, "failed to generate job", err)
 				return ctrl.Result{}, nil
 			}
+			if names := getDeprecatedResourceEnv(modeljob); len(names) != 0 {
+				r.Eventf(modeljob, corev1.EventTypeWarning, ModelJobReasonDeprecatedEnv,
+					"env %v is deprecated, use spec.resources and spec.initializerResources instead", strings.Join(names, ","))
+			}
 
 			if err := controllerutil.SetControllerReference(modeljob, job, r.Scheme); err != nil {
 				r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonFailed, "failed to set job ownreference failed", err)
@@ -76,7 +144,20 @@ func (r *ModelJobReconciler) reconcileJob(modeljob *modeljobsv1alpha1.ModelJob)
 				return ctrl.Result{}, nil
 			}
 
+			r.queue.admit(types.NamespacedName{Namespace: modeljob.Namespace, Name: modeljob.Name})
+
 			modeljob.Status.Phase = modeljobsv1alpha1.ModelJobPending
+			modeljob.Status.QueuePosition = 0
+			// The job of next attempt is created after the current attempt failed, the job recreated
+			// for the resumed modeljob belongs to the current attempt.
+			modeljob.Status.Attempts = getCurrentAttempt(modeljob)
+			if n := len(modeljob.Status.FailedAttempts); n != 0 && modeljob.Status.FailedAttempts[n-1].Attempt == modeljob.Status.Attempts {
+				modeljob.Status.Attempts++
+			}
+			if modeljob.Status.StartTime == nil {
+				now := metav1.Now()
+				modeljob.Status.StartTime = &now
+			}
 		}
 	}
 
@@ -89,24 +170,58 @@ func (r *ModelJobReconciler) updateModelJobStatus(job *batchv1.Job, modeljob *mo
 		return ctrl.Result{}, nil
 	}
 
+	pods, err := r.getModelJobPods(job, modeljob)
+	if err != nil {
+		r.recordStatus(modeljob, "", corev1.EventTypeWarning, ModelJobReasonPending, "failed to list modeljob pods", err)
+		return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+	}
+	// The stage is observed before the pods are killed by the active deadline of job.
+	stage := getModelJobStage(&modeljob.Status)
+	setModelJobConditionsByPods(&modeljob.Status, pods)
+
+	if job.Status.StartTime != nil && modeljob.Status.StartTime == nil {
+		modeljob.Status.StartTime = job.Status.StartTime.DeepCopy()
+	}
+
 	if job.Status.Active != 0 {
-		message, err := r.getModelJobMesage(modeljob)
+		message, err := getModelJobMesageByPods(pods)
 		if err != nil || message != "" {
 			r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobPending, corev1.EventTypeWarning, ModelJobReasonPending, message, err)
-			return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+			return ctrl.Result{}, nil
 		}
 
+		// The changes of pods and job are watched, so the active job does not need to be requeued.
 		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobRunning, corev1.EventTypeNormal, ModelJobReasonStartRunning, "modelJob running", nil)
-		return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+		return ctrl.Result{}, nil
 	}
 
 	if job.Status.Succeeded != 0 {
-		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobSucceeded, corev1.EventTypeNormal, ModelJobReasonSucceded, "modelJob run successfully", nil)
+		if job.Status.CompletionTime != nil && modeljob.Status.CompletionTime == nil {
+			modeljob.Status.CompletionTime = job.Status.CompletionTime.DeepCopy()
+		}
+		message := r.setModelJobResult(modeljob, pods, "modelJob run successfully")
+		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobSucceeded, corev1.EventTypeNormal, ModelJobReasonSucceded, message, nil)
+		return ctrl.Result{}, nil
+	}
+
+	// The job exceeds its active deadline, the timed out modeljob is not retried.
+	if isJobDeadlineExceeded(job) {
+		message := r.setModelJobResult(modeljob, pods, getTimeoutMessage(job, stage))
+		exitCode, _ := getModelJobExitCode(pods)
+		recordFailedAttempt(modeljob, exitCode, ModelJobReasonTimeout, message)
+		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonTimeout, message, nil)
 		return ctrl.Result{}, nil
 	}
 
 	if job.Status.Failed != 0 {
-		message, err := r.getModelJobMesage(modeljob)
+		message, err := getModelJobMesageByPods(pods)
+		message = r.setModelJobResult(modeljob, pods, message)
+		if exitCode, ok := getModelJobExitCode(pods); ok {
+			recordFailedAttempt(modeljob, exitCode, getFailedAttemptReason(exitCode, message), message)
+			if canRetry(modeljob, exitCode) {
+				return r.retryModelJob(job, modeljob, message)
+			}
+		}
 		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobFailed, corev1.EventTypeWarning, ModelJobReasonFailed, message, err)
 		return ctrl.Result{}, nil
 	}
@@ -114,14 +229,48 @@ func (r *ModelJobReconciler) updateModelJobStatus(job *batchv1.Job, modeljob *mo
 	return ctrl.Result{}, nil
 }
 
+// retryModelJob deletes the failed job, the job of next attempt will be created after the backoff.
+func (r *ModelJobReconciler) retryModelJob(job *batchv1.Job, modeljob *modeljobsv1alpha1.ModelJob, message string) (ctrl.Result, error) {
+	err := r.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		r.recordStatus(modeljob, "", corev1.EventTypeWarning, ModelJobReasonRetrying, "failed to delete failed job", err)
+		return ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}, nil
+	}
+
+	attempt := getCurrentAttempt(modeljob)
+	delay := getRetryDelay(modeljob.Spec.RetryPolicy, attempt)
+	// Conditions are observed from the job of each attempt, reset them for next attempt.
+	modeljob.Status.Conditions = nil
+	modeljob.Status.Result = nil
+	r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobPending, corev1.EventTypeWarning, ModelJobReasonRetrying,
+		fmt.Sprintf("attempt %d failed: %s, retry after %v", attempt, message, delay), nil)
+
+	return ctrl.Result{Requeue: true, RequeueAfter: delay}, nil
+}
+
 func (r *ModelJobReconciler) recordStatus(modeljob *modeljobsv1alpha1.ModelJob, phase modeljobsv1alpha1.ModelJobPhase,
 	eventType, reason, message string, err error) {
 	if phase != "" {
 		modeljob.Status.Phase = phase
-
+		modeljob.Status.Reason = reason
 	}
 	modeljob.Status.Message = message
 
+	now := metav1.Now()
+	switch modeljob.Status.Phase {
+	case modeljobsv1alpha1.ModelJobPending, modeljobsv1alpha1.ModelJobRunning:
+		if modeljob.Status.StartTime == nil {
+			modeljob.Status.StartTime = &now
+		}
+	case modeljobsv1alpha1.ModelJobSucceeded, modeljobsv1alpha1.ModelJobFailed, modeljobsv1alpha1.ModelJobCancelled:
+		if modeljob.Status.StartTime == nil {
+			modeljob.Status.StartTime = &now
+		}
+		if modeljob.Status.CompletionTime == nil {
+			modeljob.Status.CompletionTime = &now
+		}
+	}
+
 	if err != nil {
 		message := fmt.Sprintf("%v, err: %v", message, err)
 		r.Log.Error(err, message, "modelJobName", modeljob.Name)
@@ -132,18 +281,29 @@ func (r *ModelJobReconciler) recordStatus(modeljob *modeljobsv1alpha1.ModelJob,
 	r.Event(modeljob, eventType, reason, message)
 }
 
-func (r *ModelJobReconciler) getModelJobMesage(modeljob *modeljobsv1alpha1.ModelJob) (string, error) {
-	pods := corev1.PodList{}
-	opt := client.ListOptions{
-		LabelSelector: labels.SelectorFromSet(labels.Set(map[string]string{"job-name": modeljob.Name})),
-		Namespace:     modeljob.Namespace,
+// getModelJobPods lists the pods which are created by the job of modeljob from the index of cache.
+// The jobs of all attempts have the same name, so the pods of the deleted jobs are filtered out.
+func (r *ModelJobReconciler) getModelJobPods(job *batchv1.Job, modeljob *modeljobsv1alpha1.ModelJob) (*corev1.PodList, error) {
+	pods := &corev1.PodList{}
+	// The job is not created yet.
+	if job.UID == "" {
+		return pods, nil
 	}
-	err := r.List(context.TODO(), &pods, &opt)
+
+	err := r.List(context.TODO(), pods, client.InNamespace(modeljob.Namespace), client.MatchingFields{podJobOwnerIndexKey: job.Name})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return getModelJobMesageByPods(&pods)
+	owned := pods.Items[:0]
+	for _, pod := range pods.Items {
+		if metav1.IsControlledBy(&pod, job) {
+			owned = append(owned, pod)
+		}
+	}
+	pods.Items = owned
+
+	return pods, nil
 }
 
 func getModelJobMesageByPods(pods *corev1.PodList) (string, error) {
@@ -151,6 +311,11 @@ func getModelJobMesageByPods(pods *corev1.PodList) (string, error) {
 		return errContainerCreating, nil
 	}
 
+	// The pod evicted for the ephemeral storage is failed, its containers may be killed with any exit code.
+	if message := getEphemeralStorageEvictionMessage(&pods.Items[0]); message != "" {
+		return message, nil
+	}
+
 	// For pod condition.
 	for _, c := range pods.Items[0].Status.Conditions {
 		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
@@ -216,6 +381,8 @@ func getModelJobMesageByPods(pods *corev1.PodList) (string, error) {
 				return errORMBSave, nil
 			case ErrORMBPushModel:
 				return errORMBPush, nil
+			case ErrValidationFailed:
+				return errValidationFailed, nil
 			default:
 				return fmt.Sprintf("unknow error, err code: %v", cs.State.Terminated.ExitCode), nil
 			}
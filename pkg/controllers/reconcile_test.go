@@ -1,9 +1,22 @@
 package controllers
 
 import (
+	"reflect"
 	"testing"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
 )
 
 func Test_getModelJobMesageByPods(t *testing.T) {
@@ -225,3 +238,68 @@ func Test_getModelJobMesageByPods(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapPodToModelJob(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet", UID: "job"}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "default",
+		Name:            "resnet-abcde",
+		Labels:          map[string]string{modeljobsv1alpha1.ModelJobNameLabelKey: "resnet"},
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))},
+	}}
+
+	if got := indexPodByJobOwner(pod); !reflect.DeepEqual(got, []string{"resnet"}) {
+		t.Errorf("indexPodByJobOwner() = %v, want [resnet]", got)
+	}
+	got := mapPodToModelJob(handler.MapObject{Meta: pod, Object: pod})
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "resnet"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mapPodToModelJob() = %v, want %v", got, want)
+	}
+
+	pod.OwnerReferences = nil
+	if got := mapPodToModelJob(handler.MapObject{Meta: pod, Object: pod}); len(got) != 0 {
+		t.Errorf("mapPodToModelJob() = %v, want no request for the pod not controlled by job", got)
+	}
+}
+
+func Test_updateModelJobStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	modeljob := &modeljobsv1alpha1.ModelJob{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet"}}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet", UID: "job"},
+		Status:     batchv1.JobStatus{Active: 1},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "resnet-abcde",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "model-initializer", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: executorContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	r := &ModelJobReconciler{
+		Client:        fake.NewFakeClientWithScheme(scheme, modeljob, job, pod),
+		EventRecorder: record.NewFakeRecorder(10),
+		Log:           ctrl.Log.WithName("test"),
+		Scheme:        scheme,
+	}
+
+	result, err := r.updateModelJobStatus(job, modeljob)
+	if err != nil || result.Requeue || result.RequeueAfter != 0 {
+		t.Errorf("updateModelJobStatus() = %v, %v, want no requeue for the active job", result, err)
+	}
+	if modeljob.Status.Phase != modeljobsv1alpha1.ModelJobRunning {
+		t.Errorf("updateModelJobStatus() phase = %v, want %v", modeljob.Status.Phase, modeljobsv1alpha1.ModelJobRunning)
+	}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// ModelJobRenderPath is the path on the metrics server of operator which renders the job of modeljob.
+const ModelJobRenderPath = "/render"
+
+// redactedValue replaces the values of the sensitive env in the rendered job.
+const redactedValue = "<redacted>"
+
+// sensitiveEnvNameParts is the parts of the names of env whose values are redacted, the names are compared
+// in upper case.
+var sensitiveEnvNameParts = []string{
+	"PASSWORD",
+	"PASSWD",
+	"SECRET",
+	"TOKEN",
+	"CREDENTIAL",
+	"ACCESS_KEY",
+	"API_KEY",
+	"PRIVATE_KEY",
+}
+
+// RenderJob renders the job which the operator creates for the modeljob, without creating the job or the Secret
+// of registry credentials. The modeljob is defaulted and validated as the webhook does, the Secrets in its
+// namespace are read by getSecret. The values of the sensitive env are redacted, the credentials referenced from
+// Secrets are kept as references. The validation errors are returned as an Invalid error, the errors of resolving
+// the images, resources and credentials are returned as they are.
+func RenderJob(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	getSecret func(name string) (*corev1.Secret, error)) (*batchv1.Job, error) {
+	modeljob = modeljob.DeepCopy()
+	defaultModelJob(modeljob)
+	if errs := validateModelJob(modeljob, converters); len(errs) != 0 {
+		return nil, errors.NewInvalid(modeljobsv1alpha1.GroupVersion.WithKind("ModelJob").GroupKind(), modeljob.Name, errs)
+	}
+
+	credentialsSecret, _, _, err := resolveRegistryCredentials(modeljob, getSecret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to resolve registry credentials: %v", err)
+	}
+	if credentialsSecret == "" {
+		// The Secret owned by modeljob is created by the operator when the job is created.
+		credentialsSecret = modeljob.Name + registryCredentialsSecretSuffix
+	}
+
+	job, err := generateJobResource(modeljob, converters, credentialsSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job: %v", err)
+	}
+	redactJob(job)
+	return job, nil
+}
+
+// NewRenderHandler returns the handler which renders the job of the modeljob in the body of POST request by
+// RenderJob, the modelconverters and the Secrets in the namespace of modeljob are read by c. model-registry renders
+// the jobs by it, so that they are rendered with the configuration of operator. The errors are written as Status.
+func NewRenderHandler(c client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			writeRenderError(w, errors.NewMethodNotSupported(modeljobsv1alpha1.GroupVersion.WithResource("modeljobs").GroupResource(), req.Method))
+			return
+		}
+		modeljob := &modeljobsv1alpha1.ModelJob{}
+		if err := json.NewDecoder(req.Body).Decode(modeljob); err != nil {
+			writeRenderError(w, errors.NewBadRequest(fmt.Sprintf("failed to decode modeljob: %v", err)))
+			return
+		}
+
+		converters := &modeljobsv1alpha1.ModelConverterList{}
+		if err := c.List(context.TODO(), converters); err != nil {
+			writeRenderError(w, err)
+			return
+		}
+		job, err := RenderJob(modeljob, converters.Items, func(name string) (*corev1.Secret, error) {
+			secret := &corev1.Secret{}
+			err := c.Get(context.TODO(), types.NamespacedName{Namespace: modeljob.Namespace, Name: name}, secret)
+			return secret, err
+		})
+		if err != nil {
+			writeRenderError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(job)
+	})
+}
+
+// writeRenderError writes the error as Status, the errors which are not API errors are internal errors.
+func writeRenderError(w http.ResponseWriter, err error) {
+	var status metav1.Status
+	if apiStatus, ok := err.(errors.APIStatus); ok {
+		status = apiStatus.Status()
+	} else {
+		status = errors.NewInternalError(err).ErrStatus
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// redactJob redacts the values of the sensitive env of the containers of job.
+func redactJob(job *batchv1.Job) {
+	podSpec := &job.Spec.Template.Spec
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			for j := range containers[i].Env {
+				if env := &containers[i].Env[j]; env.Value != "" && isSensitiveEnvName(env.Name) {
+					env.Value = redactedValue
+				}
+			}
+		}
+	}
+}
+
+// isSensitiveEnvName returns true if the env may hold the credentials.
+func isSensitiveEnvName(name string) bool {
+	name = strings.ToUpper(name)
+	for _, part := range sensitiveEnvNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
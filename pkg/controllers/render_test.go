@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/common"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_RenderJob(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+	viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, "true")
+	defer viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, "")
+
+	secrets := map[string]*corev1.Secret{
+		"harbor": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "harbor"},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("robot"),
+				corev1.BasicAuthPasswordKey: []byte("ORMBtest12345"),
+			},
+		},
+	}
+	getSecret := func(name string) (*corev1.Secret, error) {
+		if secret, ok := secrets[name]; ok {
+			return secret, nil
+		}
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	newModelJob := func(format modeljobsv1alpha1.Format) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet"},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/resnet:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: format},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "HF_TOKEN", Value: "hf_0123456789"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modeljob    func() *modeljobsv1alpha1.ModelJob
+		wantSecret  string
+		wantInvalid bool
+		wantErr     bool
+	}{
+		{
+			name:       "fallback credentials",
+			modeljob:   func() *modeljobsv1alpha1.ModelJob { return newModelJob(modeljobsv1alpha1.FormatSavedModel) },
+			wantSecret: "resnet" + registryCredentialsSecretSuffix,
+		},
+		{
+			name: "basic-auth secret",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.FormatSavedModel)
+				modeljob.Spec.RegistryCredentials = &modeljobsv1alpha1.RegistryCredentials{SecretName: "harbor"}
+				return modeljob
+			},
+			wantSecret: "harbor",
+		},
+		{
+			name: "secret not found",
+			modeljob: func() *modeljobsv1alpha1.ModelJob {
+				modeljob := newModelJob(modeljobsv1alpha1.FormatSavedModel)
+				modeljob.Spec.RegistryCredentials = &modeljobsv1alpha1.RegistryCredentials{SecretName: "missing"}
+				return modeljob
+			},
+			wantErr: true,
+		},
+		{
+			name:        "image not configured",
+			modeljob:    func() *modeljobsv1alpha1.ModelJob { return newModelJob(modeljobsv1alpha1.FormatONNX) },
+			wantInvalid: true,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modeljob := tt.modeljob()
+			job, err := RenderJob(modeljob, nil, getSecret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.IsInvalid(err) != tt.wantInvalid {
+				t.Errorf("RenderJob() error = %v, wantInvalid %v", err, tt.wantInvalid)
+			}
+			if err != nil {
+				return
+			}
+
+			if modeljob.Labels != nil {
+				t.Errorf("RenderJob() modified the labels of modeljob: %v", modeljob.Labels)
+			}
+			env := map[string]corev1.EnvVar{}
+			for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+				env[e.Name] = e
+			}
+			if got := env["HF_TOKEN"].Value; got != redactedValue {
+				t.Errorf("RenderJob() HF_TOKEN = %v, want %v", got, redactedValue)
+			}
+			if got := env["LOG_LEVEL"].Value; got != "debug" {
+				t.Errorf("RenderJob() LOG_LEVEL = %v, want debug", got)
+			}
+			password := env[common.ORMBPasswordEnvKey]
+			if password.ValueFrom == nil || password.ValueFrom.SecretKeyRef.Name != tt.wantSecret {
+				t.Errorf("RenderJob() %v = %v, want reference of secret %v", common.ORMBPasswordEnvKey, password, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func Test_NewRenderHandler(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+	harbor := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "harbor"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("robot"),
+			corev1.BasicAuthPasswordKey: []byte("ORMBtest12345"),
+		},
+	}
+	server := httptest.NewServer(NewRenderHandler(fake.NewFakeClientWithScheme(scheme, harbor)))
+	defer server.Close()
+
+	newModelJob := func(format modeljobsv1alpha1.Format, secretName string) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet"},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/resnet:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: format},
+				},
+				RegistryCredentials: &modeljobsv1alpha1.RegistryCredentials{SecretName: secretName},
+				Env:                 []corev1.EnvVar{{Name: "HF_TOKEN", Value: "hf_0123456789"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modeljob *modeljobsv1alpha1.ModelJob
+		wantCode int
+	}{
+		{
+			name:     "rendered",
+			modeljob: newModelJob(modeljobsv1alpha1.FormatSavedModel, "harbor"),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "secret not found",
+			modeljob: newModelJob(modeljobsv1alpha1.FormatSavedModel, "missing"),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "image not configured",
+			modeljob: newModelJob(modeljobsv1alpha1.FormatONNX, "harbor"),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.modeljob)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.Post(server.URL+ModelJobRenderPath, "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != tt.wantCode {
+				t.Fatalf("NewRenderHandler() status = %v, want %v", response.StatusCode, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				status := &metav1.Status{}
+				if err := json.NewDecoder(response.Body).Decode(status); err != nil {
+					t.Fatal(err)
+				}
+				if status.Code != int32(tt.wantCode) || status.Message == "" {
+					t.Errorf("NewRenderHandler() status = %v, want the Status of code %v", status, tt.wantCode)
+				}
+				return
+			}
+
+			got := &batchv1.Job{}
+			if err := json.NewDecoder(response.Body).Decode(got); err != nil {
+				t.Fatal(err)
+			}
+			// The job rendered for model-registry is the job which the operator creates, with the sensitive
+			// env redacted.
+			modeljob := tt.modeljob.DeepCopy()
+			defaultModelJob(modeljob)
+			want, err := generateJobResource(modeljob, nil, "harbor")
+			if err != nil {
+				t.Fatal(err)
+			}
+			redactJob(want)
+			if !equality.Semantic.DeepEqual(got, want) {
+				t.Errorf("NewRenderHandler() job = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// deprecatedResourceEnvKeys is the env of modeljob which sets the resources of containers,
+// they are replaced by spec.resources and spec.initializerResources.
+var deprecatedResourceEnvKeys = []string{
+	ModelJobTaskCPUEnvKey, ModelJobTaskMEMEnvKey, ModelInitializerCPUEnvKey, ModelInitializerMEMEnvKey,
+}
+
+var supportedTolerationOperators = sets.NewString(string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists))
+
+var supportedTaintEffects = sets.NewString(string(corev1.TaintEffectNoSchedule),
+	string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute))
+
+// getDeprecatedResourceEnv returns the names of deprecated resource env set in modeljob.
+func getDeprecatedResourceEnv(modeljob *modeljobsv1alpha1.ModelJob) []string {
+	names := []string{}
+	for _, key := range deprecatedResourceEnvKeys {
+		if hasEnv(modeljob.Spec.Env, key) {
+			names = append(names, key)
+		}
+	}
+	return names
+}
+
+// getEnvResources returns the resources set by the deprecated cpu and mem env of modeljob, it returns nil
+// if neither of them is set.
+func getEnvResources(modeljob *modeljobsv1alpha1.ModelJob, cpuEnvKey, memEnvKey string) (*corev1.ResourceRequirements, error) {
+	cpu, mem := "", ""
+	for _, val := range modeljob.Spec.Env {
+		if val.Name == cpuEnvKey {
+			cpu = val.Value
+		}
+		if val.Name == memEnvKey {
+			mem = val.Value
+		}
+	}
+	if cpu == "" && mem == "" {
+		return nil, nil
+	}
+
+	return generateResources(cpu, mem)
+}
+
+// getExecutorResources returns the resources of executor container, spec.resources takes precedence over
+// the deprecated env, and the resources of modelconverter are used if neither of them is set.
+func getExecutorResources(modeljob *modeljobsv1alpha1.ModelJob, executor *executor) (*corev1.ResourceRequirements, error) {
+	if modeljob.Spec.Resources != nil {
+		return modeljob.Spec.Resources.DeepCopy(), nil
+	}
+
+	resources, err := getEnvResources(modeljob, ModelJobTaskCPUEnvKey, ModelJobTaskMEMEnvKey)
+	if err != nil || resources != nil {
+		return resources, err
+	}
+	return executor.resources.DeepCopy(), nil
+}
+
+// getInitializerResources returns the resources of model initializer container, spec.initializerResources
+// takes precedence over the deprecated env.
+func getInitializerResources(modeljob *modeljobsv1alpha1.ModelJob) (*corev1.ResourceRequirements, error) {
+	if modeljob.Spec.InitializerResources != nil {
+		return modeljob.Spec.InitializerResources.DeepCopy(), nil
+	}
+
+	resources, err := getEnvResources(modeljob, ModelInitializerCPUEnvKey, ModelInitializerMEMEnvKey)
+	if err != nil || resources != nil {
+		return resources, err
+	}
+	return &corev1.ResourceRequirements{}, nil
+}
+
+// generateResources generates the same limits and requests of cpu and mem, they are ignored if either is empty.
+func generateResources(cpu, mem string) (*corev1.ResourceRequirements, error) {
+	if cpu == "" || mem == "" {
+		return &corev1.ResourceRequirements{}, nil
+	}
+
+	cpuQuantity, err := resource.ParseQuantity(cpu)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpu %q: %v", cpu, err)
+	}
+	memQuantity, err := resource.ParseQuantity(mem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mem %q: %v", mem, err)
+	}
+
+	resourceList := corev1.ResourceList{
+		corev1.ResourceCPU:    cpuQuantity,
+		corev1.ResourceMemory: memQuantity,
+	}
+	return &corev1.ResourceRequirements{
+		Limits:   resourceList,
+		Requests: resourceList,
+	}, nil
+}
+
+// validateModelJobScheduling validates the resources and scheduling fields which are copied to the pod of job.
+func validateModelJobScheduling(spec *modeljobsv1alpha1.ModelJobSpec, specPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	errs = append(errs, validateResourceRequirements(spec.Resources, specPath.Child("resources"))...)
+	errs = append(errs, validateResourceRequirements(spec.InitializerResources, specPath.Child("initializerResources"))...)
+	for i, env := range spec.Env {
+		if !sets.NewString(deprecatedResourceEnvKeys...).Has(env.Name) {
+			continue
+		}
+		if _, err := resource.ParseQuantity(env.Value); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("env").Index(i).Child("value"), env.Value, err.Error()))
+		}
+	}
+
+	errs = append(errs, metav1validation.ValidateLabels(spec.NodeSelector, specPath.Child("nodeSelector"))...)
+	for i := range spec.Tolerations {
+		errs = append(errs, validateToleration(&spec.Tolerations[i], specPath.Child("tolerations").Index(i))...)
+	}
+
+	if spec.PriorityClassName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.PriorityClassName) {
+			errs = append(errs, field.Invalid(specPath.Child("priorityClassName"), spec.PriorityClassName, msg))
+		}
+	}
+	if spec.ServiceAccountName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.ServiceAccountName) {
+			errs = append(errs, field.Invalid(specPath.Child("serviceAccountName"), spec.ServiceAccountName, msg))
+		}
+	}
+
+	return errs
+}
+
+// validateResourceRequirements validates that the quantities are not negative and the requests do not exceed the limits.
+func validateResourceRequirements(requirements *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	if requirements == nil {
+		return nil
+	}
+
+	errs := field.ErrorList{}
+	for name, quantity := range requirements.Limits {
+		if quantity.Sign() < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("limits").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+	}
+	for name, quantity := range requirements.Requests {
+		fldPath := fldPath.Child("requests").Key(string(name))
+		if quantity.Sign() < 0 {
+			errs = append(errs, field.Invalid(fldPath, quantity.String(), "must be greater than or equal to 0"))
+		}
+		if limit, ok := requirements.Limits[name]; ok && quantity.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(fldPath, quantity.String(), fmt.Sprintf("must be less than or equal to %v limit", name)))
+		}
+	}
+	return errs
+}
+
+// validateToleration validates the toleration as kubernetes does for pods.
+func validateToleration(toleration *corev1.Toleration, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if toleration.Key != "" {
+		for _, msg := range validation.IsQualifiedName(toleration.Key) {
+			errs = append(errs, field.Invalid(fldPath.Child("key"), toleration.Key, msg))
+		}
+	} else if toleration.Operator != corev1.TolerationOpExists {
+		errs = append(errs, field.Invalid(fldPath.Child("operator"), toleration.Operator,
+			"operator must be Exists when key is empty"))
+	}
+
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+		for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+			errs = append(errs, field.Invalid(fldPath.Child("value"), toleration.Value, msg))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			errs = append(errs, field.Invalid(fldPath.Child("value"), toleration.Value,
+				"value must be empty when operator is Exists"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("operator"), toleration.Operator, supportedTolerationOperators.List()))
+	}
+
+	if toleration.Effect != "" && !supportedTaintEffects.Has(string(toleration.Effect)) {
+		errs = append(errs, field.NotSupported(fldPath.Child("effect"), toleration.Effect, supportedTaintEffects.List()))
+	}
+	if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+		errs = append(errs, field.Invalid(fldPath.Child("effect"), toleration.Effect,
+			"effect must be NoExecute when tolerationSeconds is set"))
+	}
+
+	return errs
+}
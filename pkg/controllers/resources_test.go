@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_getExecutorResources(t *testing.T) {
+	converterResources := generateResourceList("2", "4Gi")
+	tests := []struct {
+		name    string
+		spec    modeljobsv1alpha1.ModelJobSpec
+		wantCPU string
+		wantErr bool
+	}{
+		{
+			name: "typed resources take precedence over env",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Resources: &corev1.ResourceRequirements{Limits: generateResourceList("500m", "1Gi")},
+				Env:       []corev1.EnvVar{{Name: ModelJobTaskCPUEnvKey, Value: "1"}, {Name: ModelJobTaskMEMEnvKey, Value: "1Gi"}},
+			},
+			wantCPU: "500m",
+		},
+		{
+			name: "deprecated env",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Env: []corev1.EnvVar{{Name: ModelJobTaskCPUEnvKey, Value: "1"}, {Name: ModelJobTaskMEMEnvKey, Value: "1Gi"}},
+			},
+			wantCPU: "1",
+		},
+		{
+			name:    "resources of modelconverter",
+			spec:    modeljobsv1alpha1.ModelJobSpec{},
+			wantCPU: "2",
+		},
+		{
+			name: "invalid env does not panic",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Env: []corev1.EnvVar{{Name: ModelJobTaskCPUEnvKey, Value: "one"}, {Name: ModelJobTaskMEMEnvKey, Value: "1Gi"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getExecutorResources(&modeljobsv1alpha1.ModelJob{Spec: tt.spec},
+				&executor{resources: corev1.ResourceRequirements{Limits: converterResources}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getExecutorResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Limits.Cpu().Equal(resource.MustParse(tt.wantCPU)) {
+				t.Errorf("getExecutorResources() = %v, want cpu %v", got, tt.wantCPU)
+			}
+		})
+	}
+}
+
+func Test_generateJobResource_scheduling(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model: "release/savedmodel:v1",
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+			},
+			Resources:    &corev1.ResourceRequirements{Limits: generateResourceList("1", "2Gi")},
+			NodeSelector: map[string]string{"accelerator": "nvidia"},
+			Tolerations: []corev1.Toleration{
+				{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+							},
+						}},
+					},
+				},
+			},
+			PriorityClassName:  "model-jobs",
+			ServiceAccountName: "model-runner",
+		},
+	}
+	job, err := generateJobResource(modeljob, nil, "")
+	if err != nil {
+		t.Fatalf("generateJobResource() error = %v", err)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if !reflect.DeepEqual(podSpec.NodeSelector, modeljob.Spec.NodeSelector) ||
+		!reflect.DeepEqual(podSpec.Tolerations, modeljob.Spec.Tolerations) ||
+		!reflect.DeepEqual(podSpec.Affinity, modeljob.Spec.Affinity) ||
+		podSpec.PriorityClassName != "model-jobs" || podSpec.ServiceAccountName != "model-runner" {
+		t.Errorf("generateJobResource() pod spec = %v, want the scheduling fields of modeljob", podSpec)
+	}
+	if !podSpec.Containers[0].Resources.Limits.Memory().Equal(resource.MustParse("2Gi")) {
+		t.Errorf("generateJobResource() resources = %v, want spec.resources", podSpec.Containers[0].Resources)
+	}
+
+	modeljob.Spec.Tolerations[0].TolerationSeconds = new(int64)
+	if _, err := generateJobResource(modeljob, nil, ""); err == nil {
+		t.Errorf("generateJobResource() error = nil, want error of invalid toleration")
+	}
+}
+
+func generateResourceList(cpu, mem string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(mem),
+	}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// executorContainerName is the name of the task container of job.
+const executorContainerName = "executor"
+
+// getModelJobResultByPods parses the json of ModelJobResult which the executor writes to its termination message,
+// it returns nil if the executor has not terminated or wrote nothing.
+func getModelJobResultByPods(pods *corev1.PodList) (*modeljobsv1alpha1.ModelJobResult, error) {
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+
+	for _, cs := range pods.Items[0].Status.ContainerStatuses {
+		if cs.Name != executorContainerName || cs.State.Terminated == nil {
+			continue
+		}
+		message := strings.TrimSpace(cs.State.Terminated.Message)
+		if message == "" {
+			return nil, nil
+		}
+
+		result := &modeljobsv1alpha1.ModelJobResult{}
+		if err := json.Unmarshal([]byte(message), result); err != nil {
+			return nil, fmt.Errorf("failed to parse the result of executor: %v", err)
+		}
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// setModelJobResult sets the result of executor to the status of modeljob, and appends the detailed error
+// of executor to the message.
+func (r *ModelJobReconciler) setModelJobResult(modeljob *modeljobsv1alpha1.ModelJob, pods *corev1.PodList, message string) string {
+	result, err := getModelJobResultByPods(pods)
+	if err != nil {
+		r.Log.Error(err, "failed to get modeljob result", "modelJobName", modeljob.Name)
+		r.Event(modeljob, corev1.EventTypeWarning, ModelJobReasonInvalidResult, err.Error())
+		return message
+	}
+	if result == nil {
+		return message
+	}
+
+	modeljob.Status.Result = result
+	if result.Error != "" {
+		return fmt.Sprintf("%v: %v", message, result.Error)
+	}
+	return message
+}
+
+// DigestLabelValue returns the label value of the digest, the algorithm is dropped since ":" is invalid in
+// label values and the hex is truncated to the max length of label values.
+func DigestLabelValue(digest string) string {
+	if i := strings.Index(digest, ":"); i >= 0 {
+		digest = digest[i+1:]
+	}
+	if len(digest) > validation.LabelValueMaxLength {
+		digest = digest[:validation.LabelValueMaxLength]
+	}
+	return digest
+}
+
+// labelResultDigest labels the succeeded extraction modeljob with the digest of its result, so that the
+// extractions of the same digest are found by the label.
+func (r *ModelJobReconciler) labelResultDigest(modeljob *modeljobsv1alpha1.ModelJob) error {
+	if modeljob.Status.Phase != modeljobsv1alpha1.ModelJobSucceeded || modeljob.Labels[modeljobsv1alpha1.ExtractLabelKey] != "true" ||
+		modeljob.Status.Result == nil || modeljob.Status.Result.Digest == "" {
+		return nil
+	}
+	value := DigestLabelValue(modeljob.Status.Result.Digest)
+	if modeljob.Labels[modeljobsv1alpha1.ModelJobResultDigestLabelKey] == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(modeljob.DeepCopy())
+	modeljob.Labels[modeljobsv1alpha1.ModelJobResultDigestLabelKey] = value
+	return r.Patch(context.TODO(), modeljob, patch)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func newExecutorPods(message string) *corev1.PodList {
+	return &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: executorContainerName,
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{Message: message},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_getModelJobResultByPods(t *testing.T) {
+	tests := []struct {
+		name    string
+		pods    *corev1.PodList
+		want    *modeljobsv1alpha1.ModelJobResult
+		wantErr bool
+	}{
+		{
+			name: "no pods",
+			pods: &corev1.PodList{},
+		},
+		{
+			name: "empty termination message",
+			pods: newExecutorPods(""),
+		},
+		{
+			name: "succeeded",
+			pods: newExecutorPods(`{"signature":{"inputs":[{"name":"x","size":[-1,3],"dType":"float32"}],"layers":{"Conv":3}},` +
+				`"destinationRef":"harbor.io/release/savedmodel:v1","digest":"sha256:abc","size":1024}`),
+			want: &modeljobsv1alpha1.ModelJobResult{
+				Signature: &modeljobsv1alpha1.ModelSignature{
+					Inputs: []modeljobsv1alpha1.ModelTensor{{Name: "x", Size: []int64{-1, 3}, DType: "float32"}},
+					Layers: map[string]int64{"Conv": 3},
+				},
+				DestinationRef: "harbor.io/release/savedmodel:v1",
+				Digest:         "sha256:abc",
+				Size:           1024,
+			},
+		},
+		{
+			name: "failed",
+			pods: newExecutorPods(`{"error":"unknown layer"}`),
+			want: &modeljobsv1alpha1.ModelJobResult{Error: "unknown layer"},
+		},
+		{
+			name:    "invalid json",
+			pods:    newExecutorPods("exit code: 10003"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getModelJobResultByPods(tt.pods)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getModelJobResultByPods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getModelJobResultByPods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_labelResultDigest(t *testing.T) {
+	const digest = "sha256:0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0"
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	newModelJob := func(name string, extract bool, phase modeljobsv1alpha1.ModelJobPhase) *modeljobsv1alpha1.ModelJob {
+		modeljob := &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{}},
+			Status: modeljobsv1alpha1.ModelJobStatus{
+				Phase:  phase,
+				Result: &modeljobsv1alpha1.ModelJobResult{Digest: digest},
+			},
+		}
+		if extract {
+			modeljob.Labels[modeljobsv1alpha1.ExtractLabelKey] = "true"
+		}
+		return modeljob
+	}
+
+	tests := []struct {
+		name      string
+		modeljob  *modeljobsv1alpha1.ModelJob
+		wantLabel string
+	}{
+		{
+			name:      "succeeded extraction",
+			modeljob:  newModelJob("succeeded", true, modeljobsv1alpha1.ModelJobSucceeded),
+			wantLabel: DigestLabelValue(digest),
+		},
+		{
+			name:     "running extraction",
+			modeljob: newModelJob("running", true, modeljobsv1alpha1.ModelJobRunning),
+		},
+		{
+			name:     "succeeded conversion",
+			modeljob: newModelJob("conversion", false, modeljobsv1alpha1.ModelJobSucceeded),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme, tt.modeljob.DeepCopy())
+			r := &ModelJobReconciler{Client: c, Log: ctrl.Log.WithName("test"), Scheme: scheme}
+			if err := r.labelResultDigest(tt.modeljob); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &modeljobsv1alpha1.ModelJob{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: tt.modeljob.Name}, got); err != nil {
+				t.Fatal(err)
+			}
+			if label := got.Labels[modeljobsv1alpha1.ModelJobResultDigestLabelKey]; label != tt.wantLabel {
+				t.Errorf("labelResultDigest() label = %v, want %v", label, tt.wantLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+const (
+	defaultRetryInitialDelaySeconds = 10
+	defaultRetryFactor              = 2
+	defaultRetryMaxDelaySeconds     = 300
+)
+
+// defaultRetryableExitCodes is the exit codes which are caused by transient errors of model registry.
+var defaultRetryableExitCodes = []int32{ErrORMBLogin, ErrORMBPullModel, ErrORMBPushModel}
+
+// isModelJobFinished returns true if the modeljob is succeeded, failed or cancelled.
+func isModelJobFinished(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	return modeljob.Status.Phase == modeljobsv1alpha1.ModelJobSucceeded ||
+		modeljob.Status.Phase == modeljobsv1alpha1.ModelJobFailed ||
+		modeljob.Status.Phase == modeljobsv1alpha1.ModelJobCancelled
+}
+
+// getCurrentAttempt returns the sequence number of current attempt, modeljobs
+// created before retry policy supported has no attempts, treat them as the first attempt.
+func getCurrentAttempt(modeljob *modeljobsv1alpha1.ModelJob) int32 {
+	if modeljob.Status.Attempts < 1 {
+		return 1
+	}
+	return modeljob.Status.Attempts
+}
+
+// getModelJobExitCode returns the exit code of the failed container in pod,
+// the failure of init container is treated as ormb pull error.
+func getModelJobExitCode(pods *corev1.PodList) (int32, bool) {
+	if len(pods.Items) == 0 {
+		return 0, false
+	}
+
+	for _, cs := range pods.Items[0].Status.InitContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+			return ErrORMBPullModel, true
+		}
+	}
+
+	for _, cs := range pods.Items[0].Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+			return cs.State.Terminated.ExitCode, true
+		}
+	}
+
+	return 0, false
+}
+
+// recordFailedAttempt records the failure of current attempt, it is idempotent for the same attempt.
+func recordFailedAttempt(modeljob *modeljobsv1alpha1.ModelJob, exitCode int32, reason, message string) {
+	attempt := getCurrentAttempt(modeljob)
+	failedAttempts := modeljob.Status.FailedAttempts
+	if len(failedAttempts) != 0 && failedAttempts[len(failedAttempts)-1].Attempt == attempt {
+		return
+	}
+
+	modeljob.Status.FailedAttempts = append(modeljob.Status.FailedAttempts, modeljobsv1alpha1.ModelJobAttempt{
+		Attempt:    attempt,
+		ExitCode:   exitCode,
+		Reason:     reason,
+		Message:    message,
+		FinishTime: metav1.Now(),
+	})
+}
+
+// canRetry returns true if the modeljob has remaining attempts and the exit code is retryable.
+func canRetry(modeljob *modeljobsv1alpha1.ModelJob, exitCode int32) bool {
+	policy := modeljob.Spec.RetryPolicy
+	if policy == nil || getCurrentAttempt(modeljob) >= policy.MaxAttempts {
+		return false
+	}
+
+	retryableExitCodes := policy.RetryableExitCodes
+	if len(retryableExitCodes) == 0 {
+		retryableExitCodes = defaultRetryableExitCodes
+	}
+	for _, code := range retryableExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRetryDelay returns the delay before the next attempt of the given failed attempt.
+func getRetryDelay(policy *modeljobsv1alpha1.RetryPolicy, failedAttempt int32) time.Duration {
+	initialDelay, factor, maxDelay := int32(defaultRetryInitialDelaySeconds), int32(defaultRetryFactor), int32(defaultRetryMaxDelaySeconds)
+	if policy != nil && policy.Backoff != nil {
+		if policy.Backoff.InitialDelaySeconds > 0 {
+			initialDelay = policy.Backoff.InitialDelaySeconds
+		}
+		if policy.Backoff.Factor > 0 {
+			factor = policy.Backoff.Factor
+		}
+		if policy.Backoff.MaxDelaySeconds > 0 {
+			maxDelay = policy.Backoff.MaxDelaySeconds
+		}
+	}
+
+	delay := float64(initialDelay) * math.Pow(float64(factor), float64(failedAttempt-1))
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+	return time.Duration(delay) * time.Second
+}
+
+// getRetryDelayRemaining returns how long to wait before creating the job of next attempt.
+func getRetryDelayRemaining(modeljob *modeljobsv1alpha1.ModelJob) time.Duration {
+	failedAttempts := modeljob.Status.FailedAttempts
+	if len(failedAttempts) == 0 {
+		return 0
+	}
+
+	last := failedAttempts[len(failedAttempts)-1]
+	nextAttemptTime := last.FinishTime.Add(getRetryDelay(modeljob.Spec.RetryPolicy, last.Attempt))
+	return time.Until(nextAttemptTime)
+}
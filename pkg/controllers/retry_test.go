@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_canRetry(t *testing.T) {
+	type args struct {
+		modeljob *modeljobsv1alpha1.ModelJob
+		exitCode int32
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "no retry policy",
+			args: args{
+				modeljob: &modeljobsv1alpha1.ModelJob{},
+				exitCode: ErrORMBPushModel,
+			},
+			want: false,
+		},
+		{
+			name: "default retryable exit code",
+			args: args{
+				modeljob: &modeljobsv1alpha1.ModelJob{
+					Spec: modeljobsv1alpha1.ModelJobSpec{
+						RetryPolicy: &modeljobsv1alpha1.RetryPolicy{
+							MaxAttempts: 3,
+						},
+					},
+				},
+				exitCode: ErrORMBPushModel,
+			},
+			want: true,
+		},
+		{
+			name: "run task error fails fast",
+			args: args{
+				modeljob: &modeljobsv1alpha1.ModelJob{
+					Spec: modeljobsv1alpha1.ModelJobSpec{
+						RetryPolicy: &modeljobsv1alpha1.RetryPolicy{
+							MaxAttempts: 3,
+						},
+					},
+				},
+				exitCode: ErrRunTask,
+			},
+			want: false,
+		},
+		{
+			name: "custom retryable exit code",
+			args: args{
+				modeljob: &modeljobsv1alpha1.ModelJob{
+					Spec: modeljobsv1alpha1.ModelJobSpec{
+						RetryPolicy: &modeljobsv1alpha1.RetryPolicy{
+							MaxAttempts:        3,
+							RetryableExitCodes: []int32{ErrRunTask},
+						},
+					},
+				},
+				exitCode: ErrRunTask,
+			},
+			want: true,
+		},
+		{
+			name: "no remaining attempts",
+			args: args{
+				modeljob: &modeljobsv1alpha1.ModelJob{
+					Spec: modeljobsv1alpha1.ModelJobSpec{
+						RetryPolicy: &modeljobsv1alpha1.RetryPolicy{
+							MaxAttempts: 3,
+						},
+					},
+					Status: modeljobsv1alpha1.ModelJobStatus{
+						Attempts: 3,
+					},
+				},
+				exitCode: ErrORMBLogin,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canRetry(tt.args.modeljob, tt.args.exitCode); got != tt.want {
+				t.Errorf("canRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getRetryDelay(t *testing.T) {
+	type args struct {
+		policy        *modeljobsv1alpha1.RetryPolicy
+		failedAttempt int32
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			name: "default backoff",
+			args: args{
+				policy:        &modeljobsv1alpha1.RetryPolicy{},
+				failedAttempt: 2,
+			},
+			want: 20 * time.Second,
+		},
+		{
+			name: "custom backoff",
+			args: args{
+				policy: &modeljobsv1alpha1.RetryPolicy{
+					Backoff: &modeljobsv1alpha1.RetryBackoff{
+						InitialDelaySeconds: 5,
+						Factor:              3,
+					},
+				},
+				failedAttempt: 3,
+			},
+			want: 45 * time.Second,
+		},
+		{
+			name: "max delay",
+			args: args{
+				policy: &modeljobsv1alpha1.RetryPolicy{
+					Backoff: &modeljobsv1alpha1.RetryBackoff{
+						MaxDelaySeconds: 60,
+					},
+				},
+				failedAttempt: 10,
+			},
+			want: 60 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRetryDelay(tt.args.policy, tt.args.failedAttempt); got != tt.want {
+				t.Errorf("getRetryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getModelJobExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		pods   *corev1.PodList
+		want   int32
+		wantOK bool
+	}{
+		{
+			name:   "no pods",
+			pods:   &corev1.PodList{},
+			want:   0,
+			wantOK: false,
+		},
+		{
+			name: "init container failed",
+			pods: &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						Status: corev1.PodStatus{
+							InitContainerStatuses: []corev1.ContainerStatus{
+								{
+									State: corev1.ContainerState{
+										Terminated: &corev1.ContainerStateTerminated{
+											ExitCode: 1,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want:   ErrORMBPullModel,
+			wantOK: true,
+		},
+		{
+			name: "executor failed",
+			pods: &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						Status: corev1.PodStatus{
+							ContainerStatuses: []corev1.ContainerStatus{
+								{
+									State: corev1.ContainerState{
+										Terminated: &corev1.ContainerStateTerminated{
+											ExitCode: ErrRunTask,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want:   ErrRunTask,
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := getModelJobExitCode(tt.pods)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("getModelJobExitCode() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_recordFailedAttempt(t *testing.T) {
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Status: modeljobsv1alpha1.ModelJobStatus{
+			Attempts: 1,
+		},
+	}
+
+	recordFailedAttempt(modeljob, ErrORMBPushModel, reasonORMBPushFailed, errORMBPush)
+	recordFailedAttempt(modeljob, ErrORMBPushModel, reasonORMBPushFailed, errORMBPush)
+	if len(modeljob.Status.FailedAttempts) != 1 {
+		t.Errorf("recordFailedAttempt() failed attempts = %v, want 1", modeljob.Status.FailedAttempts)
+	}
+	if modeljob.Status.FailedAttempts[0].Reason != reasonORMBPushFailed {
+		t.Errorf("recordFailedAttempt() reason = %v, want %v", modeljob.Status.FailedAttempts[0].Reason, reasonORMBPushFailed)
+	}
+
+	modeljob.Status.Attempts = 2
+	recordFailedAttempt(modeljob, ErrRunTask, reasonRunTaskFailed, errRunTask)
+	if len(modeljob.Status.FailedAttempts) != 2 {
+		t.Errorf("recordFailedAttempt() failed attempts = %v, want 2", modeljob.Status.FailedAttempts)
+	}
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+const (
+	// sourceInitializerContainerName is the name of the init container which stages the model source.
+	sourceInitializerContainerName = "model-source-initializer"
+	// modelSourceVolumeName is the name of the volume of PVC model source.
+	modelSourceVolumeName = "model-source"
+	// modelSourcePath is the path which the PVC model source is mounted to.
+	modelSourcePath = "/models/source"
+)
+
+// checksumRegexp matches the checksum of HTTP model source, the length of hex is checked by the algorithm.
+var checksumRegexp = regexp.MustCompile(`^(sha256|sha512):([0-9a-f]+)$`)
+
+// checksumHexLength is the length of hex of the checksum algorithms.
+var checksumHexLength = map[string]int{
+	"sha256": 64,
+	"sha512": 128,
+}
+
+// generateSourceInitContainers generates the init container which stages the model source to /models/input.
+func generateSourceInitContainers(modeljob *modeljobsv1alpha1.ModelJob, resources *corev1.ResourceRequirements) ([]corev1.Container, error) {
+	var image string
+	if imageEnv, ok := presetImage["source-initializer"]; ok {
+		image = viper.GetString(imageEnv)
+	}
+	if image == "" {
+		return nil, fmt.Errorf("failed get model-source-initializer image")
+	}
+
+	_, format, _ := getModelJobFormats(modeljob)
+	_, subPath := generateWorkspaceVolume(modeljob)
+	container := corev1.Container{
+		Name:       sourceInitializerContainerName,
+		Image:      image,
+		Args:       []string{"--dest", modeljobsv1alpha1.SourceModelPath, "--format", string(format)},
+		WorkingDir: ModelJobWorkDir,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      ModelJobSharedVolumeName,
+				MountPath: modeljobsv1alpha1.SourceModelPath,
+				SubPath:   subPath,
+			},
+		},
+		Resources:       *resources,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+	}
+
+	source := modeljob.Spec.Source
+	switch {
+	case source.S3 != nil:
+		container.Args = append(container.Args, "s3", "--bucket", source.S3.Bucket, "--key", source.S3.Key)
+		if source.S3.Endpoint != "" {
+			container.Args = append(container.Args, "--endpoint", source.S3.Endpoint)
+		}
+		if source.S3.Region != "" {
+			container.Args = append(container.Args, "--region", source.S3.Region)
+		}
+		container.Env = generateS3CredentialsEnv(source.S3.CredentialsSecretName)
+	case source.HTTP != nil:
+		container.Args = append(container.Args, "http", "--url", source.HTTP.URL, "--checksum", source.HTTP.Checksum)
+	case source.PersistentVolumeClaim != nil:
+		container.Args = append(container.Args, "pvc", "--path", path.Join(modelSourcePath, source.PersistentVolumeClaim.Path))
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      modelSourceVolumeName,
+			MountPath: modelSourcePath,
+			ReadOnly:  true,
+		})
+	default:
+		return nil, fmt.Errorf("%v", "not support model source")
+	}
+
+	return []corev1.Container{container}, nil
+}
+
+// generateSourceVolumes generates the volumes which the model source initializer mounts.
+func generateSourceVolumes(modeljob *modeljobsv1alpha1.ModelJob) []corev1.Volume {
+	if modeljob.Spec.Source == nil || modeljob.Spec.Source.PersistentVolumeClaim == nil {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: modelSourceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: modeljob.Spec.Source.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+}
+
+// generateS3CredentialsEnv generates the env of AWS credentials which reference the Secret of S3 model source.
+func generateS3CredentialsEnv(secretName string) []corev1.EnvVar {
+	if secretName == "" {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "AWS_ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  modeljobsv1alpha1.S3AccessKeyIDKey,
+				},
+			},
+		},
+		{
+			Name: "AWS_SECRET_ACCESS_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  modeljobsv1alpha1.S3SecretAccessKeyKey,
+				},
+			},
+		},
+	}
+}
+
+// validateModelSource validates that only one member of model source is set and it can be staged.
+func validateModelSource(spec *modeljobsv1alpha1.ModelJobSpec, specPath *field.Path) field.ErrorList {
+	source := spec.Source
+	if source == nil {
+		return nil
+	}
+	errs := field.ErrorList{}
+	fldPath := specPath.Child("source")
+
+	if spec.Extraction == nil && spec.Conversion == nil {
+		errs = append(errs, field.Forbidden(fldPath, "may only be set for extraction or conversion"))
+	}
+	if len(spec.InitContainer) != 0 {
+		errs = append(errs, field.Forbidden(fldPath, "may not be set when initContainer is set"))
+	}
+
+	set := []string{}
+	if s3 := source.S3; s3 != nil {
+		set = append(set, "s3")
+		s3Path := fldPath.Child("s3")
+		if s3.Bucket == "" {
+			errs = append(errs, field.Required(s3Path.Child("bucket"), ""))
+		}
+		if s3.Key == "" {
+			errs = append(errs, field.Required(s3Path.Child("key"), ""))
+		}
+		if s3.Endpoint != "" && !isHTTPURL(s3.Endpoint) {
+			errs = append(errs, field.Invalid(s3Path.Child("endpoint"), s3.Endpoint, "must be an HTTP(S) URL"))
+		}
+		if s3.CredentialsSecretName != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(s3.CredentialsSecretName) {
+				errs = append(errs, field.Invalid(s3Path.Child("credentialsSecretName"), s3.CredentialsSecretName, msg))
+			}
+		}
+	}
+	if http := source.HTTP; http != nil {
+		set = append(set, "http")
+		httpPath := fldPath.Child("http")
+		if !isHTTPURL(http.URL) {
+			errs = append(errs, field.Invalid(httpPath.Child("url"), http.URL, "must be an HTTP(S) URL"))
+		}
+		if match := checksumRegexp.FindStringSubmatch(http.Checksum); match == nil || len(match[2]) != checksumHexLength[match[1]] {
+			errs = append(errs, field.Invalid(httpPath.Child("checksum"), http.Checksum,
+				"must be in the form of sha256:<hex> or sha512:<hex> in lower case"))
+		}
+	}
+	if pvc := source.PersistentVolumeClaim; pvc != nil {
+		set = append(set, "persistentVolumeClaim")
+		pvcPath := fldPath.Child("persistentVolumeClaim")
+		if pvc.ClaimName == "" {
+			errs = append(errs, field.Required(pvcPath.Child("claimName"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(pvc.ClaimName) {
+				errs = append(errs, field.Invalid(pvcPath.Child("claimName"), pvc.ClaimName, msg))
+			}
+		}
+		if pvc.Path != "" && !isRelativeSubPath(pvc.Path) {
+			errs = append(errs, field.Invalid(pvcPath.Child("path"), pvc.Path, "must be a relative path without '..'"))
+		}
+	}
+
+	switch len(set) {
+	case 0:
+		errs = append(errs, field.Required(fldPath, "one of s3, http or persistentVolumeClaim must be set"))
+	case 1:
+	default:
+		errs = append(errs, field.Forbidden(fldPath.Child(set[1]), fmt.Sprintf("may not be set when %v is set", set[0])))
+	}
+	return errs
+}
+
+// isHTTPURL returns true if the value is an absolute HTTP(S) URL.
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, "http") || strings.EqualFold(u.Scheme, "https")
+}
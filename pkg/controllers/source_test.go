@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_generateJobResource_source(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+	viper.Set("MODEL_SOURCE_INITIALIZER_IMAGE", "demo.goharbor.com/release/model-source-initializer:v0.3.0")
+	defer viper.Set("MODEL_SOURCE_INITIALIZER_IMAGE", "")
+
+	tests := []struct {
+		name        string
+		source      *modeljobsv1alpha1.ModelSource
+		wantArgs    []string
+		wantEnv     int
+		wantVolumes int
+	}{
+		{
+			name: "s3",
+			source: &modeljobsv1alpha1.ModelSource{S3: &modeljobsv1alpha1.S3ModelSource{
+				Endpoint:              "http://minio:9000",
+				Bucket:                "models",
+				Key:                   "resnet/",
+				CredentialsSecretName: "minio",
+			}},
+			wantArgs:    []string{"s3", "--bucket", "models", "--key", "resnet/", "--endpoint", "http://minio:9000"},
+			wantEnv:     2,
+			wantVolumes: 1,
+		},
+		{
+			name: "http",
+			source: &modeljobsv1alpha1.ModelSource{HTTP: &modeljobsv1alpha1.HTTPModelSource{
+				URL:      "https://example.com/resnet.tar.gz",
+				Checksum: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			}},
+			wantArgs: []string{"http", "--url", "https://example.com/resnet.tar.gz",
+				"--checksum", "sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+			wantVolumes: 1,
+		},
+		{
+			name: "persistentVolumeClaim",
+			source: &modeljobsv1alpha1.ModelSource{PersistentVolumeClaim: &modeljobsv1alpha1.PersistentVolumeClaimModelSource{
+				ClaimName: "datasets",
+				Path:      "models/resnet",
+			}},
+			wantArgs:    []string{"pvc", "--path", "/models/source/models/resnet"},
+			wantVolumes: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modeljob := &modeljobsv1alpha1.ModelJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "resnet"},
+				Spec: modeljobsv1alpha1.ModelJobSpec{
+					Model:  "release/savedmodel:v1",
+					Source: tt.source,
+					ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+						Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+					},
+				},
+			}
+			job, err := generateJobResource(modeljob, nil, "test-modeljob-registry-credentials")
+			if err != nil {
+				t.Fatalf("generateJobResource() error = %v", err)
+			}
+
+			podSpec := job.Spec.Template.Spec
+			if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != sourceInitializerContainerName {
+				t.Fatalf("generateJobResource() init containers = %v, want the model source initializer", podSpec.InitContainers)
+			}
+			initializer := podSpec.InitContainers[0]
+			wantArgs := append([]string{"--dest", modeljobsv1alpha1.SourceModelPath, "--format", "SavedModel"}, tt.wantArgs...)
+			if !reflect.DeepEqual(initializer.Args, wantArgs) {
+				t.Errorf("generateJobResource() initializer args = %v, want %v", initializer.Args, wantArgs)
+			}
+			if len(initializer.Env) != tt.wantEnv {
+				t.Errorf("generateJobResource() initializer env = %v, want %v env", initializer.Env, tt.wantEnv)
+			}
+			for _, env := range initializer.Env {
+				if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+					t.Errorf("generateJobResource() env %v is not referenced from secret", env)
+				}
+			}
+			if len(podSpec.Volumes) != tt.wantVolumes {
+				t.Errorf("generateJobResource() volumes = %v, want %v volumes", podSpec.Volumes, tt.wantVolumes)
+			}
+		})
+	}
+}
+
+func Test_validateModelSource(t *testing.T) {
+	extraction := modeljobsv1alpha1.ModelJobSource{
+		Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+	}
+	tests := []struct {
+		name     string
+		spec     modeljobsv1alpha1.ModelJobSpec
+		wantErrs []string
+	}{
+		{
+			name: "s3",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Source: &modeljobsv1alpha1.ModelSource{S3: &modeljobsv1alpha1.S3ModelSource{
+					Bucket: "models",
+					Key:    "resnet.tar.gz",
+				}},
+				ModelJobSource: extraction,
+			},
+		},
+		{
+			name: "invalid http",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Source: &modeljobsv1alpha1.ModelSource{HTTP: &modeljobsv1alpha1.HTTPModelSource{
+					URL:      "ftp://example.com/resnet.tar.gz",
+					Checksum: "md5:00",
+				}},
+				ModelJobSource: extraction,
+			},
+			wantErrs: []string{"spec.source.http.url", "spec.source.http.checksum"},
+		},
+		{
+			name: "short checksum",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Source: &modeljobsv1alpha1.ModelSource{HTTP: &modeljobsv1alpha1.HTTPModelSource{
+					URL:      "https://example.com/resnet.tar.gz",
+					Checksum: "sha256:00",
+				}},
+				ModelJobSource: extraction,
+			},
+			wantErrs: []string{"spec.source.http.checksum"},
+		},
+		{
+			name: "validation with pvc out of claim",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Source: &modeljobsv1alpha1.ModelSource{PersistentVolumeClaim: &modeljobsv1alpha1.PersistentVolumeClaimModelSource{
+					ClaimName: "datasets",
+					Path:      "../resnet",
+				}},
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Validation: &modeljobsv1alpha1.ValidationSource{Format: modeljobsv1alpha1.FormatSavedModel},
+				},
+			},
+			wantErrs: []string{"spec.source", "spec.source.persistentVolumeClaim.path"},
+		},
+		{
+			name: "both s3 and initContainer",
+			spec: modeljobsv1alpha1.ModelJobSpec{
+				Source:         &modeljobsv1alpha1.ModelSource{},
+				InitContainer:  []corev1.Container{{Name: "pull"}},
+				ModelJobSource: extraction,
+			},
+			wantErrs: []string{"spec.source", "spec.source"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateModelSource(&tt.spec, field.NewPath("spec"))
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateModelSource() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateModelSource() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
@@ -22,6 +22,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,10 +32,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	// +kubebuilder:scaffold:imports
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobsv1beta1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1beta1"
 )
 
 // // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -68,14 +72,26 @@ var _ = BeforeSuite(func(done Done) {
 
 	err = modeljobsv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = modeljobsv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = apiextensionsv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
 
+	// The modeljobs of tests do not reference registry credentials.
+	viper.Set(ModelJobRegistryCredentialsFallbackEnvKey, true)
+
+	// The webhook server serves the conversion webhook of modeljob for the apiserver of envtest.
 	k8sManager, err = ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:             scheme.Scheme,
 		MetricsBindAddress: "0",
+		Host:               testEnv.WebhookInstallOptions.LocalServingHost,
+		Port:               testEnv.WebhookInstallOptions.LocalServingPort,
+		CertDir:            testEnv.WebhookInstallOptions.LocalServingCertDir,
 	})
 	Expect(err).ToNot(HaveOccurred())
+	k8sManager.GetWebhookServer().Register(ModelJobConversionWebhookPath, &conversion.Webhook{})
 
 	reconciler = &ModelJobReconciler{
 		Client:        k8sManager.GetClient(),
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// isModelJobCancelRequested returns true if the modeljob has the cancel annotation.
+func isModelJobCancelRequested(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	return modeljob.Annotations[modeljobsv1alpha1.ModelJobCancelAnnotationKey] == "true"
+}
+
+// isModelJobSuspended returns true if spec.suspend of modeljob is true.
+func isModelJobSuspended(modeljob *modeljobsv1alpha1.ModelJob) bool {
+	return modeljob.Spec.Suspend != nil && *modeljob.Spec.Suspend
+}
+
+// reconcileStop stops the job of the cancelled or suspended modeljob, it returns true if the modeljob
+// should not run now. The suspended modeljob is set Pending when it is resumed, then its job is created again.
+func (r *ModelJobReconciler) reconcileStop(modeljob *modeljobsv1alpha1.ModelJob) (bool, ctrl.Result) {
+	if isModelJobFinished(modeljob) {
+		return false, ctrl.Result{}
+	}
+
+	cancelled, suspended := isModelJobCancelRequested(modeljob), isModelJobSuspended(modeljob)
+	if !cancelled && !suspended {
+		if modeljob.Status.Phase == modeljobsv1alpha1.ModelJobSuspended {
+			// The stages and result of the stopped job are not valid for the new job.
+			modeljob.Status.Conditions = nil
+			modeljob.Status.Result = nil
+			r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobPending, corev1.EventTypeNormal, ModelJobReasonResumed,
+				"modeljob is resumed", nil)
+		}
+		return false, ctrl.Result{}
+	}
+
+	if err := r.deleteModelJobJob(modeljob); err != nil {
+		r.recordStatus(modeljob, "", corev1.EventTypeWarning, ModelJobReasonPending, "failed to delete job", err)
+		return true, ctrl.Result{Requeue: true, RequeueAfter: 15 * time.Second}
+	}
+
+	modeljob.Status.QueuePosition = 0
+	if cancelled {
+		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobCancelled, corev1.EventTypeNormal, ModelJobReasonCancelled,
+			"modeljob is cancelled", nil)
+		return true, ctrl.Result{}
+	}
+	if modeljob.Status.Phase != modeljobsv1alpha1.ModelJobSuspended {
+		r.recordStatus(modeljob, modeljobsv1alpha1.ModelJobSuspended, corev1.EventTypeNormal, ModelJobReasonSuspended,
+			"modeljob is suspended", nil)
+	}
+	return true, ctrl.Result{}
+}
+
+// deleteModelJobJob deletes the job of modeljob and its pods if it exists.
+func (r *ModelJobReconciler) deleteModelJobJob(modeljob *modeljobsv1alpha1.ModelJob) error {
+	job := &batchv1.Job{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: modeljob.Namespace, Name: modeljob.Name}, job)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(job, modeljob) || job.DeletionTimestamp != nil {
+		return nil
+	}
+
+	err = r.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_reconcileStop(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = modeljobsv1alpha1.AddToScheme(scheme)
+
+	suspend := true
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "resnet", UID: "modeljob"},
+		Spec:       modeljobsv1alpha1.ModelJobSpec{Suspend: &suspend},
+		Status:     modeljobsv1alpha1.ModelJobStatus{Phase: modeljobsv1alpha1.ModelJobRunning},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "resnet",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(modeljob, modeljobsv1alpha1.GroupVersion.WithKind("ModelJob"))},
+		},
+	}
+	r := &ModelJobReconciler{
+		Client:        fake.NewFakeClientWithScheme(scheme, modeljob.DeepCopy(), job),
+		EventRecorder: record.NewFakeRecorder(10),
+		Log:           ctrl.Log.WithName("test"),
+		Scheme:        scheme,
+	}
+
+	// The suspended modeljob stops its job.
+	stopped, _ := r.reconcileStop(modeljob)
+	if !stopped || modeljob.Status.Phase != modeljobsv1alpha1.ModelJobSuspended {
+		t.Errorf("reconcileStop() = %v, phase %v, want the modeljob suspended", stopped, modeljob.Status.Phase)
+	}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "resnet"}, &batchv1.Job{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("reconcileStop() did not delete the job, error = %v", err)
+	}
+
+	// The resumed modeljob is pending for its new job.
+	suspend = false
+	stopped, _ = r.reconcileStop(modeljob)
+	if stopped || modeljob.Status.Phase != modeljobsv1alpha1.ModelJobPending {
+		t.Errorf("reconcileStop() = %v, phase %v, want the modeljob resumed", stopped, modeljob.Status.Phase)
+	}
+
+	// The cancelled modeljob is finished, it is not stopped again.
+	modeljob.Annotations = map[string]string{modeljobsv1alpha1.ModelJobCancelAnnotationKey: "true"}
+	stopped, _ = r.reconcileStop(modeljob)
+	if !stopped || modeljob.Status.Phase != modeljobsv1alpha1.ModelJobCancelled || modeljob.Status.CompletionTime == nil {
+		t.Errorf("reconcileStop() = %v, phase %v, want the modeljob cancelled", stopped, modeljob.Status.Phase)
+	}
+	if stopped, _ = r.reconcileStop(modeljob); stopped || !isModelJobFinished(modeljob) {
+		t.Errorf("reconcileStop() = %v, want the cancelled modeljob finished", stopped)
+	}
+}
+
+func Test_isModelJobWaiting_suspended(t *testing.T) {
+	suspend := true
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec:   modeljobsv1alpha1.ModelJobSpec{Suspend: &suspend},
+		Status: modeljobsv1alpha1.ModelJobStatus{Phase: modeljobsv1alpha1.ModelJobSuspended},
+	}
+	if isModelJobWaiting(modeljob, nil) {
+		t.Errorf("isModelJobWaiting() = true, want false for the suspended modeljob")
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// defines the stages of modeljob, they are reported by the timeout errors and the duration histogram.
+const (
+	modelJobStagePull = "pull"
+	modelJobStageTask = "task"
+	modelJobStagePush = "push"
+)
+
+// jobReasonDeadlineExceeded is the reason of job failed condition when the job exceeds its active deadline.
+const jobReasonDeadlineExceeded = "DeadlineExceeded"
+
+// getActiveDeadlineSeconds returns the active deadline of modeljob, the operator-wide default of the format
+// is used if it is not set, eg: SAVEDMODEL_EXTRACT_ACTIVE_DEADLINE_SECONDS for savedmodel extraction,
+// then the global default MODELJOB_ACTIVE_DEADLINE_SECONDS.
+func getActiveDeadlineSeconds(modeljob *modeljobsv1alpha1.ModelJob, presetKey string) *int64 {
+	if modeljob.Spec.ActiveDeadlineSeconds != nil {
+		return modeljob.Spec.ActiveDeadlineSeconds
+	}
+
+	envKeys := []string{ModelJobActiveDeadlineSecondsEnvKey}
+	if presetKey != "" {
+		formatEnvKey := strings.ToUpper(strings.ReplaceAll(presetKey, "-", "_")) + ActiveDeadlineSecondsEnvKeySuffix
+		envKeys = append([]string{formatEnvKey}, envKeys...)
+	}
+	for _, envKey := range envKeys {
+		seconds, err := strconv.ParseInt(viper.GetString(envKey), 10, 64)
+		if err == nil && seconds > 0 {
+			return &seconds
+		}
+	}
+
+	return nil
+}
+
+// isJobDeadlineExceeded returns true if the job is failed because it exceeds its active deadline.
+func isJobDeadlineExceeded(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue && c.Reason == jobReasonDeadlineExceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// getModelJobStage returns the stage which modeljob is running, it is derived from the conditions of modeljob.
+func getModelJobStage(status *modeljobsv1alpha1.ModelJobStatus) string {
+	if !isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobModelPulled) {
+		return modelJobStagePull
+	}
+	if !isModelJobConditionTrue(status, modeljobsv1alpha1.ModelJobTaskCompleted) {
+		return modelJobStageTask
+	}
+	return modelJobStagePush
+}
+
+// getTimeoutMessage returns the message of modeljob which exceeds its active deadline in the stage.
+func getTimeoutMessage(job *batchv1.Job, stage string) string {
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		return fmt.Sprintf("modelJob timed out in %v stage", stage)
+	}
+	return fmt.Sprintf("modelJob timed out after %vs in %v stage", *job.Spec.ActiveDeadlineSeconds, stage)
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+func Test_getActiveDeadlineSeconds(t *testing.T) {
+	viper.AutomaticEnv()
+	defer os.Unsetenv(ModelJobActiveDeadlineSecondsEnvKey)
+	defer os.Unsetenv("SAVEDMODEL_EXTRACT_ACTIVE_DEADLINE_SECONDS")
+
+	modeljob := &modeljobsv1alpha1.ModelJob{}
+	if got := getActiveDeadlineSeconds(modeljob, "savedmodel-extract"); got != nil {
+		t.Errorf("getActiveDeadlineSeconds() = %v, want nil", *got)
+	}
+
+	os.Setenv(ModelJobActiveDeadlineSecondsEnvKey, "3600")
+	if got := getActiveDeadlineSeconds(modeljob, "savedmodel-extract"); got == nil || *got != 3600 {
+		t.Errorf("getActiveDeadlineSeconds() = %v, want 3600", got)
+	}
+
+	os.Setenv("SAVEDMODEL_EXTRACT_ACTIVE_DEADLINE_SECONDS", "600")
+	if got := getActiveDeadlineSeconds(modeljob, "savedmodel-extract"); got == nil || *got != 600 {
+		t.Errorf("getActiveDeadlineSeconds() = %v, want 600", got)
+	}
+	if got := getActiveDeadlineSeconds(modeljob, "onnx-extract"); got == nil || *got != 3600 {
+		t.Errorf("getActiveDeadlineSeconds() = %v, want 3600", got)
+	}
+
+	deadline := int64(60)
+	modeljob.Spec.ActiveDeadlineSeconds = &deadline
+	if got := getActiveDeadlineSeconds(modeljob, "savedmodel-extract"); got == nil || *got != deadline {
+		t.Errorf("getActiveDeadlineSeconds() = %v, want %v", got, deadline)
+	}
+}
+
+func Test_isJobDeadlineExceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []batchv1.JobCondition
+		want       bool
+	}{
+		{
+			name: "no conditions",
+			want: false,
+		},
+		{
+			name: "backoff limit exceeded",
+			conditions: []batchv1.JobCondition{
+				{
+					Type:   batchv1.JobFailed,
+					Status: corev1.ConditionTrue,
+					Reason: "BackoffLimitExceeded",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "deadline exceeded",
+			conditions: []batchv1.JobCondition{
+				{
+					Type:   batchv1.JobFailed,
+					Status: corev1.ConditionTrue,
+					Reason: jobReasonDeadlineExceeded,
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{Status: batchv1.JobStatus{Conditions: tt.conditions}}
+			if got := isJobDeadlineExceeded(job); got != tt.want {
+				t.Errorf("isJobDeadlineExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getModelJobStage(t *testing.T) {
+	status := &modeljobsv1alpha1.ModelJobStatus{}
+	if got := getModelJobStage(status); got != modelJobStagePull {
+		t.Errorf("getModelJobStage() = %v, want %v", got, modelJobStagePull)
+	}
+
+	setModelJobCondition(status, modeljobsv1alpha1.ModelJobModelPulled, corev1.ConditionTrue, conditionReasonModelPulled, "")
+	if got := getModelJobStage(status); got != modelJobStageTask {
+		t.Errorf("getModelJobStage() = %v, want %v", got, modelJobStageTask)
+	}
+
+	setModelJobCondition(status, modeljobsv1alpha1.ModelJobTaskCompleted, corev1.ConditionTrue, conditionReasonTaskCompleted, "")
+	if got := getModelJobStage(status); got != modelJobStagePush {
+		t.Errorf("getModelJobStage() = %v, want %v", got, modelJobStagePush)
+	}
+}
+
+func Test_getModelJobStage_runningPod(t *testing.T) {
+	newPods := func(ready bool) *corev1.PodList {
+		return &corev1.PodList{Items: []corev1.Pod{{
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  executorContainerName,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+					Ready: ready,
+				}},
+			},
+		}}}
+	}
+
+	tests := []struct {
+		name  string
+		ready bool
+		want  string
+	}{
+		{
+			name: "running the task",
+			want: modelJobStageTask,
+		},
+		{
+			name:  "pushing the model",
+			ready: true,
+			want:  modelJobStagePush,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &modeljobsv1alpha1.ModelJobStatus{}
+			setModelJobConditionsByPods(status, newPods(tt.ready))
+			if got := getModelJobStage(status); got != tt.want {
+				t.Errorf("getModelJobStage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
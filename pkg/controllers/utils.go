@@ -7,8 +7,8 @@ import (
 	"github.com/spf13/viper"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
 	"github.com/kleveross/klever-model-registry/pkg/common"
@@ -50,11 +50,13 @@ func replaceModelRefDomain(inputModelRef, ormbDomain string) (string, error) {
 	return modelRef, nil
 }
 
-func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, error) {
+// generateJobResource generates the job of modeljob, the credentials of registry are referenced from
+// the basic-auth Secret of credentialsSecret.
+func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob, converters []modeljobsv1alpha1.ModelConverter,
+	credentialsSecret string) (*batchv1.Job, error) {
 	var dstFormat modeljobsv1alpha1.Format
 	var dstFramework modeljobsv1alpha1.Framework
 	var srcFormat modeljobsv1alpha1.Format
-	var image string
 	var srcModelRef string
 	var dstModelRef string
 	var ormbDomain string
@@ -64,6 +66,9 @@ func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, er
 		if modeljob.Spec.DesiredTag == nil {
 			return nil, fmt.Errorf("modeljob desired tag is nil")
 		}
+		if modeljob.Spec.Conversion.MMdnn == nil {
+			return nil, fmt.Errorf("modeljob conversion mmdnn is nil")
+		}
 		ormbDomain = getORMBDomain(true)
 		dstModelRef, err = replaceModelRefDomain(*modeljob.Spec.DesiredTag, ormbDomain)
 		if err != nil {
@@ -72,48 +77,76 @@ func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, er
 		dstFormat = modeljob.Spec.Conversion.MMdnn.To
 		dstFramework = getFrameworkByFormat(dstFormat)
 		srcFormat = modeljob.Spec.Conversion.MMdnn.From
-		if imageEnv, ok := presetImage[strings.ToLower(string(modeljob.Spec.Conversion.MMdnn.From))+"-convert"]; ok {
-			image = viper.GetString(imageEnv)
-		}
-		if image == "" {
-			return nil, fmt.Errorf("failed get %v model convert image", modeljob.Spec.Conversion.MMdnn.From)
-		}
 	} else if modeljob.Spec.Extraction != nil {
 		ormbDomain = getORMBDomain(false)
 		dstModelRef = "empty"
 		dstFormat = modeljob.Spec.Extraction.Format
 		dstFramework = getFrameworkByFormat(dstFormat)
 		srcFormat = dstFormat
-		if imageEnv, ok := presetImage[strings.ToLower(string(dstFormat))+"-extract"]; ok {
-			image = viper.GetString(imageEnv)
+	} else if modeljob.Spec.Validation != nil {
+		// The validation does not push model, the result is annotated to the artifact in Harbor directly.
+		ormbDomain = getORMBDomain(false)
+		dstModelRef = "empty"
+		dstFormat = modeljob.Spec.Validation.Format
+		dstFramework = getFrameworkByFormat(dstFormat)
+		srcFormat = dstFormat
+	} else if modeljob.Spec.Optimization != nil {
+		if modeljob.Spec.DesiredTag == nil {
+			return nil, fmt.Errorf("modeljob desired tag is nil")
 		}
-		if image == "" {
-			return nil, fmt.Errorf("failed get %v model extract image", dstFormat)
+		ormbDomain = getORMBDomain(true)
+		dstModelRef, err = replaceModelRefDomain(*modeljob.Spec.DesiredTag, ormbDomain)
+		if err != nil {
+			return nil, err
 		}
+		dstFormat = modeljob.Spec.Optimization.Format
+		dstFramework = getFrameworkByFormat(dstFormat)
+		srcFormat = dstFormat
+	} else if modeljob.Spec.Benchmark != nil {
+		// The benchmark does not push model, the result is annotated to the artifact in Harbor directly.
+		ormbDomain = getORMBDomain(false)
+		dstModelRef = "empty"
+		dstFormat = modeljob.Spec.Benchmark.Format
+		dstFramework = getFrameworkByFormat(dstFormat)
+		srcFormat = dstFormat
 	} else {
 		return nil, fmt.Errorf("%v", "not support source")
 	}
 
+	if errs := validateModelJobScheduling(&modeljob.Spec, field.NewPath("spec")); len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+	if errs := validateWorkspace(modeljob.Spec.Workspace, field.NewPath("spec", "workspace")); len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+	if errs := validateModelSource(&modeljob.Spec, field.NewPath("spec")); len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+	if errs := validateExecutor(&modeljob.Spec, field.NewPath("spec")); len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	executor, err := resolveExecutor(modeljob, converters)
+	if err != nil {
+		return nil, err
+	}
+
 	srcModelRef, err = replaceModelRefDomain(modeljob.Spec.Model, ormbDomain)
 	if err != nil {
 		return nil, err
 	}
 
-	initContainers, err := generateInitContainers(modeljob)
+	initContainers, err := generateInitContainers(modeljob, credentialsSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	cpu, mem := "", ""
-	for _, val := range modeljob.Spec.Env {
-		if val.Name == ModelJobTaskCPUEnvKey {
-			cpu = val.Value
-		}
-		if val.Name == ModelJobTaskMEMEnvKey {
-			mem = val.Value
-		}
+	resources, err := getExecutorResources(modeljob, executor)
+	if err != nil {
+		return nil, err
 	}
-	resources := generateResources(cpu, mem)
+
+	workspace, workspaceSubPath := generateWorkspaceVolume(modeljob)
 
 	schedulerName := getSchedulerName()
 	backoffLimit := int32(0)
@@ -121,17 +154,35 @@ func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, er
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: modeljob.Namespace,
 			Name:      modeljob.Name,
+			Labels:    map[string]string{modeljobsv1alpha1.ModelJobNameLabelKey: modeljob.Name},
 		},
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{modeljobsv1alpha1.ModelJobNameLabelKey: modeljob.Name},
+				},
 				Spec: corev1.PodSpec{
 					InitContainers: initContainers,
 					Containers: []corev1.Container{
 						{
-							Name:            "executor",
-							Image:           image,
+							Name:            executorContainerName,
+							Image:           executor.image,
+							Command:         executor.command,
+							Args:            executor.args,
 							WorkingDir:      ModelJobWorkDir,
 							ImagePullPolicy: corev1.PullIfNotPresent,
+							// The executor writes the json of result to its termination message.
+							TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							// The executor is ready when it pushes the model, so that the push stage is observed from the pod.
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									Exec: &corev1.ExecAction{
+										Command: []string{"grep", "-qx", modelJobStagePush, modeljobsv1alpha1.StagePath},
+									},
+								},
+								PeriodSeconds: 5,
+							},
 							Env: []corev1.EnvVar{
 								corev1.EnvVar{
 									Name:  modeljobsv1alpha1.FrameworkEnvKey,
@@ -166,58 +217,77 @@ func generateJobResource(modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, er
 									Value: strings.ToLower(string(dstFormat)),
 								},
 								corev1.EnvVar{
-									Name:  common.ORMBDomainEnvKey,
-									Value: ormbDomain,
+									Name:  modeljobsv1alpha1.ResultPathEnvKey,
+									Value: corev1.TerminationMessagePathDefault,
+								},
+								corev1.EnvVar{
+									Name:  modeljobsv1alpha1.StagePathEnvKey,
+									Value: modeljobsv1alpha1.StagePath,
 								},
 								corev1.EnvVar{
-									Name:  common.ORMBUsernameEnvkey,
-									Value: viper.GetString(common.ORMBUsernameEnvkey),
+									Name:  common.ORMBDomainEnvKey,
+									Value: ormbDomain,
 								},
 								corev1.EnvVar{
-									Name:  common.ORMBPasswordEnvKey,
-									Value: viper.GetString(common.ORMBPasswordEnvKey),
+									Name:  modeljobsv1alpha1.TaskEnvKey,
+									Value: getModelJobType(modeljob),
 								},
 							},
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      ModelJobSharedVolumeName,
 									MountPath: modeljobsv1alpha1.SourceModelPath,
+									SubPath:   workspaceSubPath,
 								},
 							},
 							Resources: *resources,
 						},
 					},
-					Volumes: []corev1.Volume{
-						{
-							Name: ModelJobSharedVolumeName,
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-					},
-					RestartPolicy: corev1.RestartPolicyNever,
-					SchedulerName: schedulerName,
+					Volumes:            append([]corev1.Volume{workspace}, generateSourceVolumes(modeljob)...),
+					RestartPolicy:      corev1.RestartPolicyNever,
+					SchedulerName:      schedulerName,
+					NodeSelector:       modeljob.Spec.NodeSelector,
+					Tolerations:        modeljob.Spec.Tolerations,
+					Affinity:           modeljob.Spec.Affinity.DeepCopy(),
+					PriorityClassName:  modeljob.Spec.PriorityClassName,
+					ServiceAccountName: modeljob.Spec.ServiceAccountName,
 				},
 			},
-			BackoffLimit: &backoffLimit,
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: getActiveDeadlineSeconds(modeljob, executor.presetKey),
 		},
 	}
 
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+		generateValidationEnv(modeljob.Spec.Validation)...)
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+		generateOptimizationEnv(modeljob.Spec.Optimization)...)
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+		generateBenchmarkEnv(modeljob.Spec.Benchmark)...)
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+		generateRegistryCredentialsEnv(credentialsSecret, common.ORMBUsernameEnvkey, common.ORMBPasswordEnvKey)...)
+	// The env of modeljob overrides the extra env of modelconverter.
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, executor.env...)
 	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, modeljob.Spec.Env...)
 
 	return job, nil
 }
 
 // generateInitContainers will pull model from harbor and export the model to /models/input path
-func generateInitContainers(modeljob *modeljobsv1alpha1.ModelJob) ([]corev1.Container, error) {
+func generateInitContainers(modeljob *modeljobsv1alpha1.ModelJob, credentialsSecret string) ([]corev1.Container, error) {
 	if modeljob.Spec.InitContainer != nil {
 		return modeljob.Spec.InitContainer, nil
 	}
+	if modeljob.Spec.Source != nil {
+		resources, err := getInitializerResources(modeljob)
+		if err != nil {
+			return nil, err
+		}
+		return generateSourceInitContainers(modeljob, resources)
+	}
 
 	ormbDomain := viper.GetString(common.ORMBDomainEnvKey)
-	ormbUsername := viper.GetString(common.ORMBUsernameEnvkey)
-	ormbPassword := viper.GetString(common.ORMBPasswordEnvKey)
-	if ormbDomain == "" || ormbUsername == "" || ormbPassword == "" {
+	if ormbDomain == "" || credentialsSecret == "" {
 		return nil, nil
 	}
 
@@ -229,16 +299,11 @@ func generateInitContainers(modeljob *modeljobsv1alpha1.ModelJob) ([]corev1.Cont
 		return nil, fmt.Errorf("failed get ormb-storage-initializer image")
 	}
 
-	cpu, mem := "", ""
-	for _, val := range modeljob.Spec.Env {
-		if val.Name == ModelInitializerCPUEnvKey {
-			cpu = val.Value
-		}
-		if val.Name == ModelInitializerMEMEnvKey {
-			mem = val.Value
-		}
+	resources, err := getInitializerResources(modeljob)
+	if err != nil {
+		return nil, err
 	}
-	resources := generateResources(cpu, mem)
+	_, subPath := generateWorkspaceVolume(modeljob)
 
 	initContainers := []corev1.Container{
 		{
@@ -248,20 +313,12 @@ func generateInitContainers(modeljob *modeljobsv1alpha1.ModelJob) ([]corev1.Cont
 			// please refenrence https://github.com/kleveross/ormb/blob/master/cmd/ormb-storage-initializer/cmd/pull-and-export.go
 			Args:       []string{modeljob.Spec.Model, modeljobsv1alpha1.SourceModelPath, "--relayout=false"},
 			WorkingDir: ModelJobWorkDir,
-			Env: []corev1.EnvVar{
-				corev1.EnvVar{
-					Name:  "ORMB_USERNAME",
-					Value: ormbUsername,
-				},
-				corev1.EnvVar{
-					Name:  "ORMB_PASSWORD",
-					Value: ormbPassword,
-				},
-			},
+			Env:        generateRegistryCredentialsEnv(credentialsSecret, "ORMB_USERNAME", "ORMB_PASSWORD"),
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					Name:      ModelJobSharedVolumeName,
 					MountPath: modeljobsv1alpha1.SourceModelPath,
+					SubPath:   subPath,
 				},
 			},
 			Resources:       *resources,
@@ -280,21 +337,3 @@ func getSchedulerName() string {
 
 	return schedulerName
 }
-
-func generateResources(cpu, mem string) *corev1.ResourceRequirements {
-	if cpu == "" || mem == "" {
-		return &corev1.ResourceRequirements{}
-	}
-
-	cpuQuantity := resource.MustParse(cpu)
-	memQuantity := resource.MustParse(mem)
-
-	resourceList := corev1.ResourceList{
-		corev1.ResourceCPU:    cpuQuantity,
-		corev1.ResourceMemory: memQuantity,
-	}
-	return &corev1.ResourceRequirements{
-		Limits:   resourceList,
-		Requests: resourceList,
-	}
-}
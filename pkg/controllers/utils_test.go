@@ -246,7 +246,7 @@ func Test_generateJobResource(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := generateJobResource(tt.args.modeljob)
+			got, err := generateJobResource(tt.args.modeljob, nil, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateJobResource() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -306,7 +306,7 @@ func Test_generateInitContainers(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := generateInitContainers(tt.args.modeljob)
+			got, err := generateInitContainers(tt.args.modeljob, "test-modeljob-registry-credentials")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateInitContainers() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -314,6 +314,11 @@ func Test_generateInitContainers(t *testing.T) {
 			if got[0].Args[0] != tt.args.modeljob.Spec.Model {
 				t.Errorf("generateInitContainers() = %v, want %v", got, tt.want)
 			}
+			for _, env := range got[0].Env {
+				if env.Value != "" || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+					t.Errorf("generateInitContainers() env %v is not referenced from secret", env)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// defaultValidationTolerance is the default max absolute difference between the outputs and expected outputs.
+const defaultValidationTolerance = "1e-5"
+
+// generateValidationEnv generates the env of executor for the validation source.
+func generateValidationEnv(validation *modeljobsv1alpha1.ValidationSource) []corev1.EnvVar {
+	if validation == nil {
+		return nil
+	}
+
+	tolerance := validation.Tolerance
+	if tolerance == "" {
+		tolerance = defaultValidationTolerance
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  modeljobsv1alpha1.ValidationToleranceEnvKey,
+			Value: tolerance,
+		},
+	}
+	// The paths are relative to the directory of the pulled model.
+	if validation.SampleInputs != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name:  modeljobsv1alpha1.ValidationSampleInputsEnvKey,
+			Value: filepath.Join(modeljobsv1alpha1.SourceModelPath, validation.SampleInputs),
+		})
+	}
+	if validation.ExpectedOutputs != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name:  modeljobsv1alpha1.ValidationExpectedOutputsEnvKey,
+			Value: filepath.Join(modeljobsv1alpha1.SourceModelPath, validation.ExpectedOutputs),
+		})
+	}
+	return envs
+}
+
+// validateValidationParameters validates the sample files and tolerance of validation source.
+func validateValidationParameters(validation *modeljobsv1alpha1.ValidationSource, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	for _, item := range []struct {
+		name string
+		path string
+	}{
+		{"sampleInputs", validation.SampleInputs},
+		{"expectedOutputs", validation.ExpectedOutputs},
+	} {
+		if item.path == "" {
+			continue
+		}
+		if filepath.IsAbs(item.path) || strings.HasPrefix(filepath.Clean(item.path), "..") {
+			errs = append(errs, field.Invalid(fldPath.Child(item.name), item.path, "must be a relative path in the model"))
+		}
+	}
+
+	if validation.Tolerance != "" {
+		tolerance, err := strconv.ParseFloat(validation.Tolerance, 64)
+		if err != nil || tolerance < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("tolerance"), validation.Tolerance, "must be a non-negative number"))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_generateJobResource_validation(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	modeljob := &modeljobsv1alpha1.ModelJob{
+		Spec: modeljobsv1alpha1.ModelJobSpec{
+			Model: "release/savedmodel:v1",
+			ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+				Validation: &modeljobsv1alpha1.ValidationSource{
+					Format:          modeljobsv1alpha1.FormatSavedModel,
+					ExpectedOutputs: "samples/outputs.json",
+				},
+			},
+		},
+	}
+	job, err := generateJobResource(modeljob, nil, "")
+	if err != nil {
+		t.Fatalf("generateJobResource() error = %v", err)
+	}
+
+	envs := map[string]string{}
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		envs[env.Name] = env.Value
+	}
+	for name, want := range map[string]string{
+		modeljobsv1alpha1.TaskEnvKey:                      "validate",
+		modeljobsv1alpha1.DestinationModelTagEnvKey:       "empty",
+		modeljobsv1alpha1.ValidationToleranceEnvKey:       defaultValidationTolerance,
+		modeljobsv1alpha1.ValidationExpectedOutputsEnvKey: "/models/input/samples/outputs.json",
+	} {
+		if envs[name] != want {
+			t.Errorf("generateJobResource() env %v = %v, want %v", name, envs[name], want)
+		}
+	}
+	if _, ok := envs[modeljobsv1alpha1.ValidationSampleInputsEnvKey]; ok {
+		t.Errorf("generateJobResource() env %v is set, want the inputs generated", modeljobsv1alpha1.ValidationSampleInputsEnvKey)
+	}
+	if got := job.Spec.Template.Spec.Containers[0].Image; got != "demo.goharbor.com/release/savedmodel-extract:v0.2.0" {
+		t.Errorf("generateJobResource() image = %v, want the extraction image", got)
+	}
+}
+
+func Test_getModelJobMesageByPods_validationFailed(t *testing.T) {
+	pods := &corev1.PodList{Items: []corev1.Pod{{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: executorContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: ErrValidationFailed},
+				},
+			}},
+		},
+	}}}
+	if got, _ := getModelJobMesageByPods(pods); got != errValidationFailed {
+		t.Errorf("getModelJobMesageByPods() = %v, want %v", got, errValidationFailed)
+	}
+	if got := exitCodeToReason(ErrValidationFailed); got != reasonValidationFailed {
+		t.Errorf("exitCodeToReason() = %v, want %v", got, reasonValidationFailed)
+	}
+}
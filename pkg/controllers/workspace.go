@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+)
+
+// ephemeralStorageEvictionMessages is the parts of the messages of kubelet when the pod is evicted for the
+// ephemeral storage of node, the limit of emptyDir or the ephemeral storage limit of containers.
+var ephemeralStorageEvictionMessages = []string{
+	"ephemeral-storage",
+	"ephemeral local storage",
+	"EmptyDir volume",
+}
+
+// generateWorkspaceVolume generates the shared volume of modeljob by its workspace, and the sub path of the
+// volume which is mounted by the init containers and the executor.
+func generateWorkspaceVolume(modeljob *modeljobsv1alpha1.ModelJob) (corev1.Volume, string) {
+	volume := corev1.Volume{
+		Name: ModelJobSharedVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+
+	workspace := modeljob.Spec.Workspace
+	switch {
+	case workspace == nil:
+	case workspace.EmptyDir != nil:
+		if sizeLimit := workspace.EmptyDir.SizeLimit; sizeLimit != nil {
+			quantity := sizeLimit.DeepCopy()
+			volume.VolumeSource.EmptyDir.SizeLimit = &quantity
+		}
+	case workspace.Ephemeral != nil:
+		volume.VolumeSource = corev1.VolumeSource{
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{modeljobsv1alpha1.ModelJobNameLabelKey: modeljob.Name},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						StorageClassName: workspace.Ephemeral.StorageClassName,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: workspace.Ephemeral.Size.DeepCopy()},
+						},
+					},
+				},
+			},
+		}
+	case workspace.PersistentVolumeClaim != nil:
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: workspace.PersistentVolumeClaim.ClaimName,
+			},
+		}
+		subPath := workspace.PersistentVolumeClaim.SubPath
+		if subPath == "" {
+			subPath = modeljob.Name
+		}
+		return volume, subPath
+	}
+	return volume, ""
+}
+
+// validateWorkspace validates that only one member of workspace is set and the sizes and paths are valid.
+func validateWorkspace(workspace *modeljobsv1alpha1.Workspace, fldPath *field.Path) field.ErrorList {
+	if workspace == nil {
+		return nil
+	}
+	errs := field.ErrorList{}
+
+	set := []string{}
+	if workspace.EmptyDir != nil {
+		set = append(set, "emptyDir")
+		if sizeLimit := workspace.EmptyDir.SizeLimit; sizeLimit != nil && sizeLimit.Sign() <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("emptyDir", "sizeLimit"), sizeLimit.String(), "must be greater than 0"))
+		}
+	}
+	if workspace.Ephemeral != nil {
+		set = append(set, "ephemeral")
+		if workspace.Ephemeral.Size.Sign() <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("ephemeral", "size"), workspace.Ephemeral.Size.String(), "must be greater than 0"))
+		}
+		if name := workspace.Ephemeral.StorageClassName; name != nil && *name != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(*name) {
+				errs = append(errs, field.Invalid(fldPath.Child("ephemeral", "storageClassName"), *name, msg))
+			}
+		}
+	}
+	if pvc := workspace.PersistentVolumeClaim; pvc != nil {
+		set = append(set, "persistentVolumeClaim")
+		pvcPath := fldPath.Child("persistentVolumeClaim")
+		if pvc.ClaimName == "" {
+			errs = append(errs, field.Required(pvcPath.Child("claimName"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(pvc.ClaimName) {
+				errs = append(errs, field.Invalid(pvcPath.Child("claimName"), pvc.ClaimName, msg))
+			}
+		}
+		if pvc.SubPath != "" && !isRelativeSubPath(pvc.SubPath) {
+			errs = append(errs, field.Invalid(pvcPath.Child("subPath"), pvc.SubPath, "must be a relative path without '..'"))
+		}
+	}
+
+	switch len(set) {
+	case 0:
+		errs = append(errs, field.Required(fldPath, "one of emptyDir, ephemeral or persistentVolumeClaim must be set"))
+	case 1:
+	default:
+		errs = append(errs, field.Forbidden(fldPath.Child(set[1]), fmt.Sprintf("may not be set when %v is set", set[0])))
+	}
+	return errs
+}
+
+// isRelativeSubPath returns true if the path is relative and does not contain '..'.
+func isRelativeSubPath(subPath string) bool {
+	if strings.HasPrefix(subPath, "/") {
+		return false
+	}
+	for _, item := range strings.Split(subPath, "/") {
+		if item == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// getEphemeralStorageEvictionMessage returns the message if the pod is evicted for the ephemeral storage.
+func getEphemeralStorageEvictionMessage(pod *corev1.Pod) string {
+	if pod.Status.Reason != ReasonEvicted {
+		return ""
+	}
+	for _, message := range ephemeralStorageEvictionMessages {
+		if strings.Contains(pod.Status.Message, message) {
+			return fmt.Sprintf("%v, set spec.workspace for the large model: %v", errEphemeralStorageEvicted, pod.Status.Message)
+		}
+	}
+	return ""
+}
+
+// getFailedAttemptReason returns the reason of failed attempt, the eviction of pod takes precedence over
+// the exit code of the killed container.
+func getFailedAttemptReason(exitCode int32, message string) string {
+	if strings.HasPrefix(message, errEphemeralStorageEvicted) {
+		return reasonEphemeralStorageEvicted
+	}
+	return exitCodeToReason(exitCode)
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+func Test_generateJobResource_workspace(t *testing.T) {
+	initGlobalVar()
+	test.InitPresetModelImage()
+
+	storageClassName := "fast"
+	sizeLimit := resource.MustParse("20Gi")
+	tests := []struct {
+		name        string
+		workspace   *modeljobsv1alpha1.Workspace
+		wantSubPath string
+		check       func(volume corev1.Volume) bool
+	}{
+		{
+			name:      "default",
+			workspace: nil,
+			check: func(volume corev1.Volume) bool {
+				return volume.EmptyDir != nil && volume.EmptyDir.SizeLimit == nil
+			},
+		},
+		{
+			name:      "emptyDir",
+			workspace: &modeljobsv1alpha1.Workspace{EmptyDir: &modeljobsv1alpha1.EmptyDirWorkspace{SizeLimit: &sizeLimit}},
+			check: func(volume corev1.Volume) bool {
+				return volume.EmptyDir != nil && volume.EmptyDir.SizeLimit.Cmp(sizeLimit) == 0
+			},
+		},
+		{
+			name: "ephemeral",
+			workspace: &modeljobsv1alpha1.Workspace{Ephemeral: &modeljobsv1alpha1.EphemeralWorkspace{
+				StorageClassName: &storageClassName,
+				Size:             resource.MustParse("100Gi"),
+			}},
+			check: func(volume corev1.Volume) bool {
+				if volume.Ephemeral == nil || volume.Ephemeral.VolumeClaimTemplate == nil {
+					return false
+				}
+				spec := volume.Ephemeral.VolumeClaimTemplate.Spec
+				storage := spec.Resources.Requests[corev1.ResourceStorage]
+				return *spec.StorageClassName == "fast" && storage.String() == "100Gi"
+			},
+		},
+		{
+			name: "persistentVolumeClaim",
+			workspace: &modeljobsv1alpha1.Workspace{PersistentVolumeClaim: &modeljobsv1alpha1.PersistentVolumeClaimWorkspace{
+				ClaimName: "models",
+			}},
+			wantSubPath: "resnet",
+			check: func(volume corev1.Volume) bool {
+				return volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == "models"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modeljob := &modeljobsv1alpha1.ModelJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "resnet"},
+				Spec: modeljobsv1alpha1.ModelJobSpec{
+					Model:     "release/savedmodel:v1",
+					Workspace: tt.workspace,
+					ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+						Extraction: &modeljobsv1alpha1.ExtractionSource{Format: modeljobsv1alpha1.FormatSavedModel},
+					},
+				},
+			}
+			job, err := generateJobResource(modeljob, nil, "test-modeljob-registry-credentials")
+			if err != nil {
+				t.Fatalf("generateJobResource() error = %v", err)
+			}
+			podSpec := job.Spec.Template.Spec
+			if len(podSpec.Volumes) != 1 || !tt.check(podSpec.Volumes[0]) {
+				t.Errorf("generateJobResource() volumes = %v, want the workspace", podSpec.Volumes)
+			}
+			for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+				if got := container.VolumeMounts[0].SubPath; got != tt.wantSubPath {
+					t.Errorf("generateJobResource() container %v sub path = %v, want %v", container.Name, got, tt.wantSubPath)
+				}
+			}
+		})
+	}
+}
+
+func Test_validateWorkspace(t *testing.T) {
+	zero := resource.MustParse("0")
+	tests := []struct {
+		name      string
+		workspace *modeljobsv1alpha1.Workspace
+		wantErrs  []string
+	}{
+		{
+			name:      "empty",
+			workspace: &modeljobsv1alpha1.Workspace{},
+			wantErrs:  []string{"workspace"},
+		},
+		{
+			name: "both emptyDir and persistentVolumeClaim",
+			workspace: &modeljobsv1alpha1.Workspace{
+				EmptyDir:              &modeljobsv1alpha1.EmptyDirWorkspace{},
+				PersistentVolumeClaim: &modeljobsv1alpha1.PersistentVolumeClaimWorkspace{ClaimName: "models"},
+			},
+			wantErrs: []string{"workspace.persistentVolumeClaim"},
+		},
+		{
+			name:      "invalid ephemeral",
+			workspace: &modeljobsv1alpha1.Workspace{Ephemeral: &modeljobsv1alpha1.EphemeralWorkspace{Size: zero}},
+			wantErrs:  []string{"workspace.ephemeral.size"},
+		},
+		{
+			name: "invalid persistentVolumeClaim",
+			workspace: &modeljobsv1alpha1.Workspace{PersistentVolumeClaim: &modeljobsv1alpha1.PersistentVolumeClaimWorkspace{
+				SubPath: "../models",
+			}},
+			wantErrs: []string{"workspace.persistentVolumeClaim.claimName", "workspace.persistentVolumeClaim.subPath"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateWorkspace(tt.workspace, field.NewPath("workspace"))
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("validateWorkspace() = %v, want errors of %v", errs, tt.wantErrs)
+				return
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantErrs[i] {
+					t.Errorf("validateWorkspace() error field = %v, want %v", err.Field, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_getModelJobMesageByPods_ephemeralStorageEvicted(t *testing.T) {
+	pods := &corev1.PodList{Items: []corev1.Pod{{
+		Status: corev1.PodStatus{
+			Phase:   corev1.PodFailed,
+			Reason:  ReasonEvicted,
+			Message: `Usage of EmptyDir volume "model" exceeds the limit "20Gi". `,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: executorContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 137},
+				},
+			}},
+		},
+	}}}
+	message, _ := getModelJobMesageByPods(pods)
+	if !strings.HasPrefix(message, errEphemeralStorageEvicted) {
+		t.Errorf("getModelJobMesageByPods() = %v, want %v", message, errEphemeralStorageEvicted)
+	}
+	if got := getFailedAttemptReason(137, message); got != reasonEphemeralStorageEvicted {
+		t.Errorf("getFailedAttemptReason() = %v, want %v", got, reasonEphemeralStorageEvicted)
+	}
+	if got := getFailureReason(message); got != reasonEphemeralStorageEvicted {
+		t.Errorf("getFailureReason() = %v, want %v", got, reasonEphemeralStorageEvicted)
+	}
+
+	// The pod evicted for the memory of node is not reported as the ephemeral storage.
+	pods.Items[0].Status.Message = "The node was low on resource: memory."
+	if message, _ = getModelJobMesageByPods(pods); strings.HasPrefix(message, errEphemeralStorageEvicted) {
+		t.Errorf("getModelJobMesageByPods() = %v, want the exit code message", message)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descriptors
+
+import (
+	"context"
+
+	"github.com/caicloud/nirvana/definition"
+
+	"github.com/kleveross/klever-model-registry/pkg/registry/client"
+	"github.com/kleveross/klever-model-registry/pkg/registry/modelconverter"
+	"github.com/kleveross/klever-model-registry/pkg/registry/paging"
+)
+
+var modelconverterController *modelconverter.ModelConverterController
+
+func init() {
+	register(modelconverterAPI)
+}
+
+// InitModelConverterController inits the modelconverter controller
+func InitModelConverterController() {
+	modelconverterController = modelconverter.New(client.GetKubeKleverOssModelConverterInformer())
+}
+
+var modelconverterAPI = definition.Descriptor{
+	Description: "APIs for modelconverter",
+	Children: []definition.Descriptor{
+		{
+			Path:        "/modelconverters",
+			Definitions: []definition.Definition{listModelConverter},
+		},
+		{
+			Path:        "/conversions",
+			Definitions: []definition.Definition{listConversion},
+		},
+	},
+}
+
+var listModelConverter = definition.Definition{
+	Method:      definition.List,
+	Summary:     "List modelconverter",
+	Description: "List modelconverter",
+	Parameters: []definition.Parameter{
+		paging.PageDefinitionParameter(),
+	},
+	Results: definition.DataErrorResults("modelconverter list"),
+	Function: func(ctx context.Context, opt *paging.ListOption) (*modelconverter.ModelConverterList, error) {
+		return modelconverterController.List(opt)
+	},
+}
+
+var listConversion = definition.Definition{
+	Method:      definition.List,
+	Summary:     "List conversion",
+	Description: "List the extractions and conversions which can be run in the cluster",
+	Parameters: []definition.Parameter{
+		definition.QueryParameterFor("type", "conversion type, enum: [Extraction, Conversion]"),
+		paging.PageDefinitionParameter(),
+	},
+	Results: definition.DataErrorResults("conversion list"),
+	Function: func(ctx context.Context, conversionType string, opt *paging.ListOption) (*modelconverter.ConversionList, error) {
+		return modelconverterController.ListConversions(conversionType, opt)
+	},
+}
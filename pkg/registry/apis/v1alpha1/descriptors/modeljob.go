@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/caicloud/nirvana/definition"
+	batchv1 "k8s.io/api/batch/v1"
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
 	"github.com/kleveross/klever-model-registry/pkg/registry/client"
@@ -43,10 +44,18 @@ var modeljobAPI = definition.Descriptor{
 			Path:        "/namespaces/{namespace}/modeljobs",
 			Definitions: []definition.Definition{createModelJob, listModelJob},
 		},
+		{
+			Path:        "/namespaces/{namespace}/modeljobs:render",
+			Definitions: []definition.Definition{renderModelJob},
+		},
 		{
 			Path:        "/namespaces/{namespace}/modeljobs/{modeljobID}",
 			Definitions: []definition.Definition{deleteModelJob, getModelJob},
 		},
+		{
+			Path:        "/namespaces/{namespace}/modeljobs/{modeljobID}/cancel",
+			Definitions: []definition.Definition{cancelModelJob},
+		},
 	},
 }
 
@@ -67,6 +76,20 @@ var createModelJob = definition.Definition{
 	},
 }
 
+var renderModelJob = definition.Definition{
+	Method:      definition.Create,
+	Summary:     "Render modeljob",
+	Description: "Render the job of modeljob without creating anything, the sensitive env is redacted",
+	Parameters: []definition.Parameter{
+		definition.PathParameterFor("namespace", "namespace"),
+		definition.BodyParameterFor("modeljob body"),
+	},
+	Results: definition.DataErrorResults("job"),
+	Function: func(ctx context.Context, namespace string, job *modeljobsv1alpha1.ModelJob) (*batchv1.Job, error) {
+		return modeljob.RenderJob(namespace, job)
+	},
+}
+
 var listModelJob = definition.Definition{
 	Method:      definition.List,
 	Summary:     "List modeljob",
@@ -112,3 +135,19 @@ var deleteModelJob = definition.Definition{
 		return modeljobController.Delete(namespace, modeljobID)
 	},
 }
+
+var cancelModelJob = definition.Definition{
+	Method:      definition.Create,
+	Summary:     "Cancel modeljob",
+	Description: "Cancel modeljob, its running job is deleted",
+	Parameters: []definition.Parameter{
+		definition.PathParameterFor("namespace", "namespace"),
+		definition.PathParameterFor("modeljobID", "modeljob id"),
+	},
+	Results: []definition.Result{
+		definition.ErrorResult(),
+	},
+	Function: func(ctx context.Context, namespace, modeljobID string) error {
+		return modeljobController.Cancel(namespace, modeljobID)
+	},
+}
@@ -18,21 +18,30 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/kleveross/ormb/pkg/oras"
 	"github.com/kleveross/ormb/pkg/ormb"
 	seldonv1 "github.com/seldonio/seldon-core/operator/client/machinelearning.seldon.io/v1/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 
 	kleverossv1alpha1 "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned"
+	kleverossscheme "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned/scheme"
 	kleverossinformers "github.com/kleveross/klever-model-registry/pkg/clientset/informers/externalversions"
 	"github.com/kleveross/klever-model-registry/pkg/clientset/informers/externalversions/modeljob/v1alpha1"
 )
 
+// cacheSyncTimeout is the timeout of waiting for the caches of informers synced, model-registry fails to start
+// instead of hanging when it is not allowed to list or watch the resources.
+const cacheSyncTimeout = 2 * time.Minute
+
 var (
 	// kubeMainClient is the client for k8s builtin resource.
 	kubeMainClient kubernetes.Interface
@@ -41,12 +50,17 @@ var (
 	kubeKleverOssClient kleverossv1alpha1.Interface
 	// kleverOssModelJobInformer is the informer of kleveross.
 	kleverOssModelJobInformer v1alpha1.ModelJobInformer
+	// kleverOssModelConverterInformer is the informer of modelconverter.
+	kleverOssModelConverterInformer v1alpha1.ModelConverterInformer
 
 	// kubeSeldonClient is the client of seldon development.
 	kubeSeldonClient seldonv1.Interface
 
 	// ormbClient is interact with harbor.
 	ormbClient ormb.Interface
+
+	// eventRecorder records the events of klever CRD.
+	eventRecorder record.EventRecorder
 )
 
 func GetKubeMainClient() kubernetes.Interface {
@@ -61,6 +75,10 @@ func GetKubeKleverOssModelJobInformer() v1alpha1.ModelJobInformer {
 	return kleverOssModelJobInformer
 }
 
+func GetKubeKleverOssModelConverterInformer() v1alpha1.ModelConverterInformer {
+	return kleverOssModelConverterInformer
+}
+
 func GetKubeSeldonClient() seldonv1.Interface {
 	return kubeSeldonClient
 }
@@ -69,6 +87,10 @@ func GetORMBClient() ormb.Interface {
 	return ormbClient
 }
 
+func GetEventRecorder() record.EventRecorder {
+	return eventRecorder
+}
+
 // InitClient initializes the client.
 func InitClient(kubeconfigPath, domain, username, password string,
 	stopCh <-chan struct{}) error {
@@ -83,6 +105,11 @@ func InitClient(kubeconfigPath, domain, username, password string,
 		return err
 	}
 
+	// init event recorder
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeMainClient.CoreV1().Events("")})
+	eventRecorder = broadcaster.NewRecorder(kleverossscheme.Scheme, corev1.EventSource{Component: "model-registry"})
+
 	// init kleveross client
 	kubeKleverOssClient, err = kleverossv1alpha1.NewForConfig(config)
 	if err != nil {
@@ -91,11 +118,19 @@ func InitClient(kubeconfigPath, domain, username, password string,
 
 	factory := kleverossinformers.NewSharedInformerFactory(kubeKleverOssClient, 30*time.Second)
 
+	kleverOssModelJobInformer = factory.Kleveross().V1alpha1().ModelJobs()
+	kleverOssModelConverterInformer = factory.Kleveross().V1alpha1().ModelConverters()
+	// Informers must be requested before the factory starts, otherwise they are never started.
+	kleverOssModelJobInformer.Informer()
+	kleverOssModelConverterInformer.Informer()
+
 	go factory.Start(stopCh)
 
-	kleverOssModelJobInformer = factory.Kleveross().V1alpha1().ModelJobs()
-	if !cache.WaitForCacheSync(stopCh, kleverOssModelJobInformer.Informer().HasSynced) {
-		return fmt.Errorf("failed to wait for modeljob synced")
+	if err := waitForCacheSync(stopCh, "modeljobs", kleverOssModelJobInformer.Informer().HasSynced); err != nil {
+		return err
+	}
+	if err := waitForCacheSync(stopCh, "modelconverters", kleverOssModelConverterInformer.Informer().HasSynced); err != nil {
+		return err
 	}
 
 	// init seldon core client
@@ -116,3 +151,21 @@ func InitClient(kubeconfigPath, domain, username, password string,
 
 	return nil
 }
+
+// waitForCacheSync waits for the cache of the informer of resource synced in cacheSyncTimeout.
+func waitForCacheSync(stopCh <-chan struct{}, resource string, cacheSynced cache.InformerSynced) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cacheSyncTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if !cache.WaitForCacheSync(ctx.Done(), cacheSynced) {
+		return fmt.Errorf("failed to wait for %v synced in %v, check that model-registry is allowed to list and watch %v", resource, cacheSyncTimeout, resource)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/caicloud/nirvana/log"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kleveross/klever-model-registry/pkg/registry/client"
@@ -82,8 +83,26 @@ func (p *proxy) createModelJob(path string, byteManifests []byte) error {
 			return nil
 		}
 
-		modeljobObj := modeljob.GenerateExtractionModelJob(p.Domain, projectName, modelName, versionName, format.(string))
-		_, err := client.GetKubeKleverOssClient().KleverossV1alpha1().ModelJobs("default").Create(context.Background(), modeljobObj, metav1.CreateOptions{})
+		namespace, err := modeljob.GetNamespace(projectName)
+		if err != nil {
+			return err
+		}
+
+		// The extracted artifact and the artifact being extracted are not extracted again, e.g. it is pushed twice or
+		// the extracted one is retagged.
+		modeljobObj := modeljob.GenerateExtractionModelJob(namespace, p.Domain, projectName, modelName, versionName, format.(string), found.Digest)
+		reused, err := modeljob.FindReusableExtraction(client.GetKubeKleverOssClient(), namespace, modeljobObj.Spec.Model, found.Digest)
+		if err != nil {
+			return err
+		}
+		if reused != nil {
+			log.Infof("the modeljob %v/%v is reused for %v/%v:%v", reused.Namespace, reused.Name, projectName, modelName, versionName)
+			client.GetEventRecorder().Eventf(reused, corev1.EventTypeNormal, modeljob.ReasonExtractionReused,
+				"the extraction is reused by %v/%v:%v of the same digest %v", projectName, modelName, versionName, found.Digest)
+			return nil
+		}
+
+		_, err = client.GetKubeKleverOssClient().KleverossV1alpha1().ModelJobs(namespace).Create(context.Background(), modeljobObj, metav1.CreateOptions{})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,141 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package modelconverter
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/clientset/informers/externalversions/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/registry/errors"
+	"github.com/kleveross/klever-model-registry/pkg/registry/paging"
+)
+
+type ModelConverterController struct {
+	modelconverterInformer v1alpha1.ModelConverterInformer
+}
+
+func New(modelconverterInformer v1alpha1.ModelConverterInformer) *ModelConverterController {
+	return &ModelConverterController{
+		modelconverterInformer: modelconverterInformer,
+	}
+}
+
+// ModelConverterList is the response of List Interface.
+type ModelConverterList struct {
+	ListMeta paging.ListMeta                     `json:"metadata"`
+	Items    []*modeljobsv1alpha1.ModelConverter `json:"items"`
+}
+
+// Conversion is the extraction or conversion which can be run in the cluster.
+type Conversion struct {
+	// Type is Extraction or Conversion.
+	Type modeljobsv1alpha1.ModelConverterType `json:"type"`
+	// SourceFormat is the format of source model.
+	SourceFormat modeljobsv1alpha1.Format `json:"sourceFormat"`
+	// TargetFormat is the format of converted model, it is empty for extraction
+	// and the conversion which accepts all target formats.
+	TargetFormat modeljobsv1alpha1.Format `json:"targetFormat,omitempty"`
+	// ModelConverter is the name of modelconverter which runs it.
+	ModelConverter string `json:"modelConverter"`
+}
+
+// ConversionList is the response of ListConversions Interface.
+type ConversionList struct {
+	ListMeta paging.ListMeta `json:"metadata"`
+	Items    []*Conversion   `json:"items"`
+}
+
+func (m ModelConverterController) List(opt *paging.ListOption) (*ModelConverterList, error) {
+	converters, err := m.listModelConverters()
+	if err != nil {
+		return nil, err
+	}
+
+	datas := paging.Page(converters, opt)
+	converterList := &ModelConverterList{
+		ListMeta: paging.ListMeta{
+			TotalItems: datas.TotalItems,
+		},
+		Items: []*modeljobsv1alpha1.ModelConverter{},
+	}
+	for _, d := range datas.Items {
+		converterList.Items = append(converterList.Items, d.(*modeljobsv1alpha1.ModelConverter))
+	}
+	return converterList, nil
+}
+
+// ListConversions lists the extractions and conversions declared by modelconverters,
+// the typeFilter is Extraction or Conversion, all types are listed if it is empty.
+func (m ModelConverterController) ListConversions(typeFilter string, opt *paging.ListOption) (*ConversionList, error) {
+	converters, err := m.listModelConverters()
+	if err != nil {
+		return nil, err
+	}
+
+	conversions := []*Conversion{}
+	for _, converter := range converters {
+		if typeFilter != "" && string(converter.Spec.Type) != typeFilter {
+			continue
+		}
+		conversions = append(conversions, toConversions(converter)...)
+	}
+
+	datas := paging.Page(conversions, opt)
+	conversionList := &ConversionList{
+		ListMeta: paging.ListMeta{
+			TotalItems: datas.TotalItems,
+		},
+		Items: []*Conversion{},
+	}
+	for _, d := range datas.Items {
+		conversionList.Items = append(conversionList.Items, d.(*Conversion))
+	}
+	return conversionList, nil
+}
+
+func (m ModelConverterController) listModelConverters() ([]*modeljobsv1alpha1.ModelConverter, error) {
+	converters, err := m.modelconverterInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, errors.RenderError(err)
+	}
+
+	sort.SliceStable(converters, func(i, j int) bool {
+		return converters[i].Name < converters[j].Name
+	})
+	return converters, nil
+}
+
+// toConversions returns the conversions of each source and target format of modelconverter.
+func toConversions(converter *modeljobsv1alpha1.ModelConverter) []*Conversion {
+	targetFormats := converter.Spec.TargetFormats
+	if converter.Spec.Type == modeljobsv1alpha1.ModelConverterTypeExtraction || len(targetFormats) == 0 {
+		targetFormats = []modeljobsv1alpha1.Format{""}
+	}
+
+	conversions := []*Conversion{}
+	for _, source := range converter.Spec.SourceFormats {
+		for _, target := range targetFormats {
+			conversions = append(conversions, &Conversion{
+				Type:           converter.Spec.Type,
+				SourceFormat:   source,
+				TargetFormat:   target,
+				ModelConverter: converter.Name,
+			})
+		}
+	}
+	return conversions
+}
@@ -0,0 +1,68 @@
+package modelconverter_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobfake "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned/fake"
+	kleverossinformers "github.com/kleveross/klever-model-registry/pkg/clientset/informers/externalversions"
+	"github.com/kleveross/klever-model-registry/pkg/registry/modelconverter"
+)
+
+var (
+	modelconverterController *modelconverter.ModelConverterController
+	stopCh                   = make(chan struct{})
+)
+
+func TestModelConverter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ModelConverter Suite")
+}
+
+var _ = BeforeSuite(func() {
+	kleverossClient := modeljobfake.NewSimpleClientset(
+		&modeljobsv1alpha1.ModelConverter{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "savedmodel-extract",
+			},
+			Spec: modeljobsv1alpha1.ModelConverterSpec{
+				Type:          modeljobsv1alpha1.ModelConverterTypeExtraction,
+				SourceFormats: []modeljobsv1alpha1.Format{modeljobsv1alpha1.FormatSavedModel},
+				Image:         "ghcr.io/kleveross/savedmodel-extract:v0.3.0",
+			},
+		},
+		&modeljobsv1alpha1.ModelConverter{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "mmdnn",
+			},
+			Spec: modeljobsv1alpha1.ModelConverterSpec{
+				Type:          modeljobsv1alpha1.ModelConverterTypeConversion,
+				SourceFormats: []modeljobsv1alpha1.Format{modeljobsv1alpha1.FormatH5, modeljobsv1alpha1.FormatCaffeModel},
+				TargetFormats: []modeljobsv1alpha1.Format{modeljobsv1alpha1.FormatONNX},
+				Image:         "ghcr.io/kleveross/mmdnn:v0.3.0",
+			},
+		},
+	)
+	factory := kleverossinformers.NewSharedInformerFactory(kleverossClient, 30*time.Second)
+	modelconverterInformer := factory.Kleveross().V1alpha1().ModelConverters()
+	modelconverterInformer.Informer()
+
+	go factory.Start(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, modelconverterInformer.Informer().HasSynced) {
+		panic(fmt.Errorf("failed to wait for modelconverter synced"))
+	}
+
+	modelconverterController = modelconverter.New(modelconverterInformer)
+})
+
+var _ = AfterSuite(func() {
+	close(stopCh)
+})
@@ -0,0 +1,31 @@
+package modelconverter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/registry/paging"
+)
+
+var _ = Describe("ModelConverter API", func() {
+	It("Should list modelconverters successfully", func() {
+		converters, err := modelconverterController.List(&paging.ListOption{Start: 0})
+		Expect(err).To(BeNil())
+		Expect(converters.ListMeta.TotalItems).To(Equal(2))
+		Expect(converters.Items[0].Name).To(Equal("mmdnn"))
+	})
+
+	It("Should list conversions successfully", func() {
+		conversions, err := modelconverterController.ListConversions("", &paging.ListOption{Start: 0})
+		Expect(err).To(BeNil())
+		Expect(conversions.ListMeta.TotalItems).To(Equal(3))
+
+		conversions, err = modelconverterController.ListConversions(string(modeljobsv1alpha1.ModelConverterTypeConversion), &paging.ListOption{Start: 0})
+		Expect(err).To(BeNil())
+		Expect(conversions.ListMeta.TotalItems).To(Equal(2))
+		Expect(conversions.Items[0].SourceFormat).To(Equal(modeljobsv1alpha1.FormatH5))
+		Expect(conversions.Items[0].TargetFormat).To(Equal(modeljobsv1alpha1.FormatONNX))
+		Expect(conversions.Items[0].ModelConverter).To(Equal("mmdnn"))
+	})
+})
@@ -0,0 +1,72 @@
+package modeljob
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	clientset "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned"
+	"github.com/kleveross/klever-model-registry/pkg/controllers"
+)
+
+// ReasonExtractionReused is the reason of the event recorded on the extraction modeljob when it is reused
+// by the artifact of the same digest.
+const ReasonExtractionReused = "ExtractionReused"
+
+// FindReusableExtraction returns the extraction modeljob in the namespace which makes the pushed artifact of model
+// and digest need no extraction: the succeeded one whose extracted model has the digest, e.g. the artifact is pushed
+// by the executor after extraction or the extracted artifact is retagged, or the one of the same model and digest
+// which is still running, e.g. the artifact is pushed twice. The executor pushes the extracted model to the same tag,
+// so the other tags of the same digest before extraction are extracted again. The modeljobs are selected by the
+// digest label of the pushed artifact and the result digest label of the operator. It returns nil if there is none
+// or the digest is empty.
+func FindReusableExtraction(kleverossClient clientset.Interface, namespace, model, digest string) (*modeljobsv1alpha1.ModelJob, error) {
+	if digest == "" {
+		return nil, nil
+	}
+	value := controllers.DigestLabelValue(digest)
+
+	succeeded, err := listExtractions(kleverossClient, namespace, modeljobsv1alpha1.ModelJobResultDigestLabelKey, value)
+	if err != nil {
+		return nil, err
+	}
+	for i := range succeeded {
+		modeljob := &succeeded[i]
+		if modeljob.DeletionTimestamp == nil && modeljob.Status.Phase == modeljobsv1alpha1.ModelJobSucceeded &&
+			modeljob.Status.Result != nil && modeljob.Status.Result.Digest == digest {
+			return modeljob, nil
+		}
+	}
+
+	running, err := listExtractions(kleverossClient, namespace, modeljobsv1alpha1.ModelJobDigestLabelKey, value)
+	if err != nil {
+		return nil, err
+	}
+	for i := range running {
+		modeljob := &running[i]
+		if modeljob.DeletionTimestamp != nil {
+			continue
+		}
+		switch modeljob.Status.Phase {
+		case modeljobsv1alpha1.ModelJobSucceeded, modeljobsv1alpha1.ModelJobFailed, modeljobsv1alpha1.ModelJobCancelled:
+		default:
+			if modeljob.Spec.Model == model && modeljob.Annotations[modeljobsv1alpha1.ModelJobDigestLabelKey] == digest {
+				return modeljob, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// listExtractions lists the extraction modeljobs in the namespace which have the label of key and value.
+func listExtractions(kleverossClient clientset.Interface, namespace, key, value string) ([]modeljobsv1alpha1.ModelJob, error) {
+	selector := labels.Set{modeljobsv1alpha1.ExtractLabelKey: "true", key: value}.String()
+	modeljobs, err := kleverossClient.KleverossV1alpha1().ModelJobs(namespace).List(context.Background(),
+		metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return modeljobs.Items, nil
+}
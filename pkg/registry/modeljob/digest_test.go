@@ -0,0 +1,68 @@
+package modeljob_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	modeljobfake "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned/fake"
+	"github.com/kleveross/klever-model-registry/pkg/controllers"
+	"github.com/kleveross/klever-model-registry/pkg/registry/modeljob"
+)
+
+var _ = Describe("Modeljob digest", func() {
+	const digest = "sha256:8a46ecd5e3c67f4c5ab8d6d8d4bdd3d5c1c2d7e4a3f9e7b6b7e8b9a4c7d5e3f21"
+
+	It("Should label the extraction modeljob with digest", func() {
+		modeljobObj := modeljob.GenerateExtractionModelJob("default", "harbor.io", "release", "resnet", "v1", "SavedModel", digest)
+		Expect(modeljobObj.Labels).To(HaveKeyWithValue(modeljobsv1alpha1.ModelJobDigestLabelKey, digest[len("sha256:"):len("sha256:")+63]))
+		Expect(modeljobObj.Annotations).To(HaveKeyWithValue(modeljobsv1alpha1.ModelJobDigestLabelKey, digest))
+	})
+
+	It("Should find the reusable extraction of digest", func() {
+		const extractedDigest = "sha256:0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0"
+		kleverossClient := modeljobfake.NewSimpleClientset()
+		create := func(name, version string, phase modeljobsv1alpha1.ModelJobPhase, result *modeljobsv1alpha1.ModelJobResult) {
+			modeljobObj := modeljob.GenerateExtractionModelJob("default", "harbor.io", "release", "resnet", version, "SavedModel", digest)
+			modeljobObj.Name = name
+			modeljobObj.Status.Phase = phase
+			modeljobObj.Status.Result = result
+			if result != nil {
+				// The operator labels the succeeded extraction with the digest of result.
+				modeljobObj.Labels[modeljobsv1alpha1.ModelJobResultDigestLabelKey] = controllers.DigestLabelValue(result.Digest)
+			}
+			_, err := kleverossClient.KleverossV1alpha1().ModelJobs("default").Create(context.Background(), modeljobObj, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+		}
+
+		// The failed extraction is not reused.
+		create("failed", "v1", modeljobsv1alpha1.ModelJobFailed, nil)
+		reused, err := modeljob.FindReusableExtraction(kleverossClient, "default", "harbor.io/release/resnet:v1", digest)
+		Expect(err).To(BeNil())
+		Expect(reused).To(BeNil())
+
+		// The running extraction is reused by the same model.
+		create("running", "v1", modeljobsv1alpha1.ModelJobRunning, nil)
+		reused, err = modeljob.FindReusableExtraction(kleverossClient, "default", "harbor.io/release/resnet:v1", digest)
+		Expect(err).To(BeNil())
+		Expect(reused.Name).To(Equal("running"))
+
+		// The other tags of the same digest before extraction are extracted again.
+		create("succeeded", "v2", modeljobsv1alpha1.ModelJobSucceeded, &modeljobsv1alpha1.ModelJobResult{Digest: extractedDigest})
+		reused, err = modeljob.FindReusableExtraction(kleverossClient, "default", "harbor.io/release/resnet:v3", digest)
+		Expect(err).To(BeNil())
+		Expect(reused).To(BeNil())
+
+		// The extracted artifact is not extracted again.
+		reused, err = modeljob.FindReusableExtraction(kleverossClient, "default", "harbor.io/release/resnet:v3", extractedDigest)
+		Expect(err).To(BeNil())
+		Expect(reused.Name).To(Equal("succeeded"))
+
+		reused, err = modeljob.FindReusableExtraction(kleverossClient, "default", "harbor.io/release/resnet:v1", "")
+		Expect(err).To(BeNil())
+		Expect(reused).To(BeNil())
+	})
+})
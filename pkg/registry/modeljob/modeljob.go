@@ -17,10 +17,12 @@ package modeljob
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
 	clientset "github.com/kleveross/klever-model-registry/pkg/clientset/clientset/versioned"
@@ -46,11 +48,21 @@ func (m ModelJobController) Create(namespace string, modeljob *modeljobsv1alpha1
 		modeljob.ObjectMeta.Name = generateModelJobName()
 	}
 
-	// Create will create convert task, so we will set "modeljob/convert"="true" label to flag it.
+	// Create will create convert task, so we will set "modeljob/convert"="true" label to flag it,
+	// the validation, optimization and benchmark tasks are flagged by their own labels.
 	if modeljob.ObjectMeta.Labels == nil {
 		modeljob.ObjectMeta.Labels = map[string]string{}
 	}
-	modeljob.ObjectMeta.Labels[convertLabelKey] = "true"
+	switch {
+	case modeljob.Spec.Validation != nil:
+		modeljob.ObjectMeta.Labels[modeljobsv1alpha1.ValidateLabelKey] = "true"
+	case modeljob.Spec.Optimization != nil:
+		modeljob.ObjectMeta.Labels[modeljobsv1alpha1.OptimizeLabelKey] = "true"
+	case modeljob.Spec.Benchmark != nil:
+		modeljob.ObjectMeta.Labels[modeljobsv1alpha1.BenchmarkLabelKey] = "true"
+	default:
+		modeljob.ObjectMeta.Labels[modeljobsv1alpha1.ConvertLabelKey] = "true"
+	}
 
 	result, err := m.kleverossClient.KleverossV1alpha1().ModelJobs(namespace).Create(context.Background(), modeljob, metav1.CreateOptions{})
 	if err != nil {
@@ -69,6 +81,28 @@ func (m ModelJobController) Get(namespace, modeljobID string) (*modeljobsv1alpha
 	return modeljob, nil
 }
 
+// Cancel sets the cancel annotation of modeljob, the modeljob-operator deletes its job and sets it Cancelled.
+func (m ModelJobController) Cancel(namespace, modeljobID string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				modeljobsv1alpha1.ModelJobCancelAnnotationKey: "true",
+			},
+		},
+	})
+	if err != nil {
+		return errors.RenderError(err)
+	}
+
+	_, err = m.kleverossClient.KleverossV1alpha1().ModelJobs(namespace).Patch(context.Background(), modeljobID,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return errors.RenderError(err)
+	}
+
+	return nil
+}
+
 func (m ModelJobController) Delete(namespace, modeljobID string) error {
 	err := m.kleverossClient.KleverossV1alpha1().ModelJobs(namespace).Delete(context.Background(), modeljobID, metav1.DeleteOptions{})
 	if err != nil {
@@ -0,0 +1,103 @@
+package modeljob
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// envNamespaceMappingFile is the path of the file which maps the Harbor projects to namespaces,
+	// it is usually mounted from a ConfigMap.
+	envNamespaceMappingFile = "MODELJOB_NAMESPACE_MAPPING_FILE"
+
+	// defaultNamespace is the namespace of the extraction modeljobs if there is no mapping.
+	defaultNamespace = "default"
+	// projectPlaceholder is replaced by the name of project in the fallback namespace.
+	projectPlaceholder = "{project}"
+)
+
+// NamespaceMapping maps the Harbor projects to the namespaces of the extraction modeljobs
+// created when the models are pushed or uploaded.
+type NamespaceMapping struct {
+	// Projects is the namespace of the projects.
+	Projects map[string]string `json:"projects,omitempty"`
+	// Fallback is the namespace of the projects which are not in Projects, "{project}" in it is
+	// replaced by the name of project, e.g. "models-{project}". It is "default" if not set.
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// Namespace returns the namespace of the extraction modeljobs of the project.
+func (m *NamespaceMapping) Namespace(project string) (string, error) {
+	namespace, ok := m.Projects[project]
+	if !ok {
+		namespace = m.Fallback
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		namespace = strings.ReplaceAll(namespace, projectPlaceholder, project)
+	}
+
+	if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+		return "", fmt.Errorf("invalid namespace %q for project %q: %s", namespace, project, strings.Join(errs, ", "))
+	}
+	return namespace, nil
+}
+
+// namespaceMappingCache caches the mapping file, it is loaded again when the file is modified,
+// e.g. the ConfigMap is updated.
+var namespaceMappingCache struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+	mapping *NamespaceMapping
+}
+
+// GetNamespace returns the namespace of the extraction modeljobs of the project by the mapping file.
+func GetNamespace(project string) (string, error) {
+	mapping, err := loadNamespaceMapping(viper.GetString(envNamespaceMappingFile))
+	if err != nil {
+		return "", err
+	}
+	return mapping.Namespace(project)
+}
+
+// loadNamespaceMapping loads the mapping from the file, the empty mapping is returned if the path is empty.
+func loadNamespaceMapping(path string) (*NamespaceMapping, error) {
+	if path == "" {
+		return &NamespaceMapping{}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load namespace mapping: %w", err)
+	}
+
+	namespaceMappingCache.Lock()
+	defer namespaceMappingCache.Unlock()
+	if namespaceMappingCache.mapping != nil && namespaceMappingCache.path == path &&
+		namespaceMappingCache.modTime.Equal(info.ModTime()) {
+		return namespaceMappingCache.mapping, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load namespace mapping: %w", err)
+	}
+	mapping := &NamespaceMapping{}
+	if err := yaml.Unmarshal(data, mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace mapping %v: %w", path, err)
+	}
+
+	namespaceMappingCache.path = path
+	namespaceMappingCache.modTime = info.ModTime()
+	namespaceMappingCache.mapping = mapping
+	return mapping, nil
+}
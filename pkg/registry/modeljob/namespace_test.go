@@ -0,0 +1,65 @@
+package modeljob_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/registry/modeljob"
+)
+
+var _ = Describe("Modeljob namespace mapping", func() {
+	It("Should map the projects to namespaces", func() {
+		mapping := &modeljob.NamespaceMapping{
+			Projects: map[string]string{"release": "models-release"},
+			Fallback: "models-{project}",
+		}
+
+		namespace, err := mapping.Namespace("release")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("models-release"))
+
+		namespace, err = mapping.Namespace("team-a")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("models-team-a"))
+
+		_, err = mapping.Namespace("team_a")
+		Expect(err).NotTo(BeNil())
+
+		namespace, err = (&modeljob.NamespaceMapping{}).Namespace("release")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("default"))
+	})
+
+	It("Should load the mapping file", func() {
+		dir, err := ioutil.TempDir("", "namespace-mapping")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "mapping.yaml")
+		Expect(ioutil.WriteFile(path, []byte("projects:\n  release: models-release\nfallback: models\n"), 0644)).To(Succeed())
+		viper.Set("MODELJOB_NAMESPACE_MAPPING_FILE", path)
+		defer viper.Set("MODELJOB_NAMESPACE_MAPPING_FILE", "")
+
+		namespace, err := modeljob.GetNamespace("release")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("models-release"))
+
+		namespace, err = modeljob.GetNamespace("team-a")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("models"))
+	})
+
+	It("Should label the extraction modeljob", func() {
+		modeljobObj := modeljob.GenerateExtractionModelJob("models-release", "harbor.io", "release", "resnet", "v1", "SavedModel", "")
+		Expect(modeljobObj.Namespace).To(Equal("models-release"))
+		Expect(modeljobObj.Labels).To(HaveKeyWithValue(modeljobsv1alpha1.ModelJobProjectLabelKey, "release"))
+		Expect(modeljobObj.Labels).To(HaveKeyWithValue(modeljobsv1alpha1.ModelJobModelLabelKey, "resnet"))
+		Expect(modeljobObj.Labels).To(HaveKeyWithValue(modeljobsv1alpha1.ModelJobVersionLabelKey, "v1"))
+	})
+})
@@ -0,0 +1,67 @@
+package modeljob
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/controllers"
+	"github.com/kleveross/klever-model-registry/pkg/registry/errors"
+)
+
+// envModelJobOperatorAddress is the env key of the address of the metrics server of modeljob-operator,
+// which renders the jobs of modeljobs.
+const envModelJobOperatorAddress = "MODELJOB_OPERATOR_ADDRESS"
+
+// RenderJob renders the job which the modeljob-operator creates for the modeljob in the namespace, nothing is
+// created. The job is rendered by modeljob-operator, so the images, resources and timeouts are resolved from
+// the configuration of modeljob-operator.
+func RenderJob(namespace string, modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, error) {
+	url := fmt.Sprintf("http://%v%v", viper.GetString(envModelJobOperatorAddress), controllers.ModelJobRenderPath)
+	job, err := renderJob(http.DefaultClient, url, namespace, modeljob)
+	if err != nil {
+		return nil, errors.RenderError(err)
+	}
+	return job, nil
+}
+
+// renderJob posts the modeljob to the render endpoint of modeljob-operator at url, the Status returned for
+// the errors is converted to the API error.
+func renderJob(httpClient *http.Client, url, namespace string, modeljob *modeljobsv1alpha1.ModelJob) (*batchv1.Job, error) {
+	modeljob = modeljob.DeepCopy()
+	modeljob.Namespace = namespace
+	if modeljob.Name == "" {
+		modeljob.Name = generateModelJobName()
+	}
+
+	body, err := json.Marshal(modeljob)
+	if err != nil {
+		return nil, err
+	}
+	response, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render job by modeljob-operator: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		status := &metav1.Status{}
+		if err := json.NewDecoder(response.Body).Decode(status); err != nil || status.Status != metav1.StatusFailure {
+			return nil, fmt.Errorf("failed to render job by modeljob-operator: %v", response.Status)
+		}
+		return nil, k8serrors.FromObject(status)
+	}
+
+	job := &batchv1.Job{}
+	if err := json.NewDecoder(response.Body).Decode(job); err != nil {
+		return nil, fmt.Errorf("failed to decode the job rendered by modeljob-operator: %v", err)
+	}
+	return job, nil
+}
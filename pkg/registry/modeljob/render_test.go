@@ -0,0 +1,79 @@
+package modeljob_test
+
+import (
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/controllers"
+	"github.com/kleveross/klever-model-registry/pkg/registry/modeljob"
+	test "github.com/kleveross/klever-model-registry/testutil"
+)
+
+var _ = Describe("Modeljob render", func() {
+	var server *httptest.Server
+	harbor := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "harbor"},
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("robot"),
+			corev1.BasicAuthPasswordKey: []byte("ORMBtest12345"),
+		},
+	}
+	newModelJob := func(format modeljobsv1alpha1.Format) *modeljobsv1alpha1.ModelJob {
+		return &modeljobsv1alpha1.ModelJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "resnet"},
+			Spec: modeljobsv1alpha1.ModelJobSpec{
+				Model: "release/resnet:v1",
+				ModelJobSource: modeljobsv1alpha1.ModelJobSource{
+					Extraction: &modeljobsv1alpha1.ExtractionSource{Format: format},
+				},
+				RegistryCredentials: &modeljobsv1alpha1.RegistryCredentials{SecretName: "harbor"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		test.InitPresetModelImage()
+		Expect(controllers.Initialization()).To(BeNil())
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+		_ = modeljobsv1alpha1.AddToScheme(scheme)
+		server = httptest.NewServer(controllers.NewRenderHandler(fake.NewFakeClientWithScheme(scheme, harbor)))
+		viper.Set("MODELJOB_OPERATOR_ADDRESS", strings.TrimPrefix(server.URL, "http://"))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		viper.Set("MODELJOB_OPERATOR_ADDRESS", "")
+	})
+
+	It("Should render the same job as modeljob-operator", func() {
+		job, err := modeljob.RenderJob("default", newModelJob(modeljobsv1alpha1.FormatSavedModel))
+		Expect(err).To(BeNil())
+
+		modeljobObj := newModelJob(modeljobsv1alpha1.FormatSavedModel)
+		modeljobObj.Namespace = "default"
+		want, err := controllers.RenderJob(modeljobObj, nil, func(name string) (*corev1.Secret, error) {
+			return harbor, nil
+		})
+		Expect(err).To(BeNil())
+		Expect(equality.Semantic.DeepEqual(job, want)).To(BeTrue())
+	})
+
+	It("Should return the error of modeljob-operator", func() {
+		_, err := modeljob.RenderJob("default", newModelJob(modeljobsv1alpha1.FormatONNX))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("image"))
+	})
+})
@@ -5,18 +5,16 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	modeljobsv1alpha1 "github.com/kleveross/klever-model-registry/pkg/apis/modeljob/v1alpha1"
+	"github.com/kleveross/klever-model-registry/pkg/controllers"
 	"github.com/kleveross/klever-model-registry/pkg/util"
 )
 
-const (
-	extractLabelKey = "modeljob/extract"
-	convertLabelKey = "modeljob/convert"
-)
-
-// GenerateExtractionModelJob will generate ModelJob by base information.
-func GenerateExtractionModelJob(domain, project, modelName, versionName, format string) *modeljobsv1alpha1.ModelJob {
+// GenerateExtractionModelJob will generate ModelJob by base information, it is labelled with
+// the project, model, version and the digest of artifact if it is known for lookup.
+func GenerateExtractionModelJob(namespace, domain, project, modelName, versionName, format, digest string) *modeljobsv1alpha1.ModelJob {
 	modeljob := modeljobsv1alpha1.ModelJob{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "kleveross.io/v1alpha1",
@@ -24,9 +22,9 @@ func GenerateExtractionModelJob(domain, project, modelName, versionName, format
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      util.RandomNameWithPrefix(fmt.Sprintf("modeljob-%v-%v-%v", project, modelName, versionName)),
-			Namespace: "default",
+			Namespace: namespace,
 			Labels: map[string]string{
-				extractLabelKey: "true",
+				modeljobsv1alpha1.ExtractLabelKey: "true",
 			},
 		},
 		Spec: modeljobsv1alpha1.ModelJobSpec{
@@ -39,6 +37,24 @@ func GenerateExtractionModelJob(domain, project, modelName, versionName, format
 		},
 	}
 
+	for key, value := range map[string]string{
+		modeljobsv1alpha1.ModelJobProjectLabelKey: project,
+		modeljobsv1alpha1.ModelJobModelLabelKey:   modelName,
+		modeljobsv1alpha1.ModelJobVersionLabelKey: versionName,
+	} {
+		// The names which are not valid label values, e.g. the too long versions, are not labelled.
+		if len(validation.IsValidLabelValue(value)) == 0 {
+			modeljob.ObjectMeta.Labels[key] = value
+		}
+	}
+
+	if digest != "" {
+		modeljob.ObjectMeta.Labels[modeljobsv1alpha1.ModelJobDigestLabelKey] = controllers.DigestLabelValue(digest)
+		modeljob.ObjectMeta.Annotations = map[string]string{
+			modeljobsv1alpha1.ModelJobDigestLabelKey: digest,
+		}
+	}
+
 	return &modeljob
 }
 
@@ -106,8 +106,14 @@ func UploadFile(ctx context.Context, tenant, user, projectName, modelName, versi
 		}
 
 		if modeljob.IsExtractModel(model.Format) {
-			modeljobObj := modeljob.GenerateExtractionModelJob(common.ORMBDomain, projectName, modelName, versionName, model.Format)
-			_, err = client.GetKubeKleverOssClient().KleverossV1alpha1().ModelJobs("default").Create(context.Background(), modeljobObj, metav1.CreateOptions{})
+			namespace, err := modeljob.GetNamespace(projectName)
+			if err != nil {
+				return errors.RenderInternalServerError(err)
+			}
+			// The ORMB client does not return the digest of the pushed artifact, so the uploaded models are not
+			// labelled with the digest and their extractions are never reused.
+			modeljobObj := modeljob.GenerateExtractionModelJob(namespace, common.ORMBDomain, projectName, modelName, versionName, model.Format, "")
+			_, err = client.GetKubeKleverOssClient().KleverossV1alpha1().ModelJobs(namespace).Create(context.Background(), modeljobObj, metav1.CreateOptions{})
 			if err != nil {
 				return errors.RenderInternalServerError(err)
 			}
@@ -20,15 +20,6 @@ const (
 	// the model from harbor by ormb pull will store in the mount point.
 	modelSharedMountName = "models-mnt"
 
-	// envTRTServingImage is the preset image for tritonserver.
-	envTRTServingImage = "TRT_SERVING_IMAGE"
-
-	// envPMMLServingImage is the preset image for pmml.
-	envPMMLServingImage = "PMML_SERVING_IMAGE"
-
-	// envMLServerLImage is the preset image for mlserver.
-	envMLServerImage = "MLSERVER_IMAGE"
-
 	// envModelInitializerImage is the preset image for model initializer.
 	envModelInitializerImage = "MODEL_INITIALIZER_IMAGE"
 
@@ -415,18 +406,7 @@ func getModelFormat(pu *seldonv1.PredictiveUnit) string {
 
 // getUserContainerImage get image by different model format.
 func getUserContainerImage(format string) string {
-	// Group1 for PMML image
-	if format == string(modeljobsv1alpha1.FormatPMML) {
-		return viper.GetString(envPMMLServingImage)
-	}
-	// Group2 for mlserver image
-	if isMLServerModel(format) {
-		return viper.GetString(envMLServerImage)
-	}
-
-	// Group3 for default TRT server image
-	return viper.GetString(envTRTServingImage)
-
+	return common.GetServingImage(format)
 }
 
 // getModelMountPath will generate model mount path in container,
@@ -545,8 +525,5 @@ func composeSchedulerName(seldonPodSpec *seldonv1.SeldonPodSpec) {
 }
 
 func isMLServerModel(format string) bool {
-	if format == string(modeljobsv1alpha1.FormatSKLearn) || format == string(modeljobsv1alpha1.FormatXGBoost) || format == string(modeljobsv1alpha1.FormatMLlib) {
-		return true
-	}
-	return false
+	return common.IsMLServerModel(format)
 }
@@ -0,0 +1,290 @@
+import argparse
+import json
+import logging
+import os
+import threading
+import time
+import urllib.error
+import urllib.request
+from concurrent.futures import ThreadPoolExecutor
+
+logging.basicConfig(
+    format='[%(levelname).1s%(asctime)s\t%(name)s] %(message)s',
+    datefmt='%m%d %I:%M:%S',
+    level=logging.INFO)
+
+# The ports of the serving runtimes, see scripts/serving.
+TRITON_PORT = 8000
+MLSERVER_PORT = 8080
+OPENSCORING_PORT = 8000
+
+MLSERVER_FORMATS = ('SKLearn', 'XGBoost', 'MLlib')
+
+# The datatypes of the v2 inference protocol of the numpy dtypes in ormbfile.yaml.
+V2_DATATYPES = {
+    'bool': 'BOOL',
+    'uint8': 'UINT8',
+    'uint16': 'UINT16',
+    'uint32': 'UINT32',
+    'uint64': 'UINT64',
+    'int8': 'INT8',
+    'int16': 'INT16',
+    'int32': 'INT32',
+    'int64': 'INT64',
+    'float16': 'FP16',
+    'float32': 'FP32',
+    'float64': 'FP64',
+    'string': 'BYTES',
+}
+
+READY_TIMEOUT_SECONDS = 600
+
+
+def write_result(path, result):
+    # The result of benchmark is reported in the result of modeljob by benchmark.sh.
+    with open(path, 'w') as f:
+        json.dump({'benchmark': result}, f)
+
+
+def format_duration(seconds):
+    """Formats the seconds as the duration of Go, e.g. 12.345ms."""
+    return '%.3fms' % (seconds * 1000)
+
+
+def percentile(values, p):
+    values = sorted(values)
+    if not values:
+        return 0.0
+    index = min(len(values) - 1, max(0, int(round(p / 100.0 * len(values) + 0.5)) - 1))
+    return values[index]
+
+
+def parse_ints(value, default):
+    if not value:
+        return default
+    return [int(v) for v in value.split(',')]
+
+
+def post(url, body, timeout=60):
+    req = urllib.request.Request(url,
+                                 data=json.dumps(body).encode(),
+                                 method='POST',
+                                 headers={'Content-Type': 'application/json'})
+    with urllib.request.urlopen(req, timeout=timeout) as resp:
+        return resp.read()
+
+
+def get_status(url):
+    try:
+        with urllib.request.urlopen(url, timeout=5) as resp:
+            return resp.status
+    except urllib.error.HTTPError as e:
+        return e.code
+    except Exception:
+        return 0
+
+
+class Runtime(object):
+    """Runtime sends the synthetic requests to the serving runtime of the format."""
+    def __init__(self, format, name, inputs):
+        self.format = format
+        self.name = name
+        self.inputs = inputs
+        if format == 'PMML':
+            self.base = 'http://localhost:%d/openscoring/model/%s' % (
+                OPENSCORING_PORT, name)
+            self.ready_url = self.base
+            self.infer_url = self.base
+        else:
+            port = MLSERVER_PORT if format in MLSERVER_FORMATS else TRITON_PORT
+            self.base = 'http://localhost:%d/v2/models/%s' % (port, name)
+            self.ready_url = self.base + '/ready'
+            self.infer_url = self.base + '/infer'
+
+    def wait_ready(self, pid):
+        deadline = time.time() + READY_TIMEOUT_SECONDS
+        while time.time() < deadline:
+            if not os.path.exists('/proc/%d' % pid):
+                raise RuntimeError('the serving runtime exited before it is ready')
+            if get_status(self.ready_url) == 200:
+                return
+            time.sleep(2)
+        raise RuntimeError('the model is not ready in %d seconds' %
+                           READY_TIMEOUT_SECONDS)
+
+    def request(self, batch_size):
+        """Generates the request, the first dynamic dim is the batch size and the others are 1."""
+        if self.format == 'PMML':
+            return {
+                'arguments': {input['name']: 0
+                              for input in self.inputs}
+            }
+
+        if not self.inputs:
+            raise ValueError('no inputs in the signature of ormbfile.yaml')
+        tensors = []
+        for input in self.inputs:
+            shape = []
+            batched = False
+            for dim in input.get('size', []):
+                if dim > 0:
+                    shape.append(dim)
+                elif not batched:
+                    shape.append(batch_size)
+                    batched = True
+                else:
+                    shape.append(1)
+            dtype = input.get('dType', 'float32')
+            count = 1
+            for dim in shape:
+                count *= dim
+            value = False if dtype == 'bool' else ('' if dtype == 'string' else 0)
+            tensors.append({
+                'name': input['name'],
+                'shape': shape,
+                'datatype': V2_DATATYPES.get(dtype, 'FP32'),
+                'data': [value] * count,
+            })
+        return {'inputs': tensors}
+
+    def infer(self, body):
+        post(self.infer_url, body)
+
+
+class MemorySampler(threading.Thread):
+    """MemorySampler samples the resident memory of the process tree of serving runtime."""
+    def __init__(self, pid, interval=0.2):
+        super(MemorySampler, self).__init__(daemon=True)
+        self.pid = pid
+        self.interval = interval
+        self.peak = 0
+        self.stopped = threading.Event()
+
+    def run(self):
+        while not self.stopped.is_set():
+            self.peak = max(self.peak, self.rss())
+            self.stopped.wait(self.interval)
+
+    def stop(self):
+        self.stopped.set()
+        self.join()
+        self.peak = max(self.peak, self.rss())
+
+    def rss(self):
+        children = {}
+        for entry in os.listdir('/proc'):
+            if not entry.isdigit():
+                continue
+            try:
+                with open('/proc/%s/stat' % entry) as f:
+                    # The command in the stat may have spaces, the ppid is the second field after it.
+                    ppid = int(f.read().rsplit(')', 1)[1].split()[1])
+            except (IOError, IndexError, ValueError):
+                continue
+            children.setdefault(ppid, []).append(int(entry))
+
+        total = 0
+        pids = [self.pid]
+        while pids:
+            pid = pids.pop()
+            pids.extend(children.get(pid, []))
+            try:
+                with open('/proc/%d/status' % pid) as f:
+                    for line in f:
+                        if line.startswith('VmRSS:'):
+                            total += int(line.split()[1]) * 1024
+            except IOError:
+                continue
+        return total
+
+
+def run_case(runtime, batch_size, concurrency, requests, warmup_requests):
+    body = runtime.request(batch_size)
+    for _ in range(warmup_requests):
+        try:
+            runtime.infer(body)
+        except Exception as e:
+            logging.warning('warmup request failed: %s' % e)
+
+    latencies = []
+    errors = [0]
+    lock = threading.Lock()
+
+    def send(_):
+        start = time.time()
+        try:
+            runtime.infer(body)
+        except Exception as e:
+            with lock:
+                errors[0] += 1
+            logging.warning('request failed: %s' % e)
+            return
+        with lock:
+            latencies.append(time.time() - start)
+
+    start = time.time()
+    with ThreadPoolExecutor(max_workers=concurrency) as executor:
+        list(executor.map(send, range(requests)))
+    elapsed = time.time() - start
+
+    return {
+        'batchSize': batch_size,
+        'concurrency': concurrency,
+        'latencyP50': format_duration(percentile(latencies, 50)),
+        'latencyP95': format_duration(percentile(latencies, 95)),
+        'latencyP99': format_duration(percentile(latencies, 99)),
+        # The throughput is the samples per second.
+        'throughput': '%.2f' % (len(latencies) * batch_size / elapsed if elapsed > 0 else 0),
+        'errors': errors[0],
+    }
+
+
+def benchmark(args):
+    batch_sizes = parse_ints(os.environ.get('BENCHMARK_BATCH_SIZES'), [1])
+    concurrency = parse_ints(os.environ.get('BENCHMARK_CONCURRENCY'), [1])
+    requests = int(os.environ.get('BENCHMARK_REQUESTS', '100'))
+    warmup_requests = int(os.environ.get('BENCHMARK_WARMUP_REQUESTS', '10'))
+
+    runtime = Runtime(args.format, args.name, json.loads(args.inputs or '[]'))
+    runtime.wait_ready(args.pid)
+    logging.info('the model %s is ready' % args.name)
+
+    sampler = MemorySampler(args.pid)
+    sampler.start()
+    cases = []
+    try:
+        for batch_size in batch_sizes:
+            for c in concurrency:
+                case = run_case(runtime, batch_size, c, requests,
+                                warmup_requests)
+                logging.info('result of case: ' + json.dumps(case))
+                cases.append(case)
+    finally:
+        sampler.stop()
+
+    if all(case['errors'] == requests for case in cases):
+        raise RuntimeError('all requests of benchmark failed')
+    return {'cases': cases, 'peakMemoryBytes': sampler.peak}
+
+
+if __name__ == '__main__':
+
+    parser = argparse.ArgumentParser(
+        description='Benchmark the model in the serving runtime.')
+    parser.add_argument('--format', help='format of model', required=True)
+    parser.add_argument('--name', help='name of model', required=True)
+    parser.add_argument('--pid',
+                        help='pid of serving runtime',
+                        type=int,
+                        required=True)
+    parser.add_argument('--inputs', help='inputs of the signature as JSON')
+    parser.add_argument('-r',
+                        metavar='FILE',
+                        help='path to write the result of benchmark',
+                        dest='result',
+                        default='/tmp/modeljob-task-result.json')
+
+    args = parser.parse_args()
+    result = benchmark(args)
+    logging.info('result of benchmark: ' + json.dumps(result))
+    write_result(args.result, result)
@@ -0,0 +1,90 @@
+#!/bin/bash
+# benchmark.sh benchmarks the model in the serving runtime image, it is executed by the entrypoint of the image
+# when MODELJOB_TASK is benchmark, and the entrypoint is started again in background to serve the model.
+
+ormb_run_task_err=10003
+ormb_push_model_err=10005
+
+entrypoint=$1
+src_tag=$SOURCE_MODEL_TAG
+input_dir=$SOURCE_MODEL_PATH
+format=$FORMAT
+benchmark_dir=$(dirname $0)
+result_path=${MODELJOB_RESULT_PATH:-/dev/termination-log}
+task_result_path=/tmp/modeljob-task-result.json
+task_log_path=/tmp/modeljob-task.log
+server_log_path=/tmp/modeljob-server.log
+annotate_log_path=/tmp/modeljob-annotate.log
+
+echo "#####################################################"
+echo "model source tag: $src_tag"
+echo "model input dir: $input_dir"
+echo "model format: $format"
+echo "task: benchmark"
+echo "entrypoint: $entrypoint"
+echo "#####################################################"
+
+# writeResult writes the json of result to the termination message, the reconciler of modeljob parses it
+# into status.result.
+function writeResult() {
+    RESULT_ERROR="$1" RESULT_TASK_PATH="$task_result_path" RESULT_TASK_SECONDS="$task_seconds" \
+    python3 - > $result_path <<'EOF'
+import json
+import os
+
+result = {}
+if os.path.exists(os.environ['RESULT_TASK_PATH']):
+    with open(os.environ['RESULT_TASK_PATH']) as f:
+        result.update(json.load(f))
+if os.environ['RESULT_ERROR']:
+    result['error'] = os.environ['RESULT_ERROR'][-1024:]
+if os.environ['RESULT_TASK_SECONDS']:
+    result['taskDuration'] = os.environ['RESULT_TASK_SECONDS'] + 's'
+print(json.dumps(result))
+EOF
+}
+
+function checkOrExit() {
+    if [ $1 != 0 ];then
+        echo "exit code: $2"
+        writeResult "$3"
+        exit $2
+    fi
+}
+
+# lastLines returns the last lines of the log as the detailed error.
+function lastLines() {
+    if [ -f $1 ];then
+        tail -n 5 $1
+    fi
+}
+
+# The serving runtime reads the signature of model from the env instead of ormbfile.yaml.
+export INPUTS=$(python3 -c "import json, yaml; print(json.dumps((yaml.safe_load(open('$input_dir/ormbfile.yaml')).get('signature') or {}).get('inputs') or []))")
+checkOrExit $? $ormb_run_task_err "failed to read the signature of ormbfile.yaml"
+export OUTPUTS=$(python3 -c "import json, yaml; print(json.dumps((yaml.safe_load(open('$input_dir/ormbfile.yaml')).get('signature') or {}).get('outputs') or []))")
+signature_inputs=$INPUTS
+
+task_start=$(date +%s)
+MODELJOB_TASK=serve $entrypoint > $server_log_path 2>&1 &
+server_pid=$!
+
+python3 $benchmark_dir/benchmark.py --format "$format" --name "$SERVING_NAME" --pid $server_pid \
+    --inputs "$signature_inputs" -r $task_result_path 2>&1 | tee $task_log_path
+status=${PIPESTATUS[0]}
+kill $server_pid 2>/dev/null
+if [ $status != 0 ];then
+    cat $server_log_path
+fi
+checkOrExit $status $ormb_run_task_err "$(lastLines $task_log_path)"
+task_seconds=$(( $(date +%s) - task_start ))
+
+# the benchmark does not push model, its result is recorded in the annotation of artifact.
+benchmark=$(python3 -c "import json; print(json.dumps(json.load(open('$task_result_path'))['benchmark'], separators=(',', ':')))")
+checkOrExit $? $ormb_run_task_err "no benchmark result"
+
+python3 $benchmark_dir/annotate.py --ref $src_tag --key kleveross.io/benchmark --value "$benchmark" \
+    -r $task_result_path -s benchmark 2>&1 | tee $annotate_log_path
+checkOrExit ${PIPESTATUS[0]} $ormb_push_model_err "$(lastLines $annotate_log_path)"
+
+writeResult ""
@@ -0,0 +1,115 @@
+import argparse
+import base64
+import json
+import logging
+import os
+import re
+import ssl
+import urllib.error
+import urllib.parse
+import urllib.request
+
+logging.basicConfig(
+    format='[%(levelname).1s%(asctime)s\t%(name)s] %(message)s',
+    datefmt='%m%d %I:%M:%S',
+    level=logging.INFO)
+
+# ormb pushes the models as OCI manifests, Harbor shows the annotations of
+# manifest as the annotations of artifact.
+MANIFEST_MEDIA_TYPE = 'application/vnd.oci.image.manifest.v1+json'
+
+
+class Registry(object):
+    """Registry is the client of docker registry v2 API, the token of the
+    Bearer challenge of Harbor is requested by basic auth."""
+    def __init__(self, domain, username, password):
+        # The models are pushed by ormb with --plain-http.
+        self.base = 'http://%s' % domain
+        self.basic = 'Basic ' + base64.b64encode(
+            ('%s:%s' % (username, password)).encode()).decode()
+        self.context = ssl._create_unverified_context()
+
+    def request(self, method, path, data=None, headers=None):
+        headers = dict(headers or {}, Authorization=self.basic)
+        try:
+            return self._do(method, self.base + path, data, headers)
+        except urllib.error.HTTPError as e:
+            challenge = e.headers.get('WWW-Authenticate', '')
+            if e.code != 401 or not challenge.startswith('Bearer '):
+                raise
+        headers['Authorization'] = 'Bearer ' + self._token(challenge)
+        return self._do(method, self.base + path, data, headers)
+
+    def _token(self, challenge):
+        params = dict(re.findall(r'(\w+)="([^"]*)"', challenge))
+        realm = params.pop('realm')
+        body = json.loads(
+            self._do('GET', realm + '?' + urllib.parse.urlencode(params),
+                     None, {'Authorization': self.basic}))
+        return body.get('token') or body.get('access_token')
+
+    def _do(self, method, url, data, headers):
+        req = urllib.request.Request(url,
+                                     data=data,
+                                     method=method,
+                                     headers=headers)
+        with urllib.request.urlopen(req, context=self.context) as resp:
+            return resp.read()
+
+
+def parse_ref(ref):
+    """Parses domain/project/model:version."""
+    domain, name = ref.split('/', 1)
+    repository, tag = name.rsplit(':', 1)
+    return domain, repository, tag
+
+
+def annotate(registry, repository, tag, key, value):
+    path = '/v2/%s/manifests/%s' % (repository, tag)
+    manifest = json.loads(
+        registry.request('GET', path, headers={'Accept': MANIFEST_MEDIA_TYPE}))
+
+    annotations = manifest.setdefault('annotations', {})
+    if annotations.get(key) == value:
+        return
+    annotations[key] = value
+    manifest.setdefault('mediaType', MANIFEST_MEDIA_TYPE)
+    registry.request('PUT',
+                     path,
+                     data=json.dumps(manifest).encode(),
+                     headers={'Content-Type': manifest['mediaType']})
+
+
+def mark_annotated(path, section='validation'):
+    with open(path, 'r') as f:
+        result = json.load(f)
+    result[section]['annotated'] = True
+    with open(path, 'w') as f:
+        json.dump(result, f)
+
+
+if __name__ == '__main__':
+
+    parser = argparse.ArgumentParser(
+        description='Annotate the model in registry.')
+    parser.add_argument('--ref', help='reference of model', required=True)
+    parser.add_argument('--key', help='key of annotation', required=True)
+    parser.add_argument('--value', help='value of annotation', required=True)
+    parser.add_argument('-r',
+                        metavar='FILE',
+                        help='path of the result of task to mark annotated',
+                        dest='result')
+    parser.add_argument('-s',
+                        metavar='SECTION',
+                        help='section of the result of task, e.g. validation or benchmark',
+                        dest='section',
+                        default='validation')
+
+    args = parser.parse_args()
+    domain, repository, tag = parse_ref(args.ref)
+    registry = Registry(domain, os.environ.get('SERVER_ORMB_USERNAME', ''),
+                        os.environ.get('SERVER_ORMB_PASSWORD', ''))
+    annotate(registry, repository, tag, args.key, args.value)
+    logging.info('annotated %s with %s=%s' % (args.ref, args.key, args.value))
+    if args.result:
+        mark_annotated(args.result, args.section)
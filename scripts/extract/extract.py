@@ -12,6 +12,12 @@ logging.basicConfig(
     level=logging.INFO)
 
 
+def write_result(path, result):
+    # The signature or error is reported in the result of modeljob by run.sh.
+    with open(path, 'w') as f:
+        json.dump(result, f)
+
+
 def update_yaml(dir, res_dict):
     with open(os.path.join(dir, 'ormbfile.yaml'), 'r') as f:
         data = yaml.safe_load(f)
@@ -31,12 +37,18 @@ def update_yaml(dir, res_dict):
             data['signature']['outputs'] = res_dict['Outputs']
         logging.info('save ormbfile.yaml: ' + json.dumps(data))
         yaml.safe_dump(data, f)
+        return data['signature']
 
 
 if __name__ == '__main__':
 
     parser = argparse.ArgumentParser(description='Process some the path.')
     parser.add_argument('-d', metavar='DIR', help='path to model', dest='dir')
+    parser.add_argument('-r',
+                        metavar='FILE',
+                        help='path to write the signature or error of extraction',
+                        dest='result',
+                        default='/tmp/modeljob-task-result.json')
 
     args = parser.parse_args()
     extractor = Extractor(path=args.dir)
@@ -44,6 +56,8 @@ if __name__ == '__main__':
     try:
         res_dict = extractor.extract()
         logging.info('origin data from extractor: ' + json.dumps(res_dict))
-        update_yaml(args.dir, res_dict)
+        signature = update_yaml(args.dir, res_dict)
+        write_result(args.result, {'signature': signature})
     except Exception as e:
         logging.error(e)
+        write_result(args.result, {'error': str(e)})
@@ -59,8 +59,8 @@ class TensorflowExtractor(BaseExtrctor):
         Returns:
           String representation of all tag-sets in the SavedModel.
         """
-        sess = tf.Session()
-        MetaGraphDef = saved_model.load(sess, [saved_model.SERVING],
+        self.sess = tf.Session()
+        MetaGraphDef = saved_model.load(self.sess, [saved_model.SERVING],
                                         os.path.join(self.dir, 'model'))
         sig = None
         if DEFAULT_SERVING_SIGNATURE_DEF_KEY not in MetaGraphDef.signature_def:
@@ -0,0 +1,157 @@
+import os
+import argparse
+import collections
+import json
+import yaml
+import logging
+
+import numpy as np
+
+from validator import Validator
+
+logging.basicConfig(
+    format='[%(levelname).1s%(asctime)s\t%(name)s] %(message)s',
+    datefmt='%m%d %I:%M:%S',
+    level=logging.INFO)
+
+
+def write_result(path, result):
+    # The result of validation is reported in the result of modeljob by run.sh.
+    with open(path, 'w') as f:
+        json.dump({'validation': result}, f)
+
+
+def load_signature(dir):
+    with open(os.path.join(dir, 'ormbfile.yaml'), 'r') as f:
+        data = yaml.safe_load(f)
+    return data.get('signature') or {}
+
+
+def load_json(path):
+    with open(path, 'r') as f:
+        return json.load(f)
+
+
+def generate_inputs(signature):
+    """Generates the random inputs by the signature, the dynamic dims are 1."""
+    if not signature.get('inputs'):
+        raise ValueError(
+            'no inputs in the signature of ormbfile.yaml, the sample inputs must be set')
+
+    random = np.random.RandomState(0)
+    inputs = collections.OrderedDict()
+    for input in signature['inputs']:
+        shape = [dim if dim > 0 else 1 for dim in input.get('size', [])]
+        dtype = np.dtype(input.get('dType', 'float32'))
+        if dtype.kind == 'f':
+            value = random.rand(*shape)
+        elif dtype.kind in 'iu':
+            value = random.randint(0, 2, size=shape)
+        elif dtype.kind == 'b':
+            value = np.zeros(shape)
+        else:
+            raise ValueError('can not generate the input %s of dtype %s' %
+                             (input['name'], dtype))
+        inputs[input['name']] = value.astype(dtype)
+    return inputs
+
+
+def read_inputs(path, signature):
+    """Reads the sample inputs in the order and dtype of signature."""
+    samples = load_json(path)
+    dtypes = {
+        input['name']: input.get('dType')
+        for input in signature.get('inputs', [])
+    }
+    names = [name for name in dtypes if name in samples]
+    names += [name for name in samples if name not in dtypes]
+
+    inputs = collections.OrderedDict()
+    for name in names:
+        inputs[name] = np.array(samples[name], dtype=dtypes.get(name))
+    return inputs
+
+
+def compare_outputs(outputs, expected, tolerance):
+    """Returns the max absolute error and the message if the outputs are not within the tolerance."""
+    max_error = 0.0
+    for name, value in expected.items():
+        if name not in outputs:
+            return max_error, 'output %s is not found' % name
+        actual = np.asarray(outputs[name], dtype=np.float64)
+        value = np.asarray(value, dtype=np.float64)
+        if actual.shape != value.shape:
+            return max_error, 'output %s has shape %s, expected %s' % (
+                name, list(actual.shape), list(value.shape))
+        if actual.size != 0:
+            max_error = max(max_error, float(np.max(np.abs(actual - value))))
+    if max_error > tolerance:
+        return max_error, 'max absolute error %g exceeds tolerance %g' % (
+            max_error, tolerance)
+    return max_error, ''
+
+
+def check_finite(outputs):
+    """Returns the message if any numeric output is not finite."""
+    for name, value in outputs.items():
+        value = np.asarray(value)
+        if value.dtype.kind in 'fc' and not np.all(np.isfinite(value)):
+            return 'output %s is not finite' % name
+    return ''
+
+
+def validate(args):
+    signature = load_signature(args.dir)
+    sample_inputs = os.environ.get('VALIDATION_SAMPLE_INPUTS')
+    expected_outputs = os.environ.get('VALIDATION_EXPECTED_OUTPUTS')
+    tolerance = float(os.environ.get('VALIDATION_TOLERANCE', '1e-5'))
+
+    result = {'passed': False, 'inputsGenerated': not sample_inputs}
+    if sample_inputs:
+        inputs = read_inputs(sample_inputs, signature)
+    else:
+        inputs = generate_inputs(signature)
+
+    validator = Validator(path=args.dir)
+    validator.outputs = signature.get('outputs', [])
+    validator.load()
+    outputs = validator.predict(inputs)
+    logging.info('outputs of model: ' +
+                 json.dumps({name: list(np.shape(value))
+                             for name, value in outputs.items()}))
+
+    if expected_outputs:
+        max_error, message = compare_outputs(outputs,
+                                             load_json(expected_outputs),
+                                             tolerance)
+        result['maxAbsoluteError'] = '%g' % max_error
+    else:
+        message = check_finite(outputs)
+
+    result['passed'] = not message
+    if message:
+        result['message'] = message
+    return result
+
+
+if __name__ == '__main__':
+
+    parser = argparse.ArgumentParser(
+        description='Run the model with sample inputs.')
+    parser.add_argument('-d', metavar='DIR', help='path to model', dest='dir')
+    parser.add_argument('-r',
+                        metavar='FILE',
+                        help='path to write the result of validation',
+                        dest='result',
+                        default='/tmp/modeljob-task-result.json')
+
+    args = parser.parse_args()
+
+    # The model which can not be loaded or run fails the validation.
+    try:
+        result = validate(args)
+    except Exception as e:
+        logging.error(e)
+        result = {'passed': False, 'message': str(e)[-1024:]}
+    logging.info('result of validation: ' + json.dumps(result))
+    write_result(args.result, result)
@@ -0,0 +1,14 @@
+import os
+module = os.environ.get('EXTRACTOR', 'NULL')
+
+if module == 'onnx':
+    from .validate_onnx import OnnxValidator as Validator
+elif module == 'h5':
+    from .validate_keras import KerasValidator as Validator
+elif module == 'savedmodel':
+    from .validate_savedmodel import TensorflowValidator as Validator
+elif module == 'torchscript':
+    from .validate_torchscript import TorchscriptValidator as Validator
+else:
+    raise ImportError(
+        'module must be in one of [onnx, h5, savedmodel, torchscript]')
@@ -0,0 +1,22 @@
+from tensorflow import keras
+
+from extractor.extract_keras import KerasExtractor, EXTENSION
+
+
+class KerasValidator(KerasExtractor):
+    def load(self):
+        # The session of keras is kept open for the inference, which is
+        # closed by _load_model of the extractor.
+        path = self._find_with_extension(EXTENSION)
+        try:
+            self.models = keras.models.load_model(path)
+        except Exception as e:
+            raise IOError('Cannot read file %s: %s.' % (path, str(e)))
+
+    def predict(self, inputs):
+        input_names = [t.name.split(':')[0] for t in self.models.inputs]
+        output_names = [t.name.split(':')[0] for t in self.models.outputs]
+        outputs = self.models.predict([inputs[name] for name in input_names])
+        if not isinstance(outputs, list):
+            outputs = [outputs]
+        return dict(zip(output_names, outputs))
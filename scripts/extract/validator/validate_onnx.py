@@ -0,0 +1,11 @@
+from extractor.extract_onnx import OnnxExtractor
+
+
+class OnnxValidator(OnnxExtractor):
+    def load(self):
+        self._load_model()
+
+    def predict(self, inputs):
+        names = [output.name for output in self.sess.get_outputs()]
+        outputs = self.sess.run(names, inputs)
+        return dict(zip(names, outputs))
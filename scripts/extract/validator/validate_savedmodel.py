@@ -0,0 +1,16 @@
+from extractor.extract_savedmodel import TensorflowExtractor
+
+
+class TensorflowValidator(TensorflowExtractor):
+    def load(self):
+        self._load_model()
+
+    def predict(self, inputs):
+        feed_dict = {
+            self.signature.inputs[name].name: value
+            for name, value in inputs.items()
+        }
+        names = list(self.signature.outputs.keys())
+        fetches = [self.signature.outputs[name].name for name in names]
+        outputs = self.sess.run(fetches, feed_dict=feed_dict)
+        return dict(zip(names, outputs))
@@ -0,0 +1,22 @@
+import torch
+
+from extractor.extract_torchscript import TorchscriptExtractor
+
+
+class TorchscriptValidator(TorchscriptExtractor):
+    def load(self):
+        self._load_model()
+        self.model.eval()
+
+    def predict(self, inputs):
+        # TorchScript models have no names of tensors, the inputs are passed in
+        # the order of signature, and the outputs are named by the signature too.
+        with torch.no_grad():
+            outputs = self.model(
+                *[torch.from_numpy(value) for value in inputs.values()])
+        if isinstance(outputs, torch.Tensor):
+            outputs = [outputs]
+        names = [output['name'] for output in self.outputs] or [
+            'output_%d' % i for i in range(len(outputs))
+        ]
+        return dict(zip(names, [output.numpy() for output in outputs]))
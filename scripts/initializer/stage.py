@@ -0,0 +1,200 @@
+import argparse
+import hashlib
+import logging
+import os
+import shutil
+import tarfile
+import urllib.request
+import zipfile
+
+import yaml
+
+logging.basicConfig(
+    format='[%(levelname).1s%(asctime)s\t%(name)s] %(message)s',
+    datefmt='%m%d %I:%M:%S',
+    level=logging.INFO)
+
+# The model files are staged in the model directory of the layout of ormb.
+MODEL_DIR = 'model'
+ORMBFILE = 'ormbfile.yaml'
+ARCHIVE_SUFFIXES = ('.tar', '.tar.gz', '.tgz', '.zip')
+
+
+def is_archive(path):
+    return path.lower().endswith(ARCHIVE_SUFFIXES)
+
+
+def extract_archive(path, dir):
+    """Extracts the archive to the directory, the members out of it are rejected."""
+    root = os.path.realpath(dir)
+    if path.lower().endswith('.zip'):
+        with zipfile.ZipFile(path) as f:
+            names = f.namelist()
+            check_members(root, names)
+            f.extractall(dir)
+    else:
+        with tarfile.open(path) as f:
+            members = [m for m in f.getmembers() if m.isfile() or m.isdir()]
+            check_members(root, [m.name for m in members])
+            f.extractall(dir, members=members)
+    os.remove(path)
+
+
+def check_members(root, names):
+    for name in names:
+        target = os.path.realpath(os.path.join(root, name))
+        if target != root and not target.startswith(root + os.sep):
+            raise ValueError('archive member %s is out of the directory' %
+                             name)
+
+
+def download_url(url, path):
+    logging.info('downloading %s' % url)
+    with urllib.request.urlopen(url, timeout=60) as resp, open(path,
+                                                              'wb') as f:
+        shutil.copyfileobj(resp, f, 1024 * 1024)
+
+
+def verify_checksum(path, checksum):
+    algorithm, expected = checksum.split(':', 1)
+    h = hashlib.new(algorithm)
+    with open(path, 'rb') as f:
+        for chunk in iter(lambda: f.read(1024 * 1024), b''):
+            h.update(chunk)
+    if h.hexdigest() != expected:
+        raise ValueError('checksum mismatch: expected %s, got %s:%s' %
+                         (checksum, algorithm, h.hexdigest()))
+
+
+def fetch_http(args, staging):
+    name = os.path.basename(args.url.split('?', 1)[0].rstrip('/')) or 'model'
+    path = os.path.join(staging, name)
+    download_url(args.url, path)
+    verify_checksum(path, args.checksum)
+    if is_archive(path):
+        extract_archive(path, staging)
+
+
+def fetch_s3(args, staging):
+    import boto3
+    from botocore import UNSIGNED
+    from botocore.config import Config
+
+    config = {}
+    if not os.environ.get('AWS_ACCESS_KEY_ID'):
+        config['signature_version'] = UNSIGNED
+    if args.endpoint:
+        # The S3-compatible services, e.g. MinIO, are addressed by path.
+        config['s3'] = {'addressing_style': 'path'}
+    client = boto3.client('s3',
+                          endpoint_url=args.endpoint or None,
+                          region_name=args.region or None,
+                          config=Config(**config))
+
+    if not args.key.endswith('/'):
+        path = os.path.join(staging, os.path.basename(args.key))
+        logging.info('downloading s3://%s/%s' % (args.bucket, args.key))
+        client.download_file(args.bucket, args.key, path)
+        if is_archive(path):
+            extract_archive(path, staging)
+        return
+
+    count = 0
+    paginator = client.get_paginator('list_objects_v2')
+    for page in paginator.paginate(Bucket=args.bucket, Prefix=args.key):
+        for obj in page.get('Contents', []):
+            relative = obj['Key'][len(args.key):]
+            if not relative or relative.endswith('/'):
+                continue
+            path = os.path.join(staging, relative)
+            check_members(os.path.realpath(staging), [relative])
+            os.makedirs(os.path.dirname(path), exist_ok=True)
+            logging.info('downloading s3://%s/%s' % (args.bucket, obj['Key']))
+            client.download_file(args.bucket, obj['Key'], path)
+            count += 1
+    if count == 0:
+        raise ValueError('no objects under s3://%s/%s' %
+                         (args.bucket, args.key))
+
+
+def fetch_pvc(args, staging):
+    if os.path.isdir(args.path):
+        for name in os.listdir(args.path):
+            src = os.path.join(args.path, name)
+            if os.path.isdir(src):
+                shutil.copytree(src, os.path.join(staging, name))
+            else:
+                shutil.copy2(src, staging)
+    elif os.path.isfile(args.path):
+        path = shutil.copy2(args.path, staging)
+        if is_archive(path):
+            extract_archive(path, staging)
+    else:
+        raise ValueError('%s is not found in the PVC' % args.path)
+
+
+def layout(staging, dest, format):
+    """Moves the staged files to the destination in the layout of ormb."""
+    names = os.listdir(staging)
+    # The archive of a directory is extracted to the directory.
+    if len(names) == 1 and os.path.isdir(os.path.join(staging, names[0])):
+        staging = os.path.join(staging, names[0])
+        names = os.listdir(staging)
+    if not names:
+        raise ValueError('no files in the model source')
+
+    if ORMBFILE in names:
+        for name in names:
+            shutil.move(os.path.join(staging, name), os.path.join(dest, name))
+        return
+
+    model_dir = os.path.join(dest, MODEL_DIR)
+    os.makedirs(model_dir, exist_ok=True)
+    for name in names:
+        shutil.move(os.path.join(staging, name), os.path.join(model_dir, name))
+    with open(os.path.join(dest, ORMBFILE), 'w') as f:
+        yaml.safe_dump({'format': format}, f)
+
+
+if __name__ == '__main__':
+
+    parser = argparse.ArgumentParser(
+        description='Stage the model source in the layout of ormb.')
+    parser.add_argument('--dest', help='path to stage model', required=True)
+    parser.add_argument('--format', help='format of model', required=True)
+    sources = parser.add_subparsers(dest='source')
+    sources.required = True
+
+    s3 = sources.add_parser('s3', help='S3-compatible bucket')
+    s3.add_argument('--endpoint', help='URL of the service')
+    s3.add_argument('--region', help='region of bucket')
+    s3.add_argument('--bucket', help='name of bucket', required=True)
+    s3.add_argument('--key', help='key or prefix of objects', required=True)
+    s3.set_defaults(fetch=fetch_s3)
+
+    http = sources.add_parser('http', help='HTTP(S) URL')
+    http.add_argument('--url', help='URL of file', required=True)
+    http.add_argument('--checksum',
+                      help='checksum of file, e.g. sha256:<hex>',
+                      required=True)
+    http.set_defaults(fetch=fetch_http)
+
+    pvc = sources.add_parser('pvc', help='path in the mounted PVC')
+    pvc.add_argument('--path', help='path of file or directory', required=True)
+    pvc.set_defaults(fetch=fetch_pvc)
+
+    args = parser.parse_args()
+    # The files of the previous attempt are removed, e.g. in the PVC workspace.
+    os.makedirs(args.dest, exist_ok=True)
+    for name in os.listdir(args.dest):
+        path = os.path.join(args.dest, name)
+        if os.path.isdir(path) and not os.path.islink(path):
+            shutil.rmtree(path)
+        else:
+            os.remove(path)
+    staging = os.path.join(args.dest, '.staging')
+    os.makedirs(staging)
+    args.fetch(args, staging)
+    layout(staging, args.dest, args.format)
+    shutil.rmtree(os.path.join(args.dest, '.staging'), ignore_errors=True)
+    logging.info('staged the model to %s' % args.dest)
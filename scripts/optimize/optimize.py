@@ -0,0 +1,34 @@
+import os
+import argparse
+import json
+import logging
+
+from optimizer import Optimizer
+
+logging.basicConfig(
+    format='[%(levelname).1s%(asctime)s\t%(name)s] %(message)s',
+    datefmt='%m%d %I:%M:%S',
+    level=logging.INFO)
+
+if __name__ == "__main__":
+
+    parser = argparse.ArgumentParser(description='Need model path.')
+    parser.add_argument(
+        '--input_dir',
+        help='path to the directory of model needs to be optimized',
+        dest='input_dir')
+    parser.add_argument('--output_dir',
+                        help='path to the directory of optimized model',
+                        dest='output_dir')
+    parser.add_argument('-r',
+                        metavar='FILE',
+                        help='path to write the result of optimization',
+                        dest='result',
+                        default='/tmp/modeljob-task-result.json')
+    args = parser.parse_args()
+
+    optimizer = Optimizer(args.input_dir, args.output_dir)
+    result = optimizer.optimize()
+    logging.info('result of optimization: ' + json.dumps(result))
+    with open(args.result, 'w') as f:
+        json.dump({'optimization': result}, f)
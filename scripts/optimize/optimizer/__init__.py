@@ -0,0 +1,9 @@
+import os
+format = os.environ.get('FORMAT', 'NULL')
+
+if format == 'ONNX':
+    from .optimize_onnx import OnnxOptimizer as Optimizer
+elif format == 'SavedModel':
+    from .optimize_savedmodel import TensorflowOptimizer as Optimizer
+else:
+    raise ImportError('FORMAT must in [ ONNX, SavedModel ]')
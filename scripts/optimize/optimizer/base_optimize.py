@@ -0,0 +1,82 @@
+import os
+import shutil
+
+import yaml
+
+OPTIMIZATIONS_ENV = 'OPTIMIZATIONS'
+TARGET_OPSET_ENV = 'TARGET_OPSET'
+
+# The labels of ormbfile.yaml which record the optimization of model.
+OPTIMIZATIONS_LABEL = 'kleveross.io/optimizations'
+ORIGINAL_SIZE_LABEL = 'kleveross.io/original-size'
+OPTIMIZED_SIZE_LABEL = 'kleveross.io/optimized-size'
+
+
+def dir_size(dir):
+    size = 0
+    for root, _, files in os.walk(dir):
+        for f in files:
+            size += os.path.getsize(os.path.join(root, f))
+    return size
+
+
+class BaseOptimizer(object):
+    # SUPPORTED_OPTIMIZATIONS is the optimizations in the order they are applied.
+    SUPPORTED_OPTIMIZATIONS = []
+
+    def __init__(self, input_dir, output_dir):
+        self.input_dir = input_dir
+        self.output_dir = output_dir
+        optimizations = [
+            o for o in os.environ.get(OPTIMIZATIONS_ENV, '').split(',') if o
+        ]
+        unsupported = set(optimizations) - set(self.SUPPORTED_OPTIMIZATIONS)
+        assert len(optimizations) != 0, 'expected optimizations'
+        assert len(unsupported) == 0, 'optimizations %s are not supported, expected %s' % (
+            sorted(unsupported), self.SUPPORTED_OPTIMIZATIONS)
+        self.optimizations = [
+            o for o in self.SUPPORTED_OPTIMIZATIONS if o in optimizations
+        ]
+        self.target_opset = int(os.environ.get(TARGET_OPSET_ENV, '0'))
+
+    def _find_with_extension(self, extension):
+        dir = os.path.join(self.input_dir, 'model')
+        filelist = list(
+            filter(lambda f: f.endswith(extension) and not f.startswith('.'),
+                   os.listdir(dir)))
+        assert (len(filelist) == 1), 'expected one %s file,but found %s' % (
+            extension, len(filelist))
+        return os.path.join(dir, filelist[0])
+
+    def _write_output_ormbfile(self, original_size, optimized_size):
+        # The signature and other metadata are kept, the optimizations do not change the inputs and outputs.
+        with open(os.path.join(self.input_dir, 'ormbfile.yaml'), 'r') as f:
+            data = yaml.safe_load(f)
+
+        labels = data.get('labels') or {}
+        labels[OPTIMIZATIONS_LABEL] = ','.join(self.optimizations)
+        labels[ORIGINAL_SIZE_LABEL] = str(original_size)
+        labels[OPTIMIZED_SIZE_LABEL] = str(optimized_size)
+        data['labels'] = labels
+
+        with open(os.path.join(self.output_dir, 'ormbfile.yaml'), 'w') as f:
+            yaml.safe_dump(data, f)
+
+    def _load_model(self):
+        raise NotImplementedError
+
+    def _optimize(self):
+        raise NotImplementedError
+
+    def optimize(self):
+        self._load_model()
+        self._optimize()
+
+        original_size = dir_size(os.path.join(self.input_dir, 'model'))
+        optimized_size = dir_size(os.path.join(self.output_dir, 'model'))
+        self._write_output_ormbfile(original_size, optimized_size)
+        return {
+            'optimizations': self.optimizations,
+            'originalSize': original_size,
+            'optimizedSize': optimized_size,
+        }
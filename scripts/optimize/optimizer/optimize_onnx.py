@@ -0,0 +1,41 @@
+import os
+
+import onnx
+from onnx import version_converter
+import onnxsim
+from onnxruntime.quantization import quantize_dynamic, QuantType
+
+from .base_optimize import BaseOptimizer
+
+EXTENSION = '.onnx'
+
+
+class OnnxOptimizer(BaseOptimizer):
+    # The quantization is the last, the quantized operators can not be simplified or upgraded.
+    SUPPORTED_OPTIMIZATIONS = ['UpgradeOpset', 'Simplify', 'DynamicQuantization']
+
+    def _load_model(self):
+        self.model_path = self._find_with_extension(EXTENSION)
+        self.model = onnx.load_model(self.model_path)
+
+    def _optimize(self):
+        if 'UpgradeOpset' in self.optimizations:
+            assert self.target_opset > 0, 'expected target opset'
+            self.model = version_converter.convert_version(
+                self.model, self.target_opset)
+
+        if 'Simplify' in self.optimizations:
+            self.model, check = onnxsim.simplify(self.model)
+            assert check, 'the simplified model can not be validated'
+
+        out_dir = os.path.join(self.output_dir, 'model')
+        os.makedirs(out_dir, exist_ok=True)
+        out_path = os.path.join(out_dir, os.path.basename(self.model_path))
+        if 'DynamicQuantization' not in self.optimizations:
+            onnx.save_model(self.model, out_path)
+            return
+
+        tmp_path = out_path + '.tmp'
+        onnx.save_model(self.model, tmp_path)
+        quantize_dynamic(tmp_path, out_path, weight_type=QuantType.QInt8)
+        os.remove(tmp_path)
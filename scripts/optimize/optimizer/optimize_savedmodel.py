@@ -0,0 +1,67 @@
+import os
+
+import tensorflow as tf
+import tensorflow.saved_model as saved_model
+from tensorflow.tools.graph_transforms import TransformGraph
+
+from .base_optimize import BaseOptimizer
+
+DEFAULT_SERVING_SIGNATURE_DEF_KEY = 'serving_default'
+
+# fold_constants needs the variables frozen to constants.
+TRANSFORMS = [
+    'fold_constants(ignore_errors=true)',
+    'fold_batch_norms',
+    'fold_old_batch_norms',
+    'strip_unused_nodes',
+    'sort_by_execution_order',
+]
+
+
+def tensor_op_name(tensor_info):
+    return tensor_info.name.split(':')[0]
+
+
+class TensorflowOptimizer(BaseOptimizer):
+    SUPPORTED_OPTIMIZATIONS = ['ConstantFolding']
+
+    def _load_model(self):
+        self.model_path = os.path.join(self.input_dir, 'model')
+        self.graph = tf.Graph()
+        self.sess = tf.Session(graph=self.graph)
+        meta_graph = saved_model.load(self.sess, [saved_model.SERVING],
+                                      self.model_path)
+
+        self.signature_key = DEFAULT_SERVING_SIGNATURE_DEF_KEY
+        if self.signature_key not in meta_graph.signature_def:
+            keys = [k for k in meta_graph.signature_def if not k.startswith('__')]
+            assert len(keys) != 0, 'unable to load model, expected ' + DEFAULT_SERVING_SIGNATURE_DEF_KEY + ' signature'
+            self.signature_key = keys[0]
+        self.signature = meta_graph.signature_def[self.signature_key]
+
+    def _optimize(self):
+        inputs = [tensor_op_name(t) for t in self.signature.inputs.values()]
+        outputs = [tensor_op_name(t) for t in self.signature.outputs.values()]
+        frozen = tf.graph_util.convert_variables_to_constants(
+            self.sess, self.graph.as_graph_def(), outputs)
+        optimized = TransformGraph(frozen, inputs, outputs, TRANSFORMS)
+        self.sess.close()
+
+        with tf.Graph().as_default() as graph:
+            tf.import_graph_def(optimized, name='')
+            with tf.Session(graph=graph) as sess:
+                signature = saved_model.predict_signature_def(
+                    inputs={
+                        k: graph.get_tensor_by_name(v.name)
+                        for k, v in self.signature.inputs.items()
+                    },
+                    outputs={
+                        k: graph.get_tensor_by_name(v.name)
+                        for k, v in self.signature.outputs.items()
+                    })
+                builder = saved_model.Builder(
+                    os.path.join(self.output_dir, 'model'))
+                builder.add_meta_graph_and_variables(
+                    sess, [saved_model.SERVING],
+                    signature_def_map={self.signature_key: signature})
+                builder.save()
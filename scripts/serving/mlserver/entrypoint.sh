@@ -1,5 +1,10 @@
 #!/bin/bash
 
+# The model is benchmarked in the serving runtime by the Benchmark ModelJob.
+if [ "$MODELJOB_TASK" == "benchmark" ]; then
+    exec /opt/benchmark/benchmark.sh "$0"
+fi
+
 python3 /opt/wrapper/preprocessor.py
 
 if [ "$MODEL_FORMAT" = "MLlib" ];then
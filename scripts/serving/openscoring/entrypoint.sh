@@ -1,5 +1,10 @@
 #!/bin/bash
 
+# The model is benchmarked in the serving runtime by the Benchmark ModelJob.
+if [ "$MODELJOB_TASK" == "benchmark" ]; then
+    exec /opt/benchmark/benchmark.sh "$0"
+fi
+
 python3 /opt/openscoring/wrapper/preprocessor.py
 
 if [ -f "${MODEL_STORE}/${SERVING_NAME}/1/model.pmml" ];then
@@ -27,6 +27,11 @@
 #
 # FROM https://github.com/NVIDIA/tensorrt-inference-server/blob/r20.08/nvidia_entrypoint.sh
 
+# The model is benchmarked in the serving runtime by the Benchmark ModelJob.
+if [ "$MODELJOB_TASK" == "benchmark" ]; then
+    exec /opt/benchmark/benchmark.sh "$0"
+fi
+
 set -e
 cat <<EOF
 ===============================
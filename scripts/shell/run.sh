@@ -6,6 +6,7 @@ ormb_export_model_err=10002
 ormb_run_task_err=10003
 ormb_save_model_err=10004
 ormb_push_model_err=10005
+validation_failed_err=10006
 
 src_tag=$SOURCE_MODEL_TAG
 dst_tag=$DESTINATION_MODEL_TAG
@@ -13,6 +14,25 @@ input_dir=$SOURCE_MODEL_PATH
 output_dir=$DESTINATION_MODEL_PATH
 source_format=$SOURCE_FORMAT
 format=$FORMAT
+result_path=${MODELJOB_RESULT_PATH:-/dev/termination-log}
+# the stage is read by the readiness probe of executor, modeljob-operator reports the push stage when it is ready.
+stage_path=${MODELJOB_STAGE_PATH:-/tmp/modeljob-stage}
+task_result_path=/tmp/modeljob-task-result.json
+task_log_path=/tmp/modeljob-task.log
+push_log_path=/tmp/modeljob-push.log
+annotate_log_path=/tmp/modeljob-annotate.log
+
+# the task is set by modeljob-operator, the executors of old operators infer it from the destination tag.
+task=$MODELJOB_TASK
+if [ -z "$task" ]
+then
+    if [ $dst_tag == "empty" ]
+    then
+        task=extract
+    else
+        task=convert
+    fi
+fi
 
 echo "#####################################################"
 echo "model source tag: $src_tag"
@@ -20,26 +40,72 @@ echo "model destination tag: $dst_tag"
 echo "model input dir: $input_dir"
 echo "model output dir: $output_dir"
 echo "model format: $format"
+echo "task: $task"
 echo "ORMB domain: $SERVER_ORMB_DOMAIN"
-echo "ORMB username: $SERVER_ORMB_USERNAME"
 echo "#####################################################"
 
 
+# writeResult writes the json of result to the termination message, the reconciler of modeljob parses it
+# into status.result. The signature is dropped if the result exceeds the 4096 bytes limit of termination message.
+function writeResult() {
+    RESULT_ERROR="$1" RESULT_DESTINATION="$dst_tag" RESULT_DIGEST="$digest" RESULT_SIZE="$size" \
+    RESULT_TASK_PATH="$task_result_path" RESULT_TASK_SECONDS="$task_seconds" RESULT_PUSH_SECONDS="$push_seconds" \
+    python3 - > $result_path <<'EOF'
+import json
+import os
+
+result = {}
+if os.path.exists(os.environ['RESULT_TASK_PATH']):
+    with open(os.environ['RESULT_TASK_PATH']) as f:
+        result.update(json.load(f))
+if os.environ['RESULT_ERROR']:
+    result['error'] = os.environ['RESULT_ERROR']
+if os.environ['RESULT_DESTINATION'] and os.environ['RESULT_DIGEST']:
+    result['destinationRef'] = os.environ['RESULT_DESTINATION']
+    result['digest'] = os.environ['RESULT_DIGEST']
+if os.environ['RESULT_SIZE']:
+    result['size'] = int(os.environ['RESULT_SIZE'])
+if os.environ['RESULT_TASK_SECONDS']:
+    result['taskDuration'] = os.environ['RESULT_TASK_SECONDS'] + 's'
+if os.environ['RESULT_PUSH_SECONDS']:
+    result['pushDuration'] = os.environ['RESULT_PUSH_SECONDS'] + 's'
+if 'error' in result:
+    result['error'] = result['error'][-1024:]
+
+output = json.dumps(result)
+if len(output) > 4096 and 'signature' in result:
+    del result['signature']
+    result['truncated'] = True
+    output = json.dumps(result)
+print(output)
+EOF
+}
+
 function checkOrExit() {
     if [ $1 != 0 ];then
         echo "exit code: $2"
+        writeResult "$3"
         exit $2
     fi
 }
 
+# lastLines returns the last lines of the log as the detailed error.
+function lastLines() {
+    if [ -f $1 ];then
+        tail -n 5 $1
+    fi
+}
+
 mkdir -p $input_dir/model
 mkdir -p $output_dir/model
 
-# login to harbor.
-ormb login  --insecure $SERVER_ORMB_DOMAIN -u $SERVER_ORMB_USERNAME -p $SERVER_ORMB_PASSWORD
-checkOrExit $? $ormb_login_err
+# login to harbor, the password is read from stdin so that it is not in the arguments of process.
+printf '%s' "$SERVER_ORMB_PASSWORD" | ormb login --insecure "$SERVER_ORMB_DOMAIN" -u "$SERVER_ORMB_USERNAME" --password-stdin
+checkOrExit $? $ormb_login_err "failed to login $SERVER_ORMB_DOMAIN"
 
-if [ $dst_tag == "empty" ]
+echo task > $stage_path
+task_start=$(date +%s)
+if [ $task == "extract" ]
 then
 
     case $format in
@@ -50,14 +116,42 @@ then
     esac
 
     # execute python script to extract
-    python3 /scripts/extract.py -d $input_dir
-    checkOrExit $? $ormb_run_task_err
+    python3 /scripts/extract.py -d $input_dir -r $task_result_path 2>&1 | tee $task_log_path
+    checkOrExit ${PIPESTATUS[0]} $ormb_run_task_err "$(lastLines $task_log_path)"
+
+elif [ $task == "validate" ]
+then
+    # execute python script to run the model with sample inputs
+    python3 /scripts/validate.py -d $input_dir -r $task_result_path 2>&1 | tee $task_log_path
+    checkOrExit ${PIPESTATUS[0]} $ormb_run_task_err "$(lastLines $task_log_path)"
+
+elif [ $task == "optimize" ]
+then
+    # execute python script to optimize the model in the same format
+    python3 /scripts/optimize.py --input_dir=$input_dir --output_dir=$output_dir -r $task_result_path 2>&1 | tee $task_log_path
+    checkOrExit ${PIPESTATUS[0]} $ormb_run_task_err "$(lastLines $task_log_path)"
 
 else
-    python3 /scripts/convert.py --input_dir=$input_dir --output_dir=$output_dir
-    checkOrExit $? $ormb_run_task_err
+    python3 /scripts/convert.py --input_dir=$input_dir --output_dir=$output_dir 2>&1 | tee $task_log_path
+    checkOrExit ${PIPESTATUS[0]} $ormb_run_task_err "$(lastLines $task_log_path)"
     
 fi
+task_seconds=$(( $(date +%s) - task_start ))
+
+# the validation does not push model, its result is recorded in the annotation of artifact.
+if [ $task == "validate" ]
+then
+    validation=$(python3 -c "import json; print('passed' if json.load(open('$task_result_path'))['validation']['passed'] else 'failed')")
+    checkOrExit $? $ormb_run_task_err "no validation result"
+
+    python3 /scripts/annotate.py --ref $src_tag --key kleveross.io/validation --value $validation -r $task_result_path 2>&1 | tee $annotate_log_path
+    checkOrExit ${PIPESTATUS[0]} $ormb_push_model_err "$(lastLines $annotate_log_path)"
+
+    [ $validation == "passed" ]
+    checkOrExit $? $validation_failed_err "$(python3 -c "import json; print(json.load(open('$task_result_path'))['validation'].get('message', ''))")"
+    writeResult ""
+    exit 0
+fi
 
 
 if [ $dst_tag == "empty" ]
@@ -67,9 +161,16 @@ then
 fi
 
 # save model 
+echo push > $stage_path
+push_start=$(date +%s)
 ormb save $output_dir $dst_tag
-checkOrExit $? $ormb_save_model_err
+checkOrExit $? $ormb_save_model_err "failed to save model $dst_tag"
+size=$(du -sb $output_dir | cut -f1)
 
 # push model to registry
-ormb push $dst_tag --plain-http
-checkOrExit $? $ormb_push_model_err
+ormb push $dst_tag --plain-http 2>&1 | tee $push_log_path
+checkOrExit ${PIPESTATUS[0]} $ormb_push_model_err "$(lastLines $push_log_path)"
+digest=$(grep -o 'sha256:[0-9a-f]\{64\}' $push_log_path | tail -n 1)
+push_seconds=$(( $(date +%s) - push_start ))
+
+writeResult ""
@@ -24,6 +24,9 @@ func CreateFailedPodForJob(c client.Client, job *batchv1.Job) error {
 			Labels: map[string]string{
 				"job-name": job.Name,
 			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job")),
+			},
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{